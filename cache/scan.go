@@ -0,0 +1,89 @@
+package cache
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/redis/go-redis/v9"
+)
+
+// scanBatchSize is the SCAN COUNT hint; Redis returns roughly this many keys per call.
+const scanBatchSize = 500
+
+// ScanKeys walks every key matching pattern with SCAN and calls fn once per batch.
+// Unlike KEYS, this never blocks the server for the whole keyspace.
+func ScanKeys(ctx context.Context, client *redis.Client, pattern string, fn func(keys []string) error) error {
+	if pattern == "" {
+		pattern = "*"
+	}
+	var cursor uint64
+	for {
+		keys, next, err := client.Scan(ctx, cursor, pattern, scanBatchSize).Result()
+		if err != nil {
+			return fmt.Errorf("failed to scan keys: %w", err)
+		}
+		if len(keys) > 0 {
+			if err := fn(keys); err != nil {
+				return err
+			}
+		}
+		cursor = next
+		if cursor == 0 {
+			return nil
+		}
+	}
+}
+
+// globEscaper escapes the characters Redis treats as special in a MATCH pattern.
+var globEscaper = strings.NewReplacer(`\`, `\\`, `*`, `\*`, `?`, `\?`, `[`, `\[`, `]`, `\]`)
+
+// PrefixPattern returns a SCAN pattern that matches every key starting with prefix, taking
+// any glob characters in the prefix literally.
+func PrefixPattern(prefix string) string {
+	return globEscaper.Replace(prefix) + "*"
+}
+
+// KeyInfo describes a single key without reading its value.
+type KeyInfo struct {
+	Key  string
+	Type string
+	// Size is the MEMORY USAGE estimate in bytes, or 0 if unavailable
+	Size int64
+	// TTL is -1 for keys without an expiry
+	TTL int64
+}
+
+// DescribeKeys fetches the type, memory usage and TTL (in milliseconds) of keys in one pipeline.
+// Keys that expire before the pipeline runs are omitted.
+func DescribeKeys(ctx context.Context, client *redis.Client, keys []string) ([]KeyInfo, error) {
+	pipe := client.Pipeline()
+	types := make([]*redis.StatusCmd, len(keys))
+	sizes := make([]*redis.IntCmd, len(keys))
+	ttls := make([]*redis.DurationCmd, len(keys))
+	for i, key := range keys {
+		types[i] = pipe.Type(ctx, key)
+		sizes[i] = pipe.MemoryUsage(ctx, key)
+		ttls[i] = pipe.PTTL(ctx, key)
+	}
+	// Individual command errors (e.g., expired keys) are inspected per key below
+	if _, err := pipe.Exec(ctx); err != nil && err != redis.Nil {
+		if _, ok := err.(redis.Error); !ok {
+			return nil, fmt.Errorf("failed to describe keys: %w", err)
+		}
+	}
+
+	infos := make([]KeyInfo, 0, len(keys))
+	for i, key := range keys {
+		keyType := types[i].Val()
+		if keyType == "" || keyType == "none" {
+			continue
+		}
+		info := KeyInfo{Key: key, Type: keyType, Size: sizes[i].Val(), TTL: -1}
+		if ttl := ttls[i].Val(); ttl > 0 {
+			info.TTL = ttl.Milliseconds()
+		}
+		infos = append(infos, info)
+	}
+	return infos, nil
+}
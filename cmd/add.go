@@ -16,11 +16,14 @@ import (
 func Add(args []string) error {
 	// Parse flags
 	cloneSource := config.IsDevMode()
+	reassign := false
 	var serviceNames []string
 
 	for _, arg := range args {
 		if arg == "--source" || arg == "-s" {
 			cloneSource = true
+		} else if arg == "--reassign" {
+			reassign = true
 		} else {
 			serviceNames = append(serviceNames, arg)
 		}
@@ -59,15 +62,15 @@ func Add(args []string) error {
 
 	// If service names provided, add services by name
 	if len(serviceNames) > 0 {
-		return addServicesByName(serviceNames, availableServices, serviceMap, cloneSource)
+		return addServicesByName(serviceNames, availableServices, serviceMap, cloneSource, reassign)
 	}
 
 	// Otherwise, show interactive menu
-	return addServicesInteractive(availableServices, serviceMap, cloneSource)
+	return addServicesInteractive(availableServices, serviceMap, cloneSource, reassign)
 }
 
 // addServicesByName adds services specified by their short names
-func addServicesByName(names []string, availableServices []config.ServiceDefinition, serviceMap *config.ServiceMapConfig, cloneSource bool) error {
+func addServicesByName(names []string, availableServices []config.ServiceDefinition, serviceMap *config.ServiceMapConfig, cloneSource, reassign bool) error {
 	// Build a map of short names to services for quick lookup
 	servicesByName := make(map[string]config.ServiceDefinition)
 	for _, service := range availableServices {
@@ -132,7 +135,7 @@ func addServicesByName(names []string, availableServices []config.ServiceDefinit
 		}
 
 		// Add to service map
-		serviceMap.Services[service.Type] = append(serviceMap.Services[service.Type], service.ToServiceEntry())
+		serviceMap.Services[service.Type] = append(serviceMap.Services[service.Type], addServiceEntry(service, serviceMap, reassign))
 	}
 
 	if err := config.SaveServiceMapConfig(serviceMap); err != nil {
@@ -144,7 +147,7 @@ func addServicesByName(names []string, availableServices []config.ServiceDefinit
 }
 
 // addServicesInteractive shows an interactive menu to select services
-func addServicesInteractive(availableServices []config.ServiceDefinition, serviceMap *config.ServiceMapConfig, cloneSource bool) error {
+func addServicesInteractive(availableServices []config.ServiceDefinition, serviceMap *config.ServiceMapConfig, cloneSource, reassign bool) error {
 	reader := bufio.NewReader(os.Stdin)
 	for {
 		ui.PrintInfo("Available services to add:")
@@ -182,7 +185,7 @@ func addServicesInteractive(availableServices []config.ServiceDefinition, servic
 			}
 
 			// Add to service map
-			serviceMap.Services[service.Type] = append(serviceMap.Services[service.Type], service.ToServiceEntry())
+			serviceMap.Services[service.Type] = append(serviceMap.Services[service.Type], addServiceEntry(service, serviceMap, reassign))
 		}
 
 		if err := config.SaveServiceMapConfig(serviceMap); err != nil {
@@ -196,6 +199,43 @@ func addServicesInteractive(availableServices []config.ServiceDefinition, servic
 	return nil
 }
 
+// addServiceEntry builds the service-map.json entry for a service being added, after checking
+// that its port is neither assigned to another service nor held by another process. With
+// reassign, a taken port is replaced by a free one; otherwise the conflict is only reported.
+func addServiceEntry(service config.ServiceDefinition, serviceMap *config.ServiceMapConfig, reassign bool) config.ServiceEntry {
+	entry := service.ToServiceEntry()
+	if entry.Port == "" {
+		return entry
+	}
+
+	assigned := utils.AssignedPorts(serviceMap, service.ID)
+	conflict, hint := "", ""
+	if owner, taken := assigned[entry.Port]; taken {
+		// Only add can move it: 'dex start --reassign' looks at running processes, not the map
+		conflict = fmt.Sprintf("is already assigned to %s in service-map.json", owner)
+		hint = fmt.Sprintf("Run 'dex remove %s' and 'dex add %s --reassign' to pick a free port, or change the port of %s in service-map.json.", service.ShortName, service.ShortName, owner)
+	} else if holder, _ := utils.ServicePortConflict(service); holder != nil {
+		conflict = fmt.Sprintf("is held by %s", holder)
+		hint = fmt.Sprintf("Use 'dex start %s --reassign' to move it to a free port.", service.ShortName)
+	}
+	if conflict == "" {
+		return entry
+	}
+
+	if !reassign {
+		ui.PrintWarning(fmt.Sprintf("Port %s for %s %s. %s", entry.Port, service.ShortName, conflict, hint))
+		return entry
+	}
+	port, err := utils.FindFreePort(utils.ServiceBindHost(service), entry.Port, assigned)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Port %s for %s %s, and no free port was found: %v", entry.Port, service.ShortName, conflict, err))
+		return entry
+	}
+	ui.PrintWarning(fmt.Sprintf("Port %s for %s %s; using port %s instead.", entry.Port, service.ShortName, conflict, port))
+	entry.Port = port
+	return entry
+}
+
 // cloneServiceSource clones the git repository for a service
 func cloneServiceSource(service config.ServiceDefinition) error {
 	// Expand the source path
@@ -0,0 +1,36 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/ui"
+)
+
+func TestAddServiceEntryMapOnlyPortConflict(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	service := config.ServiceDefinition{ID: "dex-fake-service", ShortName: "fake", Type: "be", Domain: "127.0.0.1", Port: "47611"}
+	serviceMap := &config.ServiceMapConfig{Services: map[string][]config.ServiceEntry{
+		"be": {{ID: "dex-other-service", ShortName: "other", Domain: "127.0.0.1", Port: "47611"}},
+	}}
+
+	ui.StartCapturing()
+	entry := addServiceEntry(service, serviceMap, false)
+	ui.StopCapturing()
+	output := ui.GetCapturedOutput()
+	if entry.Port != "47611" {
+		t.Errorf("port = %s without --reassign, want it left at 47611", entry.Port)
+	}
+	if !strings.Contains(output, "assigned to other in service-map.json") || !strings.Contains(output, "dex add fake --reassign") {
+		t.Errorf("warning = %q, want it to name other and point at 'dex add fake --reassign'", output)
+	}
+	if strings.Contains(output, "dex start") {
+		t.Errorf("warning = %q points at 'dex start --reassign', which cannot fix a conflict in the map", output)
+	}
+
+	entry = addServiceEntry(service, serviceMap, true)
+	if entry.Port == "47611" || entry.Port == "" {
+		t.Errorf("port = %q with --reassign, want a free port other than 47611", entry.Port)
+	}
+}
@@ -0,0 +1,246 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// Backup writes a tar.zst archive of service artifacts, config files and persistent Redis keys.
+func Backup(args []string) error {
+	target := "all"
+	if len(args) > 0 {
+		target = args[0]
+	}
+
+	switch target {
+	case "--help", "-h":
+		ui.PrintHeader("Backup Command Help")
+		ui.PrintInfo("Usage: dex backup [service|all|list]")
+		fmt.Println()
+		ui.PrintInfo("Description:")
+		ui.PrintInfo("  Writes a timestamped tar.zst archive to " + utils.BackupDir + " containing the")
+		ui.PrintInfo("  ~/Dexter/config files, each service's declared backup artifacts and the")
+		ui.PrintInfo("  persistent Redis keys. A manifest with checksums is stored in every archive.")
+		fmt.Println()
+		ui.PrintInfo("Subcommands:")
+		ui.PrintInfo("  list   Show existing archives and verify their checksums.")
+		fmt.Println()
+		ui.PrintInfo("Restore an archive with 'dex restore <archive>'.")
+		return nil
+	case "list":
+		return listBackups()
+	}
+
+	configuredServices, err := utils.GetConfiguredServices()
+	if err != nil {
+		return fmt.Errorf("failed to get configured services: %w", err)
+	}
+
+	var services []config.ServiceDefinition
+	if target == "all" {
+		services = configuredServices
+	} else {
+		service, found := config.FindService(configuredServices, target)
+		if !found {
+			return fmt.Errorf("service '%s' not found in service-map.json", target)
+		}
+		if !utils.HasArtifacts(service) {
+			ui.PrintWarning(fmt.Sprintf("%s declares no backup artifacts; only config and Redis will be saved.", service.ShortName))
+		}
+		services = append(services, service)
+	}
+
+	archivePath, manifest, err := utils.CreateBackup(context.Background(), services, utils.GetCLIVersion(), func(message string) {
+		ui.PrintInfo(message)
+	})
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, f := range manifest.Files {
+		total += f.Size
+	}
+	ui.PrintSuccess(fmt.Sprintf("Backup written to %s", archivePath))
+	ui.PrintKeyValBlock("backup", []ui.KeyVal{
+		{Key: "Services", Value: formatBackupServices(manifest.Services)},
+		{Key: "Files", Value: fmt.Sprintf("%d (%s)", len(manifest.Files), utils.FormatBytes(total))},
+		{Key: "Redis keys", Value: fmt.Sprintf("%d", manifest.RedisKeys)},
+	})
+	return nil
+}
+
+// listBackups prints every archive in the backup directory along with its verification status.
+func listBackups() error {
+	archives, err := utils.ListBackups()
+	if err != nil {
+		return err
+	}
+	if len(archives) == 0 {
+		ui.PrintInfo(fmt.Sprintf("No backups found in %s.", utils.BackupDir))
+		return nil
+	}
+
+	table := ui.NewTable([]string{"ARCHIVE", "CREATED", "SERVICES", "REDIS KEYS", "SIZE", "STATUS"})
+	for _, archive := range archives {
+		name := filepath.Base(archive)
+		manifest, err := utils.VerifyBackup(archive)
+		if manifest == nil {
+			table.AddRow(ui.TableRow{name, "-", "-", "-", utils.FormatBytes(fileSize(archive)), ui.Colorize("unreadable", ui.ColorRed)})
+			continue
+		}
+		status := ui.Colorize("ok", ui.ColorGreen)
+		if err != nil {
+			status = ui.Colorize("corrupt", ui.ColorRed)
+		}
+		table.AddRow(ui.TableRow{
+			name,
+			manifest.CreatedAt.Local().Format("2006-01-02 15:04:05"),
+			formatBackupServices(manifest.Services),
+			fmt.Sprintf("%d", manifest.RedisKeys),
+			utils.FormatBytes(fileSize(archive)),
+			status,
+		})
+	}
+	table.Render()
+	return nil
+}
+
+// Restore verifies a backup archive, stops the services it covers, puts every file and
+// Redis key back in place and restarts the services that were running.
+func Restore(args []string) error {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		ui.PrintHeader("Restore Command Help")
+		ui.PrintInfo("Usage: dex restore <archive>")
+		fmt.Println()
+		ui.PrintInfo("Description:")
+		ui.PrintInfo("  Verifies an archive written by 'dex backup', stops the services it covers,")
+		ui.PrintInfo("  restores the config files, artifacts and persistent Redis keys, then starts")
+		ui.PrintInfo("  the services again. The archive may be a path or a name from 'dex backup list'.")
+		return nil
+	}
+
+	archivePath, err := resolveBackupPath(args[0])
+	if err != nil {
+		return err
+	}
+
+	// 1. Extract and verify before touching anything live
+	ui.PrintInfo(fmt.Sprintf("Verifying %s...", filepath.Base(archivePath)))
+	extracted, err := os.MkdirTemp("", "dex-restore-")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.RemoveAll(extracted) }()
+
+	manifest, err := utils.ExtractBackup(archivePath, extracted)
+	if err != nil {
+		return err
+	}
+	ui.PrintSuccess(fmt.Sprintf("Verified %d files and %d Redis keys from %s", len(manifest.Files), manifest.RedisKeys, manifest.CreatedAt.Local().Format("2006-01-02 15:04:05")))
+
+	// 2. Stop the affected services
+	configuredServices, err := utils.GetConfiguredServices()
+	if err != nil {
+		return fmt.Errorf("failed to get configured services: %w", err)
+	}
+	var affected, running []config.ServiceDefinition
+	for _, id := range manifest.Services {
+		service, found := config.FindService(configuredServices, id)
+		if !found || !service.IsManageable() || service.SystemdName == "" {
+			continue
+		}
+		affected = append(affected, service)
+		if utils.IsServiceActive(service) {
+			running = append(running, service)
+		}
+	}
+
+	var restoreErr error
+	if len(affected) > 0 {
+		levels, err := config.OrderByDependencies(affected)
+		if err != nil {
+			return err
+		}
+		if failures := stopServicesInOrder(levels); len(failures) > 0 {
+			for _, err := range failures {
+				ui.PrintError(err.Error())
+			}
+			restoreErr = fmt.Errorf("failed to stop services before restoring")
+		}
+	}
+
+	// 3. Restore files and Redis keys. Whatever was running is started again even when this
+	// fails, so a failed restore does not leave services down.
+	if restoreErr == nil {
+		restoreErr = restoreBackupData(extracted, manifest)
+		config.ReloadServiceMap()
+	}
+
+	// 4. Bring back whatever was running
+	if len(running) > 0 {
+		levels, err := config.OrderByDependencies(running)
+		if err != nil {
+			return errors.Join(restoreErr, err)
+		}
+		if failures := startServicesInOrder(context.Background(), levels, configuredServices, false); len(failures) > 0 {
+			for _, err := range failures {
+				ui.PrintError(err.Error())
+			}
+			if restoreErr != nil {
+				return fmt.Errorf("%w; one or more services also failed to start again", restoreErr)
+			}
+			return fmt.Errorf("backup was restored but one or more services failed to start")
+		}
+		ui.PrintSuccess(fmt.Sprintf("Restarted %d service(s).", len(running)))
+	}
+
+	return restoreErr
+}
+
+// restoreBackupData puts an extracted backup's files and Redis keys back in place.
+func restoreBackupData(extracted string, manifest *utils.BackupManifest) error {
+	ui.PrintInfo("Restoring files...")
+	if err := utils.InstallBackupFiles(extracted, manifest); err != nil {
+		return err
+	}
+	ui.PrintInfo("Restoring persistent Redis keys...")
+	count, err := utils.RestoreRedisDump(context.Background(), utils.BackupRedisDumpPath(extracted))
+	if err != nil {
+		return err
+	}
+	ui.PrintSuccess(fmt.Sprintf("Restored %d files and %d Redis keys", len(manifest.Files), count))
+	return nil
+}
+
+// resolveBackupPath accepts either a path to an archive or the name of one in the backup directory.
+func resolveBackupPath(arg string) (string, error) {
+	if _, err := os.Stat(arg); err == nil {
+		return arg, nil
+	}
+	backupDir, err := config.ExpandPath(utils.BackupDir)
+	if err != nil {
+		return "", err
+	}
+	candidate := filepath.Join(backupDir, filepath.Base(arg))
+	if _, err := os.Stat(candidate); err == nil {
+		return candidate, nil
+	}
+	return "", fmt.Errorf("backup '%s' not found (see 'dex backup list')", arg)
+}
+
+// formatBackupServices joins the service IDs recorded in a manifest for display.
+func formatBackupServices(services []string) string {
+	if len(services) == 0 {
+		return "-"
+	}
+	return strings.Join(services, ", ")
+}
@@ -4,13 +4,16 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"runtime"
+	"slices"
 	"strconv"
 	"strings"
+	"sync"
 	"syscall"
 	"time"
 
@@ -199,7 +202,7 @@ func verifyDeveloperAccess() error {
 }
 
 // buildFrontendService executes the build.sh script for a frontend service like easter.company
-func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log func(message string), major, minor, patch int) (bool, error) {
+func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log func(message string), out io.Writer, major, minor, patch int) (bool, error) {
 	sourcePath, err := config.ExpandPath(def.Source)
 	if err != nil {
 		return false, fmt.Errorf("failed to expand source path for %s: %w", def.ShortName, err)
@@ -209,7 +212,7 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 
 	// 0. Install Dependencies (Bun)
 	if _, err := os.Stat(filepath.Join(sourcePath, "package.json")); err == nil {
-		log(fmt.Sprintf("[%s] Installing dependencies with Bun...", def.ShortName))
+		log("Installing dependencies with Bun...")
 		installCmd := exec.CommandContext(ctx, "bun", "install")
 		installCmd.Dir = sourcePath
 		if out, err := installCmd.CombinedOutput(); err != nil {
@@ -219,7 +222,7 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 
 	// 0.2. Type Check (TypeScript)
 	if _, err := os.Stat(filepath.Join(sourcePath, "tsconfig.json")); err == nil {
-		log(fmt.Sprintf("[%s] Checking types with TypeScript...", def.ShortName))
+		log("Checking types with TypeScript...")
 		tscCmd := exec.CommandContext(ctx, "bun", "run", "tsc", "--noEmit")
 		tscCmd.Dir = sourcePath
 		if out, err := tscCmd.CombinedOutput(); err != nil {
@@ -229,12 +232,12 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 
 	// 0. Format Code (Prettier)
 	if _, err := exec.LookPath("prettier"); err == nil {
-		log(fmt.Sprintf("[%s] Formatting source code with Prettier...", def.ShortName))
+		log("Formatting source code with Prettier...")
 		// We format the source directory (where JS/CSS/HTML lives)
 		fmtCmd := exec.CommandContext(ctx, "prettier", "--write", "source")
 		fmtCmd.Dir = sourcePath
 		if out, err := fmtCmd.CombinedOutput(); err != nil {
-			log(fmt.Sprintf("[%s] Warning: Prettier failed: %v\n%s", def.ShortName, err, string(out)))
+			log(fmt.Sprintf("Warning: Prettier failed: %v\n%s", err, string(out)))
 			// We warn but proceed, or should we fail?
 			// The user requested strict tooling. Failing on format error (if it's a syntax error that prettier can't parse) is good.
 			// If it's just "I formatted it", it returns 0.
@@ -243,11 +246,11 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 			return false, fmt.Errorf("prettier formatting failed (syntax error?): %w\n%s", err, string(out))
 		}
 	} else {
-		log(fmt.Sprintf("[%s] Warning: 'prettier' not found, skipping formatting.", def.ShortName))
+		log("Warning: 'prettier' not found, skipping formatting.")
 	}
 
 	// 0.5. Lint Code
-	log(fmt.Sprintf("[%s] Linting source code...", def.ShortName))
+	log("Linting source code...")
 	lintFailed := false
 
 	// ESLint
@@ -256,7 +259,7 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 		lintCmd := exec.CommandContext(ctx, "eslint", ".")
 		lintCmd.Dir = sourcePath
 		if out, err := lintCmd.CombinedOutput(); err != nil {
-			log(fmt.Sprintf("[%s] ESLint failed: %v\n%s", def.ShortName, err, string(out)))
+			log(fmt.Sprintf("ESLint failed: %v\n%s", err, string(out)))
 			lintFailed = true
 		}
 	}
@@ -266,7 +269,7 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 		lintCmd := exec.CommandContext(ctx, "stylelint", "source/**/*.css")
 		lintCmd.Dir = sourcePath
 		if out, err := lintCmd.CombinedOutput(); err != nil {
-			log(fmt.Sprintf("[%s] Stylelint failed: %v\n%s", def.ShortName, err, string(out)))
+			log(fmt.Sprintf("Stylelint failed: %v\n%s", err, string(out)))
 			lintFailed = true
 		}
 	}
@@ -276,7 +279,7 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 		lintCmd := exec.CommandContext(ctx, "htmlhint", "source/**/*.html")
 		lintCmd.Dir = sourcePath
 		if out, err := lintCmd.CombinedOutput(); err != nil {
-			log(fmt.Sprintf("[%s] HTMLHint failed: %v\n%s", def.ShortName, err, string(out)))
+			log(fmt.Sprintf("HTMLHint failed: %v\n%s", err, string(out)))
 			lintFailed = true
 		}
 	}
@@ -288,14 +291,14 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 	// 0.8. Run Tests (Vitest)
 	vitestConfig := filepath.Join(sourcePath, "vitest.config.js")
 	if _, err := os.Stat(vitestConfig); err == nil {
-		log(fmt.Sprintf("[%s] Running tests with Vitest...", def.ShortName))
+		log("Running tests with Vitest...")
 		testCmd := exec.CommandContext(ctx, "bun", "run", "vitest", "run")
 		testCmd.Dir = sourcePath
 		if out, err := testCmd.CombinedOutput(); err != nil {
-			log(fmt.Sprintf("[%s] Tests failed: %v\n%s", def.ShortName, err, string(out)))
+			log(fmt.Sprintf("Tests failed: %v\n%s", err, string(out)))
 			return false, fmt.Errorf("tests failed")
 		}
-		log(fmt.Sprintf("[%s] Tests passed!", def.ShortName))
+		log("Tests passed!")
 	}
 
 	// Construct full version string for frontend
@@ -305,12 +308,12 @@ func buildFrontendService(ctx context.Context, def config.ServiceDefinition, log
 	shortVersionStr := fmt.Sprintf("%d.%d.%d", major, minor, patch)
 	fullVersionStr := fmt.Sprintf("%s.%s.%s.%s.%s", shortVersionStr, branch, commit, buildDate, arch)
 
-	log(fmt.Sprintf("[%s] Running frontend build script: %s (Version: %s)", def.ShortName, buildScriptPath, fullVersionStr))
+	log(fmt.Sprintf("Running frontend build script: %s (Version: %s)", buildScriptPath, fullVersionStr))
 
 	cmd := exec.CommandContext(ctx, "bash", buildScriptPath)
 	cmd.Dir = sourcePath // Execute the script from the service's source directory
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = out
+	cmd.Stderr = out
 	cmd.Env = append(os.Environ(), fmt.Sprintf("DEX_BUILD_VERSION=%s", fullVersionStr))
 
 	if err := cmd.Run(); err != nil {
@@ -362,6 +365,11 @@ func verifyGitHubAccess() error {
 }
 
 func Build(args []string) error {
+	// A plan changes nothing, so it is not announced to the event service as a build
+	if slices.Contains(args, "--plan") && !slices.Contains(args, "--source") {
+		return runBuild(context.Background(), args)
+	}
+
 	startTime := time.Now()
 
 	// Setup signal handling for graceful cleanup on Ctrl+C
@@ -468,7 +476,7 @@ func runBuild(ctx context.Context, args []string) error {
 	for _, arg := range args {
 		if arg == "--help" || arg == "-h" {
 			ui.PrintHeader("Build Command Help")
-			ui.PrintInfo("Usage: dex build [major|minor|patch] [-f|--force]")
+			ui.PrintInfo("Usage: dex build [major|minor|patch] [-f|--force] [-j N] [--plan [--json]]")
 			fmt.Println()
 			ui.PrintInfo("Arguments:")
 			ui.PrintInfo("  major, minor, patch   Increment the version number accordingly.")
@@ -476,14 +484,71 @@ func runBuild(ctx context.Context, args []string) error {
 			fmt.Println()
 			ui.PrintInfo("Flags:")
 			ui.PrintInfo("  -f, --force           Force rebuild of all services even if no changes are detected.")
+			ui.PrintInfo("  -j, --jobs N          Build up to N services at once (default: build.jobs in options.json,")
+			ui.PrintInfo("                        else the number of CPUs up to 4). -j 1 builds one at a time.")
+			ui.PrintInfo("  --summarize           Summarise minor/major changelogs with the local dex-summary-model.")
+			ui.PrintInfo("  --plan                Show the services, versions, Redis keys, commits and publishing")
+			ui.PrintInfo("                        the build would involve, then stop without changing anything.")
+			ui.PrintInfo("  --json                With --plan, print the plan as JSON.")
 			fmt.Println()
 			ui.PrintInfo("Description:")
 			ui.PrintInfo("  Builds and installs CLI and services from source.")
 			ui.PrintInfo("  This command requires developer access to the source code.")
+			ui.PrintInfo("  Minor and major releases publish a changelog per service, grouped by")
+			ui.PrintInfo("  conventional-commit type, to data.json and the website's release notes.")
+			ui.PrintInfo("  Services build in parallel unless one lists another in 'build_after' in")
+			ui.PrintInfo("  service-map.json. Each service's output is prefixed with its name and")
+			ui.PrintInfo("  saved to ~/Dexter/logs/build/<service>.log.")
 			return nil
 		}
 	}
 
+	// Check for --force, --summarize, --plan, --json and -j flags
+	forceRebuild := false
+	summarizeChangelog := false
+	planOnly := false
+	planJSON := false
+	jobs := 0
+	var filteredArgs []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--force" || arg == "-f":
+			forceRebuild = true
+		case arg == "--summarize":
+			summarizeChangelog = true
+		case arg == "--plan":
+			planOnly = true
+		case arg == "--json":
+			planJSON = true
+		case arg == "-j" || arg == "--jobs":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a number of jobs", arg)
+			}
+			i++
+			n, err := parseBuildJobs(args[i])
+			if err != nil {
+				return err
+			}
+			jobs = n
+		case strings.HasPrefix(arg, "--jobs=") || (strings.HasPrefix(arg, "-j") && len(arg) > 2):
+			n, err := parseBuildJobs(strings.TrimPrefix(strings.TrimPrefix(arg, "--jobs="), "-j"))
+			if err != nil {
+				return err
+			}
+			jobs = n
+		default:
+			filteredArgs = append(filteredArgs, arg)
+		}
+	}
+	args = filteredArgs
+	if planJSON && !planOnly {
+		return fmt.Errorf("--json is only supported with --plan")
+	}
+	if jobs == 0 {
+		jobs = defaultBuildJobs()
+	}
+
 	// Verify this is a developer environment
 
 	if err := verifyDeveloperAccess(); err != nil {
@@ -492,12 +557,40 @@ func runBuild(ctx context.Context, args []string) error {
 
 	}
 
-	// Verify GitHub access (one-time check)
+	// Verify GitHub access (one-time check); a plan never pushes, so it does not need it
+
+	if !planOnly {
+		if err := verifyGitHubAccess(); err != nil {
+			return err
+		}
+	}
+
+	// Validate arguments
+	if len(args) > 1 {
+		return fmt.Errorf("build command accepts at most 1 argument (major, minor, or patch)")
+	}
 
-	if err := verifyGitHubAccess(); err != nil {
+	var requestedIncrement string
+	if len(args) == 1 {
+		requestedIncrement = args[0]
+		if requestedIncrement != "major" && requestedIncrement != "minor" && requestedIncrement != "patch" {
+			return fmt.Errorf("invalid argument '%s': must be 'major', 'minor', or 'patch'", requestedIncrement)
+		}
+	} else {
+		requestedIncrement = "auto"
+	}
 
+	// ---
+	// THE LAW OF VERSION: Determine versioning strategy
+	// ---
+	allServices := config.GetAllServices()
+	strategy, err := planBuildStrategy(allServices, requestedIncrement, forceRebuild)
+	if err != nil {
 		return err
+	}
 
+	if planOnly {
+		return printBuildPlan(ctx, strategy, forceRebuild, planJSON)
 	}
 
 	logFile, err := config.LogFile()
@@ -510,18 +603,6 @@ func runBuild(ctx context.Context, args []string) error {
 		_, _ = fmt.Fprintln(logFile, message)
 	}
 
-	// Check for --force flag
-	forceRebuild := false
-	var filteredArgs []string
-	for _, arg := range args {
-		if arg == "--force" || arg == "-f" {
-			forceRebuild = true
-		} else {
-			filteredArgs = append(filteredArgs, arg)
-		}
-	}
-	args = filteredArgs
-
 	// Check for active processes before starting build (unless forced)
 	if !forceRebuild {
 		if err := waitForActiveProcesses(ctx); err != nil {
@@ -534,91 +615,20 @@ func runBuild(ctx context.Context, args []string) error {
 		ui.PrintWarning(fmt.Sprintf("Failed to clean Redis: %v", err))
 	}
 
-	// Validate arguments
-	if len(args) > 1 {
-		return fmt.Errorf("build command accepts at most 1 argument (major, minor, or patch)")
-	}
-
-	var requestedIncrement string
-	if len(args) == 1 {
-		requestedIncrement = args[0]
-		if requestedIncrement != "major" && requestedIncrement != "minor" && requestedIncrement != "patch" {
-			return fmt.Errorf("invalid argument '%s': must be 'major', 'minor', or 'patch'", requestedIncrement)
-		}
-	} else {
-		requestedIncrement = "auto"
-	}
-
 	log("Build command called...")
 	ui.PrintHeader("Building All Services from Local Source")
-	allServices := config.GetAllServices()
-
-	// ---
-	// THE LAW OF VERSION: Determine versioning strategy
-	// ---
-	var servicesWithChanges []config.ServiceDefinition
-	for _, s := range allServices {
-		if s.IsBuildable() && hasUncommittedChanges(s) {
-			servicesWithChanges = append(servicesWithChanges, s)
-		}
+	for _, note := range strategy.notes {
+		ui.PrintInfo(note)
 	}
-
-	var incrementType string
-	var buildAllServices bool
-	var targetMajorAll, targetMinorAll, targetPatchAll int
-
-	switch requestedIncrement {
-	case "major":
-		// LAW 3: Major increment - force ALL services to same major version
-		ui.PrintInfo("Major release: incrementing ALL services to same major version")
-		highestMajor, _, err := getHighestMajorMinor(allServices)
-		if err != nil {
-			return err
-		}
-		targetMajorAll = highestMajor + 1
-		targetMinorAll = 0
-		targetPatchAll = 0
-		incrementType = "major"
-		buildAllServices = true
-
-	case "minor":
-		// LAW 2: Minor increment - force ALL services to same minor version
-		ui.PrintInfo("Minor release: incrementing ALL services to same minor version")
-		highestMajor, highestMinor, err := getHighestMajorMinor(allServices)
-		if err != nil {
-			return err
-		}
-		targetMajorAll = highestMajor
-		targetMinorAll = highestMinor + 1
-		targetPatchAll = 0
-		incrementType = "minor"
-		buildAllServices = true
-
-	case "patch", "auto":
-		// If force rebuild is specified, build ALL services
-		if forceRebuild {
-			ui.PrintInfo("Force rebuild: building all services")
-			servicesWithChanges = []config.ServiceDefinition{}
-			for _, s := range allServices {
-				if s.IsBuildable() {
-					servicesWithChanges = append(servicesWithChanges, s)
-				}
-			}
-		} else if len(servicesWithChanges) == 0 {
-			ui.PrintWarning("No uncommitted changes detected in any service")
-			return nil
-		}
-
-		if len(servicesWithChanges) == 1 {
-			ui.PrintInfo(fmt.Sprintf("Building %s with patch increment", servicesWithChanges[0].ShortName))
-		} else {
-			ui.PrintInfo(fmt.Sprintf("Building %d services with individual patch increments", len(servicesWithChanges)))
-		}
-
-		incrementType = "patch"
-		buildAllServices = false
+	if strategy.noChanges {
+		ui.PrintWarning("No uncommitted changes detected in any service")
+		return nil
 	}
 
+	incrementType := strategy.incrementType
+	targetMajorAll, targetMinorAll, targetPatchAll := strategy.targetMajor, strategy.targetMinor, strategy.targetPatch
+	buildTasks := strategy.tasks
+
 	// ---
 	// 1. Capture "before" state: Get versions and sizes by executing binaries
 	// ---
@@ -634,134 +644,79 @@ func runBuild(ctx context.Context, args []string) error {
 	}
 
 	// ---
-	// 2. Build services based on versioning strategy
-	// ---
-	type buildTask struct {
-		service                               config.ServiceDefinition
-		targetMajor, targetMinor, targetPatch int
-	}
-
-	var buildTasks []buildTask
-
-	// Determine which services to build and their target versions
-	for _, s := range allServices {
-		if !s.IsBuildable() {
-			continue
-		}
-
-		// Check if source code exists
-		sourcePath, err := config.ExpandPath(s.Source)
-		if err != nil || sourcePath == "" {
-			continue
-		}
-		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
-			continue
-		}
-
-		// Skip if not building all services and this service has no changes
-		if !buildAllServices {
-			hasChanges := false
-			for _, changed := range servicesWithChanges {
-				if changed.ID == s.ID {
-					hasChanges = true
-					break
-				}
-			}
-			if !hasChanges {
-				continue
-			}
-		}
-
-		var targetMajor, targetMinor, targetPatch int
-
-		if buildAllServices {
-			// Use the shared version for all services
-			targetMajor = targetMajorAll
-			targetMinor = targetMinorAll
-			targetPatch = targetPatchAll
-		} else {
-			// Individual patch increment
-			baseMajor, baseMinor, basePatch, err := getServiceVersion(s)
-			if err != nil {
-				return fmt.Errorf("failed to get version for %s: %w", s.ShortName, err)
-			}
-			targetMajor = baseMajor
-			targetMinor = baseMinor
-			targetPatch = basePatch + 1
-		}
-
-		buildTasks = append(buildTasks, buildTask{
-			service:     s,
-			targetMajor: targetMajor,
-			targetMinor: targetMinor,
-			targetPatch: targetPatch,
-		})
-	}
-
-	// ---
-	// 2. Build Phase: Build each service
+	// 2. Build Phase: Build independent services in parallel
 	// ---
 	ui.PrintHeader("Build Phase")
+	if len(buildTasks) > 1 {
+		ui.PrintInfo(fmt.Sprintf("Building %d services, up to %d at once. Per-service logs: ~/Dexter/logs/build/", len(buildTasks), min(jobs, len(buildTasks))))
+	}
 	var builtServices []config.ServiceDefinition
+	// Versions the Go pipeline stored before replacing a binary, the only safe rollback targets
+	var previousMu sync.Mutex
+	previousVersions := make(map[string]string)
 
-	for i, task := range buildTasks {
-		// Check for cancellation between tasks
-		select {
-		case <-ctx.Done():
-			return ctx.Err()
-		default:
-		}
-
-		if i > 0 {
-			fmt.Println()
-		}
-
+	results, buildErr := scheduleBuilds(ctx, buildTasks, jobs, func(ctx context.Context, task buildTask, stream *buildStream) (bool, error) {
 		s := task.service
-		ui.PrintInfo(fmt.Sprintf("%s%s%s", ui.ColorCyan, fmt.Sprintf("# Building %s", s.ShortName), ui.ColorReset))
-
-		baseMajor, baseMinor, basePatch, err := getServiceVersion(s)
-		if err != nil {
-			return fmt.Errorf("failed to get version for %s: %w", s.ShortName, err)
-		}
-
-		ui.PrintInfo(fmt.Sprintf("Incrementing version: %d.%d.%d -> %d.%d.%d (%s)",
-			baseMajor, baseMinor, basePatch, task.targetMajor, task.targetMinor, task.targetPatch, incrementType))
+		stream.Info(fmt.Sprintf("# Building %s", s.ShortName))
+		stream.Info(fmt.Sprintf("Incrementing version: %d.%d.%d -> %d.%d.%d (%s)",
+			task.baseMajor, task.baseMinor, task.basePatch, task.targetMajor, task.targetMinor, task.targetPatch, incrementType))
 
 		var built bool
-		var buildErr error
-		serviceStartTime := time.Now()
-		if s.Type == "fe" { // Check if it's a frontend service
-			built, buildErr = buildFrontendService(ctx, s, log, task.targetMajor, task.targetMinor, task.targetPatch)
+		var err error
+		if s.GetBuildKind() == config.BuildKindFrontend { // Check if it's a frontend service
+			built, err = buildFrontendService(ctx, s, stream.Log, stream, task.targetMajor, task.targetMinor, task.targetPatch)
 		} else {
-			built, buildErr = utils.RunUnifiedBuildPipeline(ctx, s, log, task.targetMajor, task.targetMinor, task.targetPatch)
+			var previous string
+			built, previous, err = utils.RunUnifiedBuildPipeline(ctx, s, stream.Log, stream, task.targetMajor, task.targetMinor, task.targetPatch)
+			if previous != "" {
+				previousMu.Lock()
+				previousVersions[s.ID] = previous
+				previousMu.Unlock()
+			}
 		}
 
-		serviceDuration := time.Since(serviceStartTime)
+		switch {
+		case err != nil && ctx.Err() != nil:
+			stream.Error("Cancelled")
+		case err != nil:
+			stream.Error(fmt.Sprintf("Build failed: %v", err))
+		case built:
+			stream.Success(fmt.Sprintf("Successfully built %s!", s.ShortName))
+		}
+		return built, err
+	})
 
-		if buildErr != nil {
+	for _, result := range results {
+		s := result.task.service
+		if result.err != nil {
+			if result.cancelled {
+				continue
+			}
 			// EMIT NOTIFICATION ON FAILURE
 			utils.SendEvent("system.notification.generated", map[string]interface{}{
 				"title":    fmt.Sprintf("Build Failed: %s", s.ShortName),
 				"priority": "critical",
 				"category": "build",
-				"body":     fmt.Sprintf("Build failure in service '%s'. Error: %v", s.ShortName, buildErr),
+				"body":     fmt.Sprintf("Build failure in service '%s'. Error: %v", s.ShortName, result.err),
 			})
-			return buildErr
+			continue
 		}
 
-		if built {
+		if result.built {
 			builtServices = append(builtServices, s)
-			ui.PrintSuccess(fmt.Sprintf("Successfully built %s!", s.ShortName))
 
 			// EMIT EVENT: system.build.completed
 			utils.SendEvent("system.build.completed", map[string]interface{}{
 				"service_name": s.ShortName,
-				"version":      fmt.Sprintf("%d.%d.%d", task.targetMajor, task.targetMinor, task.targetPatch),
-				"duration":     serviceDuration.String(),
+				"version":      fmt.Sprintf("%d.%d.%d", result.task.targetMajor, result.task.targetMinor, result.task.targetPatch),
+				"duration":     result.duration.String(),
 				"status":       "success",
 			})
 		}
 	}
+	if buildErr != nil {
+		return buildErr
+	}
 
 	// ---
 	// 3. Install Phase: Install each built service
@@ -779,6 +734,23 @@ func runBuild(ctx context.Context, args []string) error {
 			if err := utils.InstallSystemdService(s); err != nil {
 				return err
 			}
+
+			// A new binary must come up healthy, otherwise the one this build replaced is put back.
+			// Without a stored copy of it there is nothing safe to roll back to.
+			binPath, _ := config.ExpandPath(s.GetBinaryPath())
+			if _, err := os.Stat(binPath); err == nil {
+				previous := previousVersions[s.ID]
+				ui.PrintInfo(fmt.Sprintf("Waiting for %s to report healthy...", s.ShortName))
+				if err := utils.VerifyOrRollback(ctx, s, previous); err != nil {
+					utils.SendEvent("system.notification.generated", map[string]interface{}{
+						"title":    fmt.Sprintf("Install Failed: %s", s.ShortName),
+						"priority": "critical",
+						"category": "build",
+						"body":     err.Error(),
+					})
+					return err
+				}
+			}
 			ui.PrintSuccess(fmt.Sprintf("Successfully installed %s!", s.ShortName))
 		}
 	}
@@ -823,7 +795,29 @@ func runBuild(ctx context.Context, args []string) error {
 	}
 
 	// ---
-	// 6. Publish to easter.company (major, minor, AND patch)
+	// 6. Changelog Phase: what changed in each service since its previous release
+	// ---
+	changelogs := make(map[string]*release.ServiceChangelog)
+	if (incrementType == "minor" || incrementType == "major") && len(builtServices) > 0 {
+		fmt.Println()
+		ui.PrintHeader("Changelog Phase")
+
+		for _, task := range buildTasks {
+			if !containsService(builtServices, task.service) {
+				continue
+			}
+			version := fmt.Sprintf("%d.%d.%d", task.targetMajor, task.targetMinor, task.targetPatch)
+			changelog, err := buildServiceChangelog(task.service, version, summarizeChangelog)
+			if err != nil {
+				ui.PrintWarning(fmt.Sprintf("[%s] Failed to generate changelog: %v", task.service.ShortName, err))
+				continue
+			}
+			changelogs[task.service.ShortName] = changelog
+		}
+	}
+
+	// ---
+	// 7. Publish to easter.company (major, minor, AND patch)
 	// ---
 	if incrementType == "major" || incrementType == "minor" || incrementType == "patch" {
 		fmt.Println()
@@ -861,7 +855,7 @@ func runBuild(ctx context.Context, args []string) error {
 			fullVersion = newVersions[builtServices[0].ID]
 		}
 
-		if err := release.PublishRelease(fullVersion, shortVersion, incrementType, builtServices); err != nil {
+		if err := release.PublishRelease(fullVersion, shortVersion, incrementType, builtServices, changelogs); err != nil {
 			ui.PrintError(fmt.Sprintf("Failed to publish release: %v", err))
 			ui.PrintWarning("Binaries are built and committed, but not published to easter.company")
 		} else {
@@ -870,7 +864,7 @@ func runBuild(ctx context.Context, args []string) error {
 	}
 
 	// ---
-	// 7. Summary
+	// 8. Summary
 	// ---
 	fmt.Println()
 	ui.PrintHeader("Summary")
@@ -928,7 +922,7 @@ func runBuild(ctx context.Context, args []string) error {
 	fmt.Println()
 
 	// ---
-	// 8. Ollama Model Sync (runs after all builds and installs are complete)
+	// 9. Ollama Model Sync (runs after all builds and installs are complete)
 	// ---
 	// This uses the *newly-built* dex-cli binary to ensure its own models are in sync.
 	if len(builtServices) > 0 {
@@ -956,7 +950,7 @@ func runBuild(ctx context.Context, args []string) error {
 	ui.PrintSuccess("Build complete.")
 
 	// ---
-	// 9. Run release script if version increment was requested (post-build actions)
+	// 10. Run release script if version increment was requested (post-build actions)
 	// ---
 	if incrementType != "" && len(builtServices) > 0 {
 		fmt.Println()
@@ -1066,3 +1060,51 @@ func gitAddCommitPush(def config.ServiceDefinition, incrementType string, major,
 
 	return nil
 }
+
+// containsService reports whether a service is in a list.
+func containsService(services []config.ServiceDefinition, service config.ServiceDefinition) bool {
+	for _, s := range services {
+		if s.ID == service.ID {
+			return true
+		}
+	}
+	return false
+}
+
+// buildServiceChangelog collects a service's commits since its previous minor or major release
+// tag, optionally asking dex-summary-model for a short summary of them.
+func buildServiceChangelog(def config.ServiceDefinition, version string, summarize bool) (*release.ServiceChangelog, error) {
+	sourcePath, err := config.ExpandPath(def.Source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to expand source path: %w", err)
+	}
+
+	previous, err := git.GetPreviousReleaseTag(sourcePath, version)
+	if err != nil {
+		return nil, err
+	}
+	changelog, err := release.BuildChangelog(sourcePath, previous, version)
+	if err != nil {
+		return nil, err
+	}
+
+	commits := 0
+	for _, group := range changelog.Groups {
+		commits += len(group.Entries)
+	}
+	if previous == "" {
+		ui.PrintInfo(fmt.Sprintf("[%s] First release: %d commits", def.ShortName, commits))
+	} else {
+		ui.PrintInfo(fmt.Sprintf("[%s] %d commits since %s", def.ShortName, commits, previous))
+	}
+
+	if summarize && !changelog.Empty() {
+		prompt := fmt.Sprintf("Summarise these changes to the %s service for users deciding whether to update, in 2-3 plain sentences. Output ONLY the summary:\n\n%s", def.ShortName, changelog.Plain())
+		if summary, err := utils.GenerateContent("dex-summary-model", prompt); err != nil {
+			ui.PrintWarning(fmt.Sprintf("[%s] Changelog summary skipped: %v", def.ShortName, err))
+		} else {
+			changelog.Summary = strings.TrimSpace(summary)
+		}
+	}
+	return changelog, nil
+}
@@ -0,0 +1,338 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/release"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// buildPlanSampleKeys is how many Redis keys the text plan lists before summarising
+const buildPlanSampleKeys = 20
+
+// buildTask is a service 'dex build' will build and the version it is built as.
+type buildTask struct {
+	service                               config.ServiceDefinition
+	baseMajor, baseMinor, basePatch       int
+	targetMajor, targetMinor, targetPatch int
+}
+
+// buildStrategy is the outcome of the version rules for one 'dex build' invocation.
+type buildStrategy struct {
+	incrementType string
+	// targetMajor, targetMinor and targetPatch are the shared version of a major or minor release
+	targetMajor, targetMinor, targetPatch int
+	tasks                                 []buildTask
+	// notes explain the strategy; they are printed before building
+	notes []string
+	// noChanges is set when an automatic patch build found nothing to build
+	noChanges bool
+}
+
+// planBuildStrategy applies THE LAW OF VERSION: major and minor releases move every service to
+// one shared version, while patch builds increment each changed service individually. It only
+// reads state, so it is safe to call for a plan.
+func planBuildStrategy(allServices []config.ServiceDefinition, requestedIncrement string, forceRebuild bool) (*buildStrategy, error) {
+	var servicesWithChanges []config.ServiceDefinition
+	for _, s := range allServices {
+		if s.IsBuildable() && hasUncommittedChanges(s) {
+			servicesWithChanges = append(servicesWithChanges, s)
+		}
+	}
+
+	strategy := &buildStrategy{}
+	buildAllServices := false
+
+	switch requestedIncrement {
+	case "major":
+		// LAW 3: Major increment - force ALL services to same major version
+		strategy.notes = append(strategy.notes, "Major release: incrementing ALL services to same major version")
+		highestMajor, _, err := getHighestMajorMinor(allServices)
+		if err != nil {
+			return nil, err
+		}
+		strategy.targetMajor = highestMajor + 1
+		strategy.incrementType = "major"
+		buildAllServices = true
+
+	case "minor":
+		// LAW 2: Minor increment - force ALL services to same minor version
+		strategy.notes = append(strategy.notes, "Minor release: incrementing ALL services to same minor version")
+		highestMajor, highestMinor, err := getHighestMajorMinor(allServices)
+		if err != nil {
+			return nil, err
+		}
+		strategy.targetMajor = highestMajor
+		strategy.targetMinor = highestMinor + 1
+		strategy.incrementType = "minor"
+		buildAllServices = true
+
+	case "patch", "auto":
+		strategy.incrementType = "patch"
+		// If force rebuild is specified, build ALL services
+		if forceRebuild {
+			strategy.notes = append(strategy.notes, "Force rebuild: building all services")
+			servicesWithChanges = []config.ServiceDefinition{}
+			for _, s := range allServices {
+				if s.IsBuildable() {
+					servicesWithChanges = append(servicesWithChanges, s)
+				}
+			}
+		} else if len(servicesWithChanges) == 0 {
+			strategy.noChanges = true
+			return strategy, nil
+		}
+
+		if len(servicesWithChanges) == 1 {
+			strategy.notes = append(strategy.notes, fmt.Sprintf("Building %s with patch increment", servicesWithChanges[0].ShortName))
+		} else {
+			strategy.notes = append(strategy.notes, fmt.Sprintf("Building %d services with individual patch increments", len(servicesWithChanges)))
+		}
+	}
+
+	// Determine which services to build and their target versions
+	for _, s := range allServices {
+		if !s.IsBuildable() {
+			continue
+		}
+
+		// Check if source code exists
+		sourcePath, err := config.ExpandPath(s.Source)
+		if err != nil || sourcePath == "" {
+			continue
+		}
+		if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+			continue
+		}
+
+		// Skip if not building all services and this service has no changes
+		if !buildAllServices && !containsService(servicesWithChanges, s) {
+			continue
+		}
+
+		baseMajor, baseMinor, basePatch, err := getServiceVersion(s)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get version for %s: %w", s.ShortName, err)
+		}
+		task := buildTask{service: s, baseMajor: baseMajor, baseMinor: baseMinor, basePatch: basePatch}
+
+		if buildAllServices {
+			// Use the shared version for all services
+			task.targetMajor, task.targetMinor, task.targetPatch = strategy.targetMajor, strategy.targetMinor, strategy.targetPatch
+		} else {
+			// Individual patch increment
+			task.targetMajor, task.targetMinor, task.targetPatch = baseMajor, baseMinor, basePatch+1
+		}
+		strategy.tasks = append(strategy.tasks, task)
+	}
+
+	return strategy, nil
+}
+
+// BuildPlan is what 'dex build --plan' reports
+type BuildPlan struct {
+	Increment string             `json:"increment"`
+	Force     bool               `json:"force"`
+	Notes     []string           `json:"notes,omitempty"`
+	Services  []BuildPlanService `json:"services"`
+	Redis     BuildPlanRedis     `json:"redis"`
+	Commits   []BuildPlanCommit  `json:"commits"`
+	Changelog bool               `json:"changelog"`
+	Publish   bool               `json:"publish"`
+	Platforms []string           `json:"platforms,omitempty"`
+}
+
+// BuildPlanService is a service the build would produce
+type BuildPlanService struct {
+	Service          string `json:"service"`
+	ID               string `json:"id"`
+	Kind             string `json:"kind"`
+	InstalledVersion string `json:"installed_version"`
+	CurrentVersion   string `json:"current_version"`
+	TargetVersion    string `json:"target_version"`
+}
+
+// BuildPlanRedis lists the runtime keys the build would delete before building
+type BuildPlanRedis struct {
+	Delete    []string `json:"delete"`
+	Preserved int      `json:"preserved"`
+	Error     string   `json:"error,omitempty"`
+}
+
+// BuildPlanCommit is what the Git phase would do in one repository
+type BuildPlanCommit struct {
+	Service      string   `json:"service"`
+	ChangedFiles []string `json:"changed_files"`
+	Commit       bool     `json:"commit"`
+	Tag          string   `json:"tag"`
+	TagExists    bool     `json:"tag_exists"`
+	Push         bool     `json:"push"`
+}
+
+// printBuildPlan reports what a build with this strategy would do, without doing any of it.
+func printBuildPlan(ctx context.Context, strategy *buildStrategy, force, asJSON bool) error {
+	plan := BuildPlan{
+		Increment: strategy.incrementType,
+		Force:     force,
+		Notes:     strategy.notes,
+		Services:  []BuildPlanService{},
+		Commits:   []BuildPlanCommit{},
+		Redis:     BuildPlanRedis{Delete: []string{}},
+	}
+	if strategy.noChanges {
+		plan.Notes = append(plan.Notes, "No uncommitted changes detected in any service")
+	}
+
+	deleteKeys, preserved, err := utils.PlanWipeRedis(ctx)
+	if err != nil {
+		plan.Redis.Error = err.Error()
+	} else {
+		plan.Redis.Delete = deleteKeys
+		plan.Redis.Preserved = preserved
+	}
+
+	for _, task := range strategy.tasks {
+		s := task.service
+		target := fmt.Sprintf("%d.%d.%d", task.targetMajor, task.targetMinor, task.targetPatch)
+		plan.Services = append(plan.Services, BuildPlanService{
+			Service:          s.ShortName,
+			ID:               s.ID,
+			Kind:             s.GetBuildKind(),
+			InstalledVersion: utils.GetBinaryVersion(s),
+			CurrentVersion:   fmt.Sprintf("%d.%d.%d", task.baseMajor, task.baseMinor, task.basePatch),
+			TargetVersion:    target,
+		})
+
+		changed := uncommittedFiles(s)
+		plan.Commits = append(plan.Commits, BuildPlanCommit{
+			Service:      s.ShortName,
+			ChangedFiles: changed,
+			Commit:       len(changed) > 0,
+			Tag:          target,
+			TagExists:    tagExists(s, target),
+			Push:         true,
+		})
+	}
+
+	// Patch releases are source-only; PublishRelease skips them
+	if len(strategy.tasks) > 0 && (strategy.incrementType == "major" || strategy.incrementType == "minor") {
+		plan.Publish = true
+		plan.Changelog = true
+		if platforms, err := release.BuildPlatforms(); err == nil {
+			for _, platform := range platforms {
+				plan.Platforms = append(plan.Platforms, platform.String())
+			}
+		}
+	}
+
+	if asJSON {
+		data, err := json.MarshalIndent(plan, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+		return nil
+	}
+	renderBuildPlan(plan)
+	return nil
+}
+
+// renderBuildPlan prints a plan for people.
+func renderBuildPlan(plan BuildPlan) {
+	ui.PrintHeader("Build Plan")
+	for _, note := range plan.Notes {
+		ui.PrintInfo(note)
+	}
+
+	ui.PrintSubHeader("Services")
+	if len(plan.Services) == 0 {
+		ui.PrintInfo("Nothing to build.")
+	} else {
+		table := ui.NewTable([]string{"SERVICE", "KIND", "INSTALLED", "CURRENT", "TARGET"})
+		for _, s := range plan.Services {
+			table.AddRow(ui.TableRow{s.Service, orNA(s.Kind), orNA(s.InstalledVersion), s.CurrentVersion, ui.Colorize(s.TargetVersion, ui.ColorGreen)})
+		}
+		table.Render()
+	}
+
+	ui.PrintSubHeader("Redis")
+	if plan.Redis.Error != "" {
+		ui.PrintWarning(fmt.Sprintf("Could not inspect Redis: %s", plan.Redis.Error))
+	} else {
+		ui.PrintInfo(fmt.Sprintf("%d runtime keys would be deleted, %d persistent keys preserved.", len(plan.Redis.Delete), plan.Redis.Preserved))
+		for i, key := range plan.Redis.Delete {
+			if i == buildPlanSampleKeys {
+				ui.PrintInfo(ui.Colorize(fmt.Sprintf("  ... and %d more (use --json for the full list)", len(plan.Redis.Delete)-i), ui.ColorDarkGray))
+				break
+			}
+			ui.PrintInfo("  " + key)
+		}
+	}
+
+	ui.PrintSubHeader("Git")
+	if len(plan.Commits) == 0 {
+		ui.PrintInfo("No commits.")
+	} else {
+		table := ui.NewTable([]string{"SERVICE", "COMMIT", "TAG", "PUSH"})
+		for _, c := range plan.Commits {
+			commit := ui.Colorize("no changes", ui.ColorDarkGray)
+			if c.Commit {
+				commit = fmt.Sprintf("%d changed files", len(c.ChangedFiles))
+			}
+			tag := c.Tag
+			if c.TagExists {
+				tag += ui.Colorize(" (exists)", ui.ColorYellow)
+			}
+			table.AddRow(ui.TableRow{c.Service, commit, tag, "yes"})
+		}
+		table.Render()
+	}
+
+	ui.PrintSubHeader("Publish")
+	if plan.Publish {
+		ui.PrintInfo(fmt.Sprintf("Would publish a %s release to easter.company for %s, with changelogs.", plan.Increment, strings.Join(plan.Platforms, ", ")))
+	} else {
+		ui.PrintInfo("Would not publish (patch builds are source-only).")
+	}
+	fmt.Println()
+	ui.PrintInfo("No changes made (--plan).")
+}
+
+// uncommittedFiles lists the files git reports as changed in a service's repository.
+func uncommittedFiles(def config.ServiceDefinition) []string {
+	sourcePath, err := config.ExpandPath(def.Source)
+	if err != nil {
+		return nil
+	}
+	cmd := exec.Command("git", "status", "--porcelain")
+	cmd.Dir = sourcePath
+	output, err := cmd.Output()
+	if err != nil {
+		return nil
+	}
+	files := []string{}
+	for _, line := range strings.Split(strings.TrimRight(string(output), "\n"), "\n") {
+		if len(line) > 3 {
+			files = append(files, line[3:])
+		}
+	}
+	return files
+}
+
+// tagExists reports whether a service's repository already has a tag.
+func tagExists(def config.ServiceDefinition, tag string) bool {
+	sourcePath, err := config.ExpandPath(def.Source)
+	if err != nil {
+		return false
+	}
+	cmd := exec.Command("git", "rev-parse", "-q", "--verify", "refs/tags/"+tag)
+	cmd.Dir = sourcePath
+	return cmd.Run() == nil
+}
@@ -0,0 +1,300 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/ui"
+)
+
+// maxDefaultBuildJobs caps the default parallelism; every Go build already uses all cores
+const maxDefaultBuildJobs = 4
+
+// buildStreamColors are cycled through to tell the services' output apart
+var buildStreamColors = []string{ui.ColorCyan, ui.ColorPurple, ui.ColorBlue, ui.ColorGreen, ui.ColorYellow, ui.ColorBrightRed}
+
+// buildResult is the outcome of one scheduled build
+type buildResult struct {
+	task     buildTask
+	built    bool
+	err      error
+	duration time.Duration
+	// cancelled is set when the build was stopped because the user interrupted
+	cancelled bool
+}
+
+// buildFunc builds one task, logging to its stream
+type buildFunc func(ctx context.Context, task buildTask, stream *buildStream) (bool, error)
+
+// defaultBuildJobs returns options.json build.jobs, or the number of CPUs up to maxDefaultBuildJobs.
+func defaultBuildJobs() int {
+	if options, err := config.LoadOptionsConfig(); err == nil && options.Build.Jobs > 0 {
+		return options.Build.Jobs
+	}
+	return max(1, min(runtime.NumCPU(), maxDefaultBuildJobs))
+}
+
+// parseBuildJobs reads the value of a -j/--jobs flag.
+func parseBuildJobs(value string) (int, error) {
+	jobs, err := strconv.Atoi(value)
+	if err != nil || jobs < 1 {
+		return 0, fmt.Errorf("invalid job count '%s': must be a positive number", value)
+	}
+	return jobs, nil
+}
+
+// buildOrder resolves each task's build_after entries to the indexes of the tasks it waits
+// for. Services that are not part of this build impose no ordering. It fails on a cycle.
+func buildOrder(tasks []buildTask) ([][]int, error) {
+	services := make([]config.ServiceDefinition, len(tasks))
+	for i, task := range tasks {
+		services[i] = task.service
+	}
+
+	after := make([][]int, len(tasks))
+	for i, task := range tasks {
+		for _, name := range task.service.BuildAfter {
+			dep, ok := config.FindService(services, name)
+			if !ok {
+				continue
+			}
+			for j := range tasks {
+				if tasks[j].service.ID == dep.ID && j != i {
+					after[i] = append(after[i], j)
+				}
+			}
+		}
+	}
+
+	// Repeatedly place tasks whose predecessors are placed; anything left over is in a cycle
+	placed := make([]bool, len(tasks))
+	for progress := true; progress; {
+		progress = false
+		for i := range tasks {
+			if placed[i] {
+				continue
+			}
+			ready := true
+			for _, j := range after[i] {
+				ready = ready && placed[j]
+			}
+			if ready {
+				placed[i] = true
+				progress = true
+			}
+		}
+	}
+	var cycle []string
+	for i, ok := range placed {
+		if !ok {
+			cycle = append(cycle, tasks[i].service.ShortName)
+		}
+	}
+	if len(cycle) > 0 {
+		return nil, fmt.Errorf("build_after cycle between: %s", strings.Join(cycle, ", "))
+	}
+	return after, nil
+}
+
+// scheduleBuilds runs the tasks with at most jobs of them at once, starting each as soon as the
+// services it builds after are done. Tasks are started in the order given, so with one job the
+// build is sequential. The first failure stops new builds from starting, but the ones in flight
+// are left to finish so no service is stopped halfway through its build. Results are returned in
+// task order for every task that ran, whatever order they finished in.
+func scheduleBuilds(ctx context.Context, tasks []buildTask, jobs int, build buildFunc) ([]buildResult, error) {
+	after, err := buildOrder(tasks)
+	if err != nil {
+		return nil, err
+	}
+
+	streams := newBuildStreams(tasks)
+	defer streams.close()
+
+	results := make([]*buildResult, len(tasks))
+	started := make([]bool, len(tasks))
+	finished := make(chan *buildResult)
+	indexOf := make(map[string]int, len(tasks))
+	for i, task := range tasks {
+		indexOf[task.service.ID] = i
+	}
+
+	running := 0
+	var firstErr error
+	for {
+		// Start every task that is ready, in order, while there are free slots
+		for i := 0; i < len(tasks) && running < jobs && firstErr == nil && ctx.Err() == nil; i++ {
+			if started[i] {
+				continue
+			}
+			ready := true
+			for _, j := range after[i] {
+				ready = ready && results[j] != nil
+			}
+			if !ready {
+				continue
+			}
+			started[i] = true
+			running++
+			go func(task buildTask, stream *buildStream) {
+				start := time.Now()
+				built, err := build(ctx, task, stream)
+				stream.flush()
+				finished <- &buildResult{task: task, built: built, err: err, duration: time.Since(start), cancelled: err != nil && ctx.Err() != nil}
+			}(tasks[i], streams.forTask(i))
+		}
+
+		if running == 0 {
+			break
+		}
+		result := <-finished
+		running--
+		results[indexOf[result.task.service.ID]] = result
+		if result.err != nil && firstErr == nil {
+			firstErr = result.err
+		}
+	}
+
+	var ordered []buildResult
+	for _, result := range results {
+		if result != nil {
+			ordered = append(ordered, *result)
+		}
+	}
+	if firstErr == nil && ctx.Err() != nil {
+		firstErr = ctx.Err()
+	}
+	return ordered, firstErr
+}
+
+// buildStreams holds the log stream of every task in a build. Output from all of them is
+// printed line by line, prefixed with the service name, so parallel builds stay readable.
+type buildStreams struct {
+	mu      sync.Mutex
+	streams []*buildStream
+	logFile *os.File
+}
+
+// buildStream is one service's output: its lines go to the terminal with a prefix and,
+// unprefixed, to ~/Dexter/logs/build/<service>.log.
+type buildStream struct {
+	parent  *buildStreams
+	prefix  string
+	name    string
+	file    *os.File
+	pending []byte
+}
+
+// newBuildStreams opens a log stream for each task. Log files are optional; a stream without
+// one still prints.
+func newBuildStreams(tasks []buildTask) *buildStreams {
+	streams := &buildStreams{}
+	if logFile, err := config.LogFile(); err == nil {
+		streams.logFile = logFile
+	}
+
+	width := 0
+	for _, task := range tasks {
+		width = max(width, len(task.service.ShortName))
+	}
+	logDir, _ := config.ExpandPath(filepath.Join(config.DexterRoot, "logs", "build"))
+	_ = os.MkdirAll(logDir, 0o755)
+
+	for i, task := range tasks {
+		name := task.service.ShortName
+		stream := &buildStream{
+			parent: streams,
+			name:   name,
+			prefix: ui.Colorize(fmt.Sprintf("[%-*s]", width, name), buildStreamColors[i%len(buildStreamColors)]) + " ",
+		}
+		if file, err := os.Create(filepath.Join(logDir, name+".log")); err == nil {
+			stream.file = file
+		}
+		streams.streams = append(streams.streams, stream)
+	}
+	return streams
+}
+
+func (s *buildStreams) forTask(i int) *buildStream {
+	return s.streams[i]
+}
+
+func (s *buildStreams) close() {
+	for _, stream := range s.streams {
+		if stream.file != nil {
+			_ = stream.file.Close()
+		}
+	}
+	if s.logFile != nil {
+		_ = s.logFile.Close()
+	}
+}
+
+// Write prints complete lines of command output; a trailing partial line waits for the rest.
+func (s *buildStream) Write(p []byte) (int, error) {
+	s.pending = append(s.pending, p...)
+	for {
+		i := bytes.IndexByte(s.pending, '\n')
+		if i < 0 {
+			break
+		}
+		s.println(string(s.pending[:i]))
+		s.pending = s.pending[i+1:]
+	}
+	return len(p), nil
+}
+
+// flush prints a trailing partial line.
+func (s *buildStream) flush() {
+	if len(s.pending) > 0 {
+		s.println(string(s.pending))
+		s.pending = nil
+	}
+}
+
+// println prints one line to the terminal and the service's log file.
+func (s *buildStream) println(line string) {
+	line = strings.TrimRight(line, "\r")
+	s.parent.mu.Lock()
+	defer s.parent.mu.Unlock()
+	ui.PrintRaw(s.prefix + line + "\n")
+	if s.file != nil {
+		_, _ = fmt.Fprintln(s.file, ui.StripANSI(line))
+	}
+}
+
+// Info prints a status line for the service.
+func (s *buildStream) Info(message string) {
+	s.println(ui.Colorize(message, ui.ColorCyan))
+}
+
+// Success prints a success line for the service.
+func (s *buildStream) Success(message string) {
+	s.println(ui.Colorize("✓ "+message, ui.ColorGreen))
+}
+
+// Error prints a failure line for the service.
+func (s *buildStream) Error(message string) {
+	s.println(ui.Colorize("✕ "+message, ui.ColorRed))
+}
+
+// Log records a pipeline message in the service's log file and the dex-cli log, like the
+// log function the build pipelines have always been given.
+func (s *buildStream) Log(message string) {
+	s.parent.mu.Lock()
+	defer s.parent.mu.Unlock()
+	if s.file != nil {
+		_, _ = fmt.Fprintln(s.file, message)
+	}
+	if s.parent.logFile != nil {
+		_, _ = fmt.Fprintf(s.parent.logFile, "[%s] %s\n", s.name, message)
+	}
+}
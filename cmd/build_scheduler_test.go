@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+func testTask(shortName string, buildAfter ...string) buildTask {
+	return buildTask{service: config.ServiceDefinition{ID: "dex-" + shortName + "-service", ShortName: shortName, BuildAfter: buildAfter}}
+}
+
+// isolateBuildLogs keeps the build streams' log files out of the real home directory.
+func isolateBuildLogs(t *testing.T) {
+	t.Helper()
+	t.Setenv("HOME", t.TempDir())
+}
+
+func TestBuildOrder(t *testing.T) {
+	tasks := []buildTask{
+		testTask("a"),
+		testTask("b", "a"),
+		testTask("c", "dex-b-service", "missing"),
+		testTask("d", "d"),
+	}
+	after, err := buildOrder(tasks)
+	if err != nil {
+		t.Fatalf("buildOrder() error = %v", err)
+	}
+	want := [][]int{nil, {0}, {1}, nil}
+	if !reflect.DeepEqual(after, want) {
+		t.Errorf("buildOrder() = %v, want %v", after, want)
+	}
+}
+
+func TestBuildOrderCycle(t *testing.T) {
+	tasks := []buildTask{
+		testTask("a", "c"),
+		testTask("b", "a"),
+		testTask("c", "b"),
+		testTask("d"),
+	}
+	if _, err := buildOrder(tasks); err == nil || err.Error() != "build_after cycle between: a, b, c" {
+		t.Errorf("buildOrder() error = %v, want a cycle between a, b, c", err)
+	}
+	if _, err := scheduleBuilds(context.Background(), tasks, 2, func(context.Context, buildTask, *buildStream) (bool, error) {
+		t.Error("no build should start when build_after has a cycle")
+		return false, nil
+	}); err == nil {
+		t.Error("scheduleBuilds() error = nil, want the cycle")
+	}
+}
+
+func TestScheduleBuildsOneJobIsSequential(t *testing.T) {
+	isolateBuildLogs(t)
+	tasks := []buildTask{testTask("a"), testTask("b"), testTask("c")}
+
+	var mu sync.Mutex
+	var order []string
+	running, maxRunning := 0, 0
+	_, err := scheduleBuilds(context.Background(), tasks, 1, func(_ context.Context, task buildTask, _ *buildStream) (bool, error) {
+		mu.Lock()
+		order = append(order, task.service.ShortName)
+		running++
+		maxRunning = max(maxRunning, running)
+		mu.Unlock()
+
+		time.Sleep(10 * time.Millisecond)
+
+		mu.Lock()
+		running--
+		mu.Unlock()
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("scheduleBuilds() error = %v", err)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(order, want) {
+		t.Errorf("build order = %v, want %v", order, want)
+	}
+	if maxRunning != 1 {
+		t.Errorf("%d builds ran at once with one job", maxRunning)
+	}
+}
+
+func TestScheduleBuildsWaitsForBuildAfter(t *testing.T) {
+	isolateBuildLogs(t)
+	tasks := []buildTask{testTask("a"), testTask("b", "a"), testTask("c")}
+
+	var mu sync.Mutex
+	var events []string
+	_, err := scheduleBuilds(context.Background(), tasks, 3, func(_ context.Context, task buildTask, _ *buildStream) (bool, error) {
+		name := task.service.ShortName
+		mu.Lock()
+		events = append(events, "start "+name)
+		mu.Unlock()
+		if name == "a" {
+			time.Sleep(20 * time.Millisecond)
+		}
+		mu.Lock()
+		events = append(events, "done "+name)
+		mu.Unlock()
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("scheduleBuilds() error = %v", err)
+	}
+
+	index := func(event string) int {
+		for i, e := range events {
+			if e == event {
+				return i
+			}
+		}
+		t.Fatalf("event %q missing from %v", event, events)
+		return -1
+	}
+	if index("start b") < index("done a") {
+		t.Errorf("b started before a finished: %v", events)
+	}
+	if index("start c") > index("done a") {
+		t.Errorf("c waited for a without building after it: %v", events)
+	}
+}
+
+func TestScheduleBuildsResultsInTaskOrder(t *testing.T) {
+	isolateBuildLogs(t)
+	tasks := []buildTask{testTask("a"), testTask("b"), testTask("c")}
+	delays := map[string]time.Duration{"a": 30 * time.Millisecond, "b": 15 * time.Millisecond, "c": 0}
+
+	results, err := scheduleBuilds(context.Background(), tasks, 3, func(_ context.Context, task buildTask, _ *buildStream) (bool, error) {
+		time.Sleep(delays[task.service.ShortName])
+		return true, nil
+	})
+	if err != nil {
+		t.Fatalf("scheduleBuilds() error = %v", err)
+	}
+	var got []string
+	for _, result := range results {
+		got = append(got, result.task.service.ShortName)
+	}
+	if want := []string{"a", "b", "c"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("result order = %v, want %v", got, want)
+	}
+}
+
+func TestScheduleBuildsFailureLetsInFlightFinish(t *testing.T) {
+	isolateBuildLogs(t)
+	tasks := []buildTask{testTask("a"), testTask("b"), testTask("c")}
+	errBuild := errors.New("compile error")
+	failed := make(chan struct{})
+
+	results, err := scheduleBuilds(context.Background(), tasks, 2, func(ctx context.Context, task buildTask, _ *buildStream) (bool, error) {
+		switch task.service.ShortName {
+		case "a":
+			close(failed)
+			return false, errBuild
+		case "b":
+			<-failed
+			select {
+			case <-ctx.Done():
+				return false, ctx.Err()
+			case <-time.After(50 * time.Millisecond):
+				return true, nil
+			}
+		default:
+			t.Error("c started after a build had failed")
+			return true, nil
+		}
+	})
+	if !errors.Is(err, errBuild) {
+		t.Fatalf("scheduleBuilds() error = %v, want %v", err, errBuild)
+	}
+	if len(results) != 2 {
+		t.Fatalf("got %d results, want a and b", len(results))
+	}
+	if b := results[1]; b.err != nil || b.cancelled || !b.built {
+		t.Errorf("b = {built: %v, err: %v, cancelled: %v}, want it built despite a failing", b.built, b.err, b.cancelled)
+	}
+}
+
+func TestScheduleBuildsInterrupted(t *testing.T) {
+	isolateBuildLogs(t)
+	tasks := []buildTask{testTask("a"), testTask("b")}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	results, err := scheduleBuilds(ctx, tasks, 1, func(ctx context.Context, _ buildTask, _ *buildStream) (bool, error) {
+		cancel()
+		<-ctx.Done()
+		return false, ctx.Err()
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("scheduleBuilds() error = %v, want %v", err, context.Canceled)
+	}
+	if len(results) != 1 || !results[0].cancelled {
+		t.Errorf("results = %+v, want only a, marked cancelled", results)
+	}
+}
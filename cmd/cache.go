@@ -1,34 +1,97 @@
 package cmd
 
 import (
+	"bufio"
 	"context"
+	"encoding/json"
+	"flag"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"sort"
+	"strings"
 	"time"
+	"unicode/utf8"
 
 	"github.com/EasterCompany/dex-cli/cache"
+	"github.com/EasterCompany/dex-cli/config"
 	"github.com/EasterCompany/dex-cli/ui"
 	"github.com/EasterCompany/dex-cli/utils"
 )
 
+// cacheGetLimit caps how many elements of a collection 'dex cache get' prints.
+const cacheGetLimit = 100
+
 // Cache manages the local cache.
 func Cache(args []string) error {
-	if len(args) == 0 {
-		return fmt.Errorf("cache command requires a subcommand (clear, list)")
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		ui.PrintHeader("Cache Command Help")
+		ui.PrintInfo("Usage: dex cache <subcommand> [flags]")
+		fmt.Println()
+		ui.PrintInfo("Subcommands:")
+		ui.PrintInfo("  list     [--pattern p] [--limit N] [--sort key|size|ttl]  List matching keys")
+		ui.PrintInfo("  get      <key>                                           Show a key's decoded value")
+		ui.PrintInfo("  del      --pattern p [-y]                                Delete matching keys")
+		ui.PrintInfo("  stats    [--pattern p] [--depth N]                       Memory usage per prefix")
+		ui.PrintInfo("  clear    [-y]                                            Flush the whole database")
+		ui.PrintInfo("  export   [-o file] [--prefix p]... [--dry-run]           Export keys to NDJSON")
+		ui.PrintInfo("  import   [--replace] [--dry-run] <file>                  Import an NDJSON export")
+		if len(args) == 0 {
+			return fmt.Errorf("cache command requires a subcommand")
+		}
+		return nil
 	}
 
 	subcommand := args[0]
 	switch subcommand {
 	case "clear":
-		return clearCache()
+		return clearCache(args[1:])
 	case "list":
-		return listCache()
+		return listCache(args[1:])
+	case "get":
+		return getCache(args[1:])
+	case "del":
+		return delCache(args[1:])
+	case "stats":
+		return statsCache(args[1:])
+	case "export":
+		return exportCache(args[1:])
+	case "import":
+		return importCache(args[1:])
 	default:
 		return fmt.Errorf("unknown cache subcommand: %s", subcommand)
 	}
 }
 
-func clearCache() error {
+// parseCacheFlags parses a cache subcommand's flags, returning done=true if help was shown.
+func parseCacheFlags(flagSet *flag.FlagSet, args []string) (done bool, err error) {
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return true, nil
+		}
+		return false, fmt.Errorf("failed to parse flags for %s: %w", flagSet.Name(), err)
+	}
+	return false, nil
+}
+
+// confirm asks a yes/no question on stdin, defaulting to no.
+func confirm(question string) bool {
+	fmt.Printf("%s [y/N]: ", question)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+func clearCache(args []string) error {
+	var yes bool
+	flagSet := flag.NewFlagSet("cache clear", flag.ContinueOnError)
+	flagSet.BoolVar(&yes, "yes", false, "Skip the confirmation prompt")
+	flagSet.BoolVar(&yes, "y", false, "Skip the confirmation prompt (shorthand)")
+	if done, err := parseCacheFlags(flagSet, args); done || err != nil {
+		return err
+	}
+
 	ctx := context.Background()
 	client, err := cache.GetLocalClient(ctx)
 	if err != nil {
@@ -36,21 +99,125 @@ func clearCache() error {
 	}
 	defer func() { _ = client.Close() }()
 
+	size, err := client.DBSize(ctx).Result()
+	if err != nil {
+		return fmt.Errorf("failed to count keys: %w", err)
+	}
+	if !yes {
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("refusing to flush %d keys without confirmation: stdin is not a terminal, pass --yes", size)
+		}
+		ui.PrintWarning("This deletes every key, including dossiers and event history.")
+		ui.PrintInfo("Use 'dex cache del --pattern' to remove a subset, or 'dex cache export --all' first.")
+		if !confirm(fmt.Sprintf("Flush all %d keys?", size)) {
+			return fmt.Errorf("aborted: cache was not cleared")
+		}
+	}
+
 	if err := client.FlushDB(ctx).Err(); err != nil {
 		return fmt.Errorf("failed to clear cache: %w", err)
 	}
 
-	ui.PrintSuccess("Local cache cleared.")
+	ui.PrintSuccess(fmt.Sprintf("Local cache cleared (%d keys).", size))
 	return nil
 }
 
-type CacheEntry struct {
-	Key    string
-	Size   int64
-	Expiry time.Time
+func listCache(args []string) error {
+	var pattern, sortBy string
+	var limit int
+	flagSet := flag.NewFlagSet("cache list", flag.ContinueOnError)
+	flagSet.StringVar(&pattern, "pattern", "*", "Glob pattern to match keys against")
+	flagSet.IntVar(&limit, "limit", 50, "Maximum number of keys to show (0 for all)")
+	flagSet.StringVar(&sortBy, "sort", "key", "Sort by key, size or ttl")
+	if done, err := parseCacheFlags(flagSet, args); done || err != nil {
+		return err
+	}
+	if sortBy != "key" && sortBy != "size" && sortBy != "ttl" {
+		return fmt.Errorf("invalid --sort value %q (expected key, size or ttl)", sortBy)
+	}
+
+	ctx := context.Background()
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local cache client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var keys []string
+	if err := cache.ScanKeys(ctx, client, pattern, func(batch []string) error {
+		keys = append(keys, batch...)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	if len(keys) == 0 {
+		ui.PrintInfo(fmt.Sprintf("No keys match %q.", pattern))
+		return nil
+	}
+
+	matched := len(keys)
+	var entries []cache.KeyInfo
+	if sortBy == "key" {
+		// Only the keys that will be shown need describing
+		sort.Strings(keys)
+		if limit > 0 && len(keys) > limit {
+			keys = keys[:limit]
+		}
+	}
+	for start := 0; start < len(keys); start += 500 {
+		end := min(start+500, len(keys))
+		infos, err := cache.DescribeKeys(ctx, client, keys[start:end])
+		if err != nil {
+			return err
+		}
+		entries = append(entries, infos...)
+	}
+
+	switch sortBy {
+	case "size":
+		sort.Slice(entries, func(i, j int) bool { return entries[i].Size > entries[j].Size })
+	case "ttl":
+		// Soonest expiry first, keys without an expiry last
+		sort.Slice(entries, func(i, j int) bool {
+			a, b := entries[i].TTL, entries[j].TTL
+			if (a < 0) != (b < 0) {
+				return b < 0
+			}
+			return a < b
+		})
+	}
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+
+	table := ui.NewTableWithWidths([]string{"KEY", "TYPE", "SIZE", "TTL"}, []int{60, 0, 0, 0})
+	var shownSize int64
+	for _, entry := range entries {
+		shownSize += entry.Size
+		table.AddRow(ui.TableRow{entry.Key, entry.Type, utils.FormatBytes(entry.Size), formatCacheTTL(entry.TTL)})
+	}
+	table.Render()
+
+	ui.PrintInfo(fmt.Sprintf("Showing %d of %d keys matching %q (%s).", len(entries), matched, pattern, utils.FormatBytes(shownSize)))
+	return nil
+}
+
+// formatCacheTTL renders a TTL in milliseconds, where -1 means no expiry.
+func formatCacheTTL(ms int64) string {
+	if ms < 0 {
+		return "-"
+	}
+	return (time.Duration(ms) * time.Millisecond).Round(time.Second).String()
 }
 
-func listCache() error {
+func getCache(args []string) error {
+	if len(args) != 1 {
+		return fmt.Errorf("usage: dex cache get <key>")
+	}
+	key := args[0]
+
 	ctx := context.Background()
 	client, err := cache.GetLocalClient(ctx)
 	if err != nil {
@@ -58,58 +225,487 @@ func listCache() error {
 	}
 	defer func() { _ = client.Close() }()
 
-	keys, err := client.Keys(ctx, "*").Result()
+	infos, err := cache.DescribeKeys(ctx, client, []string{key})
 	if err != nil {
-		return fmt.Errorf("failed to list cache keys: %w", err)
+		return err
 	}
+	if len(infos) == 0 {
+		return fmt.Errorf("key '%s' does not exist", key)
+	}
+	info := infos[0]
+	ui.PrintKeyValBlock(key, []ui.KeyVal{
+		{Key: "Type", Value: info.Type},
+		{Key: "Size", Value: utils.FormatBytes(info.Size)},
+		{Key: "TTL", Value: formatCacheTTL(info.TTL)},
+	})
+	fmt.Println()
+
+	switch info.Type {
+	case "string":
+		value, err := client.Get(ctx, key).Result()
+		if err != nil {
+			return err
+		}
+		fmt.Println(formatCacheValue(value))
+	case "hash":
+		table := ui.NewTableWithWidths([]string{"FIELD", "VALUE"}, []int{40, 80})
+		count, err := scanCollection(func(cursor uint64) ([]string, uint64, error) {
+			return client.HScan(ctx, key, cursor, "*", 100).Result()
+		}, 2, func(items []string) {
+			table.AddRow(ui.TableRow{items[0], items[1]})
+		})
+		if err != nil {
+			return err
+		}
+		table.Render()
+		printCacheTruncation(count)
+	case "set":
+		count, err := scanCollection(func(cursor uint64) ([]string, uint64, error) {
+			return client.SScan(ctx, key, cursor, "*", 100).Result()
+		}, 1, func(items []string) {
+			fmt.Println(formatCacheValue(items[0]))
+		})
+		if err != nil {
+			return err
+		}
+		printCacheTruncation(count)
+	case "list":
+		items, err := client.LRange(ctx, key, 0, cacheGetLimit-1).Result()
+		if err != nil {
+			return err
+		}
+		for i, item := range items {
+			fmt.Printf("%d) %s\n", i, formatCacheValue(item))
+		}
+		length, _ := client.LLen(ctx, key).Result()
+		printCacheTruncation(int(length))
+	case "zset":
+		members, err := client.ZRangeWithScores(ctx, key, 0, cacheGetLimit-1).Result()
+		if err != nil {
+			return err
+		}
+		table := ui.NewTableWithWidths([]string{"SCORE", "MEMBER"}, []int{0, 100})
+		for _, z := range members {
+			table.AddRow(ui.TableRow{fmt.Sprintf("%g", z.Score), fmt.Sprint(z.Member)})
+		}
+		table.Render()
+		card, _ := client.ZCard(ctx, key).Result()
+		printCacheTruncation(int(card))
+	case "stream":
+		messages, err := client.XRangeN(ctx, key, "-", "+", cacheGetLimit).Result()
+		if err != nil {
+			return err
+		}
+		for _, msg := range messages {
+			fields, _ := json.Marshal(msg.Values)
+			fmt.Printf("%s %s\n", msg.ID, fields)
+		}
+		length, _ := client.XLen(ctx, key).Result()
+		printCacheTruncation(int(length))
+	default:
+		ui.PrintWarning(fmt.Sprintf("Values of type %s cannot be displayed.", info.Type))
+	}
+	return nil
+}
 
+// scanCollection iterates an HSCAN/SSCAN style cursor, passing groups of `stride` items to fn
+// until cacheGetLimit groups have been shown. It returns the number of groups seen.
+func scanCollection(scan func(cursor uint64) ([]string, uint64, error), stride int, fn func(items []string)) (int, error) {
+	var cursor uint64
+	count := 0
+	for {
+		items, next, err := scan(cursor)
+		if err != nil {
+			return count, err
+		}
+		for i := 0; i+stride <= len(items); i += stride {
+			if count < cacheGetLimit {
+				fn(items[i : i+stride])
+			}
+			count++
+		}
+		cursor = next
+		if cursor == 0 {
+			return count, nil
+		}
+	}
+}
+
+// printCacheTruncation notes how many elements 'dex cache get' left out.
+func printCacheTruncation(total int) {
+	if total > cacheGetLimit {
+		ui.PrintInfo(fmt.Sprintf("... %d more (showing the first %d of %d)", total-cacheGetLimit, cacheGetLimit, total))
+	}
+}
+
+// formatCacheValue pretty-prints JSON values and marks binary data instead of dumping it.
+func formatCacheValue(value string) string {
+	trimmed := strings.TrimSpace(value)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		var v interface{}
+		if json.Unmarshal([]byte(trimmed), &v) == nil {
+			if pretty, err := json.MarshalIndent(v, "", "  "); err == nil {
+				return string(pretty)
+			}
+		}
+	}
+	if !utf8ValidPrintable(value) {
+		return fmt.Sprintf("<binary, %s>", utils.FormatBytes(int64(len(value))))
+	}
+	return value
+}
+
+// utf8ValidPrintable reports whether s is text that is safe to print to a terminal.
+func utf8ValidPrintable(s string) bool {
+	if !utf8.ValidString(s) {
+		return false
+	}
+	for _, r := range s {
+		if r < 0x20 && r != '\n' && r != '\t' && r != '\r' {
+			return false
+		}
+	}
+	return true
+}
+
+func delCache(args []string) error {
+	var pattern string
+	var yes bool
+	flagSet := flag.NewFlagSet("cache del", flag.ContinueOnError)
+	flagSet.StringVar(&pattern, "pattern", "", "Glob pattern of keys to delete")
+	flagSet.BoolVar(&yes, "yes", false, "Skip the confirmation prompt")
+	flagSet.BoolVar(&yes, "y", false, "Skip the confirmation prompt (shorthand)")
+	if done, err := parseCacheFlags(flagSet, args); done || err != nil {
+		return err
+	}
+	if pattern == "" {
+		return fmt.Errorf("cache del requires --pattern (use 'dex cache clear' to flush everything)")
+	}
+
+	ctx := context.Background()
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local cache client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var keys []string
+	if err := cache.ScanKeys(ctx, client, pattern, func(batch []string) error {
+		keys = append(keys, batch...)
+		return nil
+	}); err != nil {
+		return err
+	}
 	if len(keys) == 0 {
-		ui.PrintInfo("Local cache is empty.")
+		ui.PrintInfo(fmt.Sprintf("No keys match %q.", pattern))
 		return nil
 	}
 
-	var entries []CacheEntry
-	var totalSize int64
+	if !yes {
+		if !isTerminal(os.Stdin) {
+			return fmt.Errorf("refusing to delete %d keys without confirmation: stdin is not a terminal, pass --yes", len(keys))
+		}
+		sort.Strings(keys)
+		for i, key := range keys {
+			if i == 10 {
+				ui.PrintInfo(fmt.Sprintf("  ... and %d more", len(keys)-10))
+				break
+			}
+			ui.PrintInfo("  " + key)
+		}
+		if !confirm(fmt.Sprintf("Delete %d keys matching %q?", len(keys), pattern)) {
+			return fmt.Errorf("aborted: no keys were deleted")
+		}
+	}
 
-	for _, key := range keys {
-		size, err := client.MemoryUsage(ctx, key).Result()
+	var deleted int64
+	for start := 0; start < len(keys); start += 500 {
+		end := min(start+500, len(keys))
+		n, err := client.Unlink(ctx, keys[start:end]...).Result()
 		if err != nil {
-			size = 0 // Default to 0 if size can't be fetched
+			return fmt.Errorf("failed to delete keys: %w", err)
 		}
-		totalSize += size
+		deleted += n
+	}
+	ui.PrintSuccess(fmt.Sprintf("Deleted %d keys matching %q.", deleted, pattern))
+	return nil
+}
+
+// cachePrefixStats aggregates key counts and memory usage for one prefix.
+type cachePrefixStats struct {
+	Prefix string
+	Keys   int
+	Size   int64
+}
+
+func statsCache(args []string) error {
+	var pattern string
+	var depth int
+	flagSet := flag.NewFlagSet("cache stats", flag.ContinueOnError)
+	flagSet.StringVar(&pattern, "pattern", "*", "Glob pattern to match keys against")
+	flagSet.IntVar(&depth, "depth", 1, "Number of ':'-separated segments that form a prefix")
+	if done, err := parseCacheFlags(flagSet, args); done || err != nil {
+		return err
+	}
+	if depth < 1 {
+		return fmt.Errorf("--depth must be at least 1")
+	}
+
+	ctx := context.Background()
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local cache client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
 
-		ttl, err := client.TTL(ctx, key).Result()
+	byPrefix := make(map[string]*cachePrefixStats)
+	var totalKeys int
+	var totalSize int64
+	if err := cache.ScanKeys(ctx, client, pattern, func(batch []string) error {
+		infos, err := cache.DescribeKeys(ctx, client, batch)
 		if err != nil {
-			ttl = -1 // No expiry
+			return err
 		}
-		expiry := time.Now().Add(ttl)
-		if ttl == -1 {
-			expiry = time.Time{} // Zero time for no expiry
+		for _, info := range infos {
+			prefix := keyPrefix(info.Key, depth)
+			stats, ok := byPrefix[prefix]
+			if !ok {
+				stats = &cachePrefixStats{Prefix: prefix}
+				byPrefix[prefix] = stats
+			}
+			stats.Keys++
+			stats.Size += info.Size
+			totalKeys++
+			totalSize += info.Size
 		}
+		return nil
+	}); err != nil {
+		return err
+	}
 
-		entries = append(entries, CacheEntry{
-			Key:    key,
-			Size:   size,
-			Expiry: expiry,
-		})
+	if totalKeys == 0 {
+		ui.PrintInfo(fmt.Sprintf("No keys match %q.", pattern))
+		return nil
 	}
 
-	// Sort entries by key
-	sort.Slice(entries, func(i, j int) bool {
-		return entries[i].Key < entries[j].Key
-	})
+	rows := make([]*cachePrefixStats, 0, len(byPrefix))
+	for _, stats := range byPrefix {
+		rows = append(rows, stats)
+	}
+	sort.Slice(rows, func(i, j int) bool { return rows[i].Size > rows[j].Size })
 
-	ui.PrintHeader("Local Cache Entries")
-	for _, entry := range entries {
-		ui.PrintInfo(fmt.Sprintf("Key: %s", entry.Key))
-		ui.PrintInfo(fmt.Sprintf("  Size: %s", utils.FormatBytes(entry.Size)))
-		if !entry.Expiry.IsZero() {
-			ui.PrintInfo(fmt.Sprintf("  Expires: %s", entry.Expiry.Format(time.RFC3339)))
-		} else {
-			ui.PrintInfo("  Expires: (no expiry)")
+	table := ui.NewTable([]string{"PREFIX", "KEYS", "MEMORY", "SHARE"})
+	for _, row := range rows {
+		share := 0.0
+		if totalSize > 0 {
+			share = float64(row.Size) / float64(totalSize) * 100
+		}
+		table.AddRow(ui.TableRow{row.Prefix, fmt.Sprintf("%d", row.Keys), utils.FormatBytes(row.Size), fmt.Sprintf("%.1f%%", share)})
+	}
+	table.Render()
+
+	summary := fmt.Sprintf("%d keys, %s across %d prefixes", totalKeys, utils.FormatBytes(totalSize), len(rows))
+	if used, err := client.Info(ctx, "memory").Result(); err == nil {
+		for _, line := range strings.Split(used, "\n") {
+			if value, ok := strings.CutPrefix(strings.TrimSpace(line), "used_memory_human:"); ok {
+				summary += fmt.Sprintf(" (server total %s)", value)
+				break
+			}
+		}
+	}
+	ui.PrintInfo(summary)
+	return nil
+}
+
+// keyPrefix returns the first `depth` ':'-separated segments of a key, including the trailing ':'.
+// Keys with fewer segments are their own prefix.
+func keyPrefix(key string, depth int) string {
+	idx := 0
+	for i := 0; i < depth; i++ {
+		next := strings.Index(key[idx:], ":")
+		if next < 0 {
+			if i == 0 {
+				return key
+			}
+			break
 		}
+		idx += next + 1
 	}
-	ui.PrintInfo(fmt.Sprintf("Total cache size: %s", utils.FormatBytes(totalSize)))
+	return key[:idx]
+}
 
+// prefixFlag collects repeated --prefix values.
+type prefixFlag []string
+
+func (p *prefixFlag) String() string { return strings.Join(*p, ",") }
+
+func (p *prefixFlag) Set(value string) error {
+	*p = append(*p, value)
 	return nil
 }
+
+// exportCache streams the persistent keyspace (or the given prefixes) to an NDJSON file.
+func exportCache(args []string) error {
+	var prefixes prefixFlag
+	var output string
+	var all, dryRun bool
+
+	flagSet := flag.NewFlagSet("cache export", flag.ContinueOnError)
+	flagSet.Var(&prefixes, "prefix", "Key prefix to export (repeatable)")
+	flagSet.StringVar(&output, "output", "", "File to write")
+	flagSet.StringVar(&output, "o", "", "File to write (shorthand)")
+	flagSet.BoolVar(&all, "all", false, "Export every key")
+	flagSet.BoolVar(&dryRun, "dry-run", false, "Count matching keys without writing")
+	flagSet.Usage = func() {
+		ui.PrintHeader("Cache Export Help")
+		ui.PrintInfo("Usage: dex cache export [-o file] [--prefix p]... [--all] [--dry-run]")
+		fmt.Println()
+		ui.PrintInfo("Description:")
+		ui.PrintInfo("  Streams keys to a portable NDJSON file with each key's type, TTL and value.")
+		ui.PrintInfo("  By default the persistent prefixes that survive 'dex build' and 'dex update' are exported.")
+		fmt.Println()
+		ui.PrintInfo("Flags:")
+		ui.PrintInfo("  -o, --output   File to write (default " + utils.BackupDir + "/redis-<time>.ndjson)")
+		ui.PrintInfo("  --prefix       Only export keys starting with this prefix (repeatable)")
+		ui.PrintInfo("  --all          Export every key in the database")
+		ui.PrintInfo("  --dry-run      Count the matching keys without writing a file")
+	}
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return fmt.Errorf("failed to parse flags for cache export: %w", err)
+	}
+
+	selected := []string(prefixes)
+	switch {
+	case all && len(selected) > 0:
+		return fmt.Errorf("--all and --prefix cannot be combined")
+	case all:
+		selected = []string{""}
+	case len(selected) == 0:
+		selected = utils.PersistentRedisPrefixes
+	}
+
+	ctx := context.Background()
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local cache client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	var stats *utils.RedisTransferStats
+	if dryRun {
+		stats, err = utils.ExportRedisKeys(ctx, client, selected, io.Discard, true)
+		if err != nil {
+			return err
+		}
+		ui.PrintInfo(fmt.Sprintf("Dry run: %d keys would be exported %s", stats.Keys, formatTypeCounts(stats.ByType)))
+	} else {
+		if output == "" {
+			backupDir, err := config.ExpandPath(utils.BackupDir)
+			if err != nil {
+				return err
+			}
+			output = filepath.Join(backupDir, fmt.Sprintf("redis-%s.ndjson", time.Now().Format("20060102-150405")))
+		}
+		if err := os.MkdirAll(filepath.Dir(output), 0755); err != nil {
+			return fmt.Errorf("failed to create output directory: %w", err)
+		}
+		file, err := os.OpenFile(output, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", output, err)
+		}
+		stats, err = utils.ExportRedisKeys(ctx, client, selected, file, false)
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(output)
+			return fmt.Errorf("export failed: %w", err)
+		}
+		ui.PrintSuccess(fmt.Sprintf("Exported %d keys %s to %s", stats.Keys, formatTypeCounts(stats.ByType), output))
+	}
+
+	// Keys that could not be exported are data the file does not carry, so fail loudly
+	if len(stats.Unsupported) > 0 {
+		ui.PrintWarning(fmt.Sprintf("%d keys have a type 'dex cache export' does not support and are not in the export:", len(stats.Unsupported)))
+		for _, key := range stats.Unsupported {
+			ui.PrintInfo("  " + key)
+		}
+		return fmt.Errorf("%d keys could not be exported", len(stats.Unsupported))
+	}
+	return nil
+}
+
+// importCache loads an NDJSON file written by 'dex cache export'.
+func importCache(args []string) error {
+	var replace, dryRun bool
+
+	flagSet := flag.NewFlagSet("cache import", flag.ContinueOnError)
+	flagSet.BoolVar(&replace, "replace", false, "Overwrite keys that already exist")
+	flagSet.BoolVar(&dryRun, "dry-run", false, "Validate the file without writing")
+	flagSet.Usage = func() {
+		ui.PrintHeader("Cache Import Help")
+		ui.PrintInfo("Usage: dex cache import [--replace] [--dry-run] <file>")
+		fmt.Println()
+		ui.PrintInfo("Flags:")
+		ui.PrintInfo("  --replace   Overwrite keys that already exist (default: skip them)")
+		ui.PrintInfo("  --dry-run   Validate the file and report what would change")
+	}
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return fmt.Errorf("failed to parse flags for cache import: %w", err)
+	}
+	if flagSet.NArg() != 1 {
+		flagSet.Usage()
+		return fmt.Errorf("cache import requires exactly one file")
+	}
+
+	file, err := os.Open(flagSet.Arg(0))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = file.Close() }()
+
+	ctx := context.Background()
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get local cache client: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	stats, err := utils.ImportRedisKeys(ctx, client, file, replace, dryRun)
+	if err != nil {
+		return err
+	}
+
+	verb := "Imported"
+	if dryRun {
+		verb = "Dry run: would import"
+	}
+	ui.PrintSuccess(fmt.Sprintf("%s %d keys %s", verb, stats.Keys, formatTypeCounts(stats.ByType)))
+	if stats.Skipped > 0 {
+		ui.PrintWarning(fmt.Sprintf("Skipped %d existing keys (use --replace to overwrite them)", stats.Skipped))
+	}
+	return nil
+}
+
+// formatTypeCounts renders per-type key counts, e.g. "(hash: 3, string: 12)".
+func formatTypeCounts(byType map[string]int) string {
+	if len(byType) == 0 {
+		return ""
+	}
+	types := make([]string, 0, len(byType))
+	for t := range byType {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+	parts := make([]string, 0, len(types))
+	for _, t := range types {
+		parts = append(parts, fmt.Sprintf("%s: %d", t, byType[t]))
+	}
+	return "(" + strings.Join(parts, ", ") + ")"
+}
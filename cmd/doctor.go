@@ -0,0 +1,540 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/health"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// doctorFinding is a problem 'dex doctor' found, with the fix it can apply
+type doctorFinding struct {
+	area    string // config, system, services or health
+	subject string
+	problem string
+	fix     string       // What apply does, shown before it runs
+	apply   func() error // Nil when the problem needs manual work
+	manual  string       // What to do by hand when there is no fix or it fails
+}
+
+// doctorOutcome is what happened to a finding once fixes were offered
+type doctorOutcome struct {
+	finding doctorFinding
+	status  string // fixed, failed, skipped or manual
+	err     error
+}
+
+// Doctor collects the problems found by 'dex verify', 'dex system validate' and the config
+// checks, and offers a fix for each one it knows how to repair
+func Doctor(args []string) error {
+	fix, yes := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--help", "-h":
+			printDoctorHelp()
+			return nil
+		case "--fix":
+			fix = true
+		case "--yes", "-y":
+			fix, yes = true, true
+		default:
+			return fmt.Errorf("unknown argument '%s'", arg)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	ui.PrintHeader("DEX DOCTOR")
+	flagged := make(map[string]bool)
+	var findings []doctorFinding
+	findings = append(findings, doctorConfigFindings()...)
+	findings = append(findings, doctorSystemFindings(ctx)...)
+	findings = append(findings, doctorServiceFindings(ctx, flagged)...)
+	findings = append(findings, doctorHealthFindings(ctx, flagged)...)
+
+	if len(findings) == 0 {
+		ui.PrintSuccess("No problems found.")
+		return nil
+	}
+
+	table := ui.NewTable([]string{"Area", "Subject", "Problem", "Fix"})
+	fixable := 0
+	for _, finding := range findings {
+		remedy := ui.Colorize("manual", ui.ColorYellow)
+		if finding.apply != nil {
+			remedy = finding.fix
+			fixable++
+		}
+		table.AddRow([]string{finding.area, finding.subject, finding.problem, remedy})
+	}
+	table.Render()
+	fmt.Println()
+
+	if !fix {
+		ui.PrintWarning(fmt.Sprintf("Found %d problems; %d can be fixed automatically.", len(findings), fixable))
+		if fixable > 0 {
+			ui.PrintInfo("Run 'dex doctor --fix' to choose fixes, or 'dex doctor --yes' to apply them all.")
+		}
+		printDoctorManual(findings)
+		return fmt.Errorf("%d problems found", len(findings))
+	}
+
+	interactive := isTerminal(os.Stdin)
+	if !yes && !interactive && fixable > 0 {
+		ui.PrintWarning("stdin is not a terminal; skipping fixes. Use --yes to apply them without asking.")
+	}
+
+	outcomes := make([]doctorOutcome, 0, len(findings))
+	for _, finding := range findings {
+		outcome := doctorOutcome{finding: finding, status: "manual"}
+		switch {
+		case finding.apply == nil:
+		case !yes && (!interactive || !confirm(fmt.Sprintf("%s: %s. %s?", finding.subject, finding.problem, finding.fix))):
+			outcome.status = "skipped"
+		default:
+			ui.PrintInfo(fmt.Sprintf("%s: %s...", finding.subject, finding.fix))
+			if err := finding.apply(); err != nil {
+				outcome.status = "failed"
+				outcome.err = err
+				ui.PrintError(fmt.Sprintf("  %v", err))
+				config.Log(fmt.Sprintf("dex doctor: failed to fix %s (%s): %v", finding.subject, finding.problem, err))
+			} else {
+				outcome.status = "fixed"
+				config.Log(fmt.Sprintf("dex doctor: fixed %s (%s): %s", finding.subject, finding.problem, finding.fix))
+			}
+		}
+		outcomes = append(outcomes, outcome)
+	}
+
+	return printDoctorSummary(outcomes)
+}
+
+// printDoctorSummary lists what was fixed and what is left, and fails when anything is left.
+func printDoctorSummary(outcomes []doctorOutcome) error {
+	fmt.Println()
+	ui.PrintHeader("DOCTOR SUMMARY")
+
+	byStatus := make(map[string][]doctorOutcome)
+	for _, outcome := range outcomes {
+		byStatus[outcome.status] = append(byStatus[outcome.status], outcome)
+	}
+
+	if fixed := byStatus["fixed"]; len(fixed) > 0 {
+		ui.PrintSuccess(fmt.Sprintf("Fixed %d problems:", len(fixed)))
+		for _, outcome := range fixed {
+			ui.PrintInfo(fmt.Sprintf("  ✓ %s: %s", outcome.finding.subject, outcome.finding.fix))
+		}
+	}
+	if failed := byStatus["failed"]; len(failed) > 0 {
+		ui.PrintError(fmt.Sprintf("%d fixes failed:", len(failed)))
+		for _, outcome := range failed {
+			ui.PrintInfo(fmt.Sprintf("  ✗ %s: %v", outcome.finding.subject, outcome.err))
+			if outcome.finding.manual != "" {
+				ui.PrintInfo(ui.Colorize("    → "+outcome.finding.manual, ui.ColorDarkGray))
+			}
+		}
+	}
+	if skipped := byStatus["skipped"]; len(skipped) > 0 {
+		ui.PrintWarning(fmt.Sprintf("%d fixes skipped:", len(skipped)))
+		for _, outcome := range skipped {
+			ui.PrintInfo(fmt.Sprintf("  - %s: %s", outcome.finding.subject, outcome.finding.fix))
+		}
+	}
+	var manual []doctorFinding
+	for _, outcome := range byStatus["manual"] {
+		manual = append(manual, outcome.finding)
+	}
+	printDoctorManual(manual)
+
+	remaining := len(outcomes) - len(byStatus["fixed"])
+	if remaining > 0 {
+		return fmt.Errorf("%d problems still need attention", remaining)
+	}
+	ui.PrintSuccess("All problems fixed.")
+	return nil
+}
+
+// printDoctorManual lists the findings that have no automatic fix.
+func printDoctorManual(findings []doctorFinding) {
+	var manual []doctorFinding
+	for _, finding := range findings {
+		if finding.apply == nil {
+			manual = append(manual, finding)
+		}
+	}
+	if len(manual) == 0 {
+		return
+	}
+	ui.PrintWarning(fmt.Sprintf("%d problems need manual work:", len(manual)))
+	for _, finding := range manual {
+		ui.PrintInfo(fmt.Sprintf("  • %s: %s", finding.subject, finding.problem))
+		if finding.manual != "" {
+			ui.PrintInfo(ui.Colorize("    → "+finding.manual, ui.ColorDarkGray))
+		}
+	}
+}
+
+// doctorConfigFindings checks that the config files exist, parse, and have not drifted
+// from the defaults.
+func doctorConfigFindings() []doctorFinding {
+	var findings []doctorFinding
+
+	serviceMap, err := config.LoadServiceMapConfig()
+	switch {
+	case os.IsNotExist(err):
+		findings = append(findings, doctorFinding{
+			area: "config", subject: "service-map.json", problem: "file is missing",
+			fix:   "create the default service map",
+			apply: func() error { return config.SaveServiceMapConfig(config.DefaultServiceMapConfig()) },
+		})
+	case err != nil:
+		findings = append(findings, doctorFinding{
+			area: "config", subject: "service-map.json", problem: err.Error(),
+			manual: "Fix the JSON in ~/Dexter/config/service-map.json, or run 'dex config reset'.",
+		})
+	default:
+		for _, drift := range config.FindServiceMapDrift(serviceMap) {
+			finding := doctorFinding{
+				area: "config", subject: "service-map.json", problem: fmt.Sprintf("%s: %s", drift.ID, drift.Problem),
+				manual: fmt.Sprintf("Edit or remove the '%s' entry in ~/Dexter/config/service-map.json.", drift.ID),
+			}
+			if drift.Fix != nil {
+				repair := drift.Fix
+				finding.fix = "repair the entry"
+				finding.apply = func() error {
+					// Each fix reloads the map so earlier fixes are kept
+					current, err := config.LoadServiceMapConfig()
+					if err != nil {
+						return fmt.Errorf("failed to load service-map.json: %w", err)
+					}
+					repair(current)
+					return config.SaveServiceMapConfig(current)
+				}
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	options, err := config.LoadOptionsConfig()
+	switch {
+	case os.IsNotExist(err):
+		findings = append(findings, doctorFinding{
+			area: "config", subject: "options.json", problem: "file is missing",
+			fix:   "create the default options",
+			apply: func() error { return config.SaveOptionsConfig(config.DefaultOptionsConfig()) },
+		})
+	case err != nil:
+		findings = append(findings, doctorFinding{
+			area: "config", subject: "options.json", problem: err.Error(),
+			manual: "Fix the JSON in ~/Dexter/config/options.json.",
+		})
+	case config.HealOptionsConfig(options):
+		findings = append(findings, doctorFinding{
+			area: "config", subject: "options.json", problem: "default values are missing",
+			fix: "add the missing defaults",
+			apply: func() error {
+				current, err := config.LoadOptionsConfig()
+				if err != nil {
+					return fmt.Errorf("failed to load options.json: %w", err)
+				}
+				config.HealOptionsConfig(current)
+				return config.SaveOptionsConfig(current)
+			},
+		})
+	}
+
+	if _, err := config.LoadServerMapConfig(); os.IsNotExist(err) {
+		findings = append(findings, doctorFinding{
+			area: "config", subject: "server-map.json", problem: "file is missing",
+			fix:   "create the default server map",
+			apply: func() error { return config.SaveServerMapConfig(config.DefaultServerMapConfig()) },
+		})
+	} else if err != nil {
+		findings = append(findings, doctorFinding{
+			area: "config", subject: "server-map.json", problem: err.Error(),
+			manual: "Fix the JSON in ~/Dexter/config/server-map.json.",
+		})
+	}
+	return findings
+}
+
+// doctorSystemFindings checks the required packages and Dexter's Python environments.
+func doctorSystemFindings(ctx context.Context) []doctorFinding {
+	var findings []doctorFinding
+
+	sys, err := config.LoadSystemConfig()
+	if err != nil {
+		findings = append(findings, doctorFinding{
+			area: "system", subject: "packages", problem: fmt.Sprintf("failed to scan the system: %v", err),
+			manual: "Run 'dex system scan' for details.",
+		})
+	} else {
+		for _, pkg := range sys.Packages {
+			if !pkg.Required || pkg.Installed {
+				continue
+			}
+			finding := doctorFinding{
+				area: "system", subject: pkg.Name, problem: fmt.Sprintf("required package is missing (>= %s)", pkg.MinVersion),
+				manual: fmt.Sprintf("Install %s %s or later with your package manager.", pkg.Name, pkg.MinVersion),
+			}
+			if pkg.InstallCommand != "" {
+				finding.fix = fmt.Sprintf("run '%s'", pkg.InstallCommand)
+				finding.apply = func() error { return installPackage(pkg, config.Log) }
+			}
+			findings = append(findings, finding)
+		}
+	}
+
+	var venvProblems []string
+	for _, version := range []string{"3.14", "3.10"} {
+		if err := utils.CheckPythonVersion(version); err != nil {
+			venvProblems = append(venvProblems, err.Error())
+		} else if err := utils.CheckPoetryInstalled(version); err != nil {
+			venvProblems = append(venvProblems, err.Error())
+		}
+	}
+	if len(venvProblems) > 0 {
+		findings = append(findings, doctorFinding{
+			area: "system", subject: "python venv", problem: strings.Join(venvProblems, "; "),
+			fix:    "recreate Dexter's Python environments",
+			apply:  func() error { return utils.EnsurePythonVenv(false) },
+			manual: "Install python3.14 and python3.10, then run 'dex doctor --fix' again.",
+		})
+	}
+
+	services, err := utils.GetConfiguredServices()
+	if err != nil {
+		// An invalid service map is reported with the config findings
+		return findings
+	}
+	for _, s := range services {
+		if !s.IsManageable() || !utils.IsPythonService(s) {
+			continue
+		}
+		sourcePath, err := config.ExpandPath(s.Source)
+		if err != nil {
+			continue
+		}
+		if _, err := os.Stat(sourcePath); err != nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(sourcePath, "venv", "bin", "python")); err == nil {
+			continue
+		}
+		findings = append(findings, doctorFinding{
+			area: "system", subject: s.ShortName, problem: fmt.Sprintf("Python venv is missing in %s", s.Source),
+			fix: "create the venv and install requirements",
+			apply: func() error {
+				return utils.SetupPythonService(ctx, s, config.Log, os.Stdout)
+			},
+			manual: fmt.Sprintf("Run 'dex build %s --force'.", s.ShortName),
+		})
+	}
+	return findings
+}
+
+// doctorServiceFindings checks each service's systemd unit, log file and port. Services with
+// a finding are flagged so the health checks do not report them twice.
+func doctorServiceFindings(ctx context.Context, flagged map[string]bool) []doctorFinding {
+	services, err := utils.GetConfiguredServices()
+	if err != nil {
+		return nil
+	}
+
+	var findings []doctorFinding
+	for _, s := range services {
+		if !s.IsManageable() || s.SystemdName == "" {
+			continue
+		}
+
+		execStart, _ := utils.SystemdExecStart(s)
+		executable := strings.Fields(execStart)[0]
+		_, statErr := os.Stat(executable)
+		canInstall := statErr == nil
+		reinstall := func() error { return utils.InstallSystemdService(s) }
+		missingExecutable := fmt.Sprintf("Run 'dex build %s' so %s exists, then 'dex doctor --fix'.", s.ShortName, executable)
+
+		unitPath, _ := config.ExpandPath(s.GetSystemdPath())
+		current, err := unitExecStart(unitPath)
+		switch {
+		case os.IsNotExist(err):
+			flagged[s.ShortName] = true
+			finding := doctorFinding{
+				area: "services", subject: s.ShortName, problem: "systemd unit is not installed",
+				manual: missingExecutable,
+			}
+			if canInstall {
+				finding.fix = "install and start the unit"
+				finding.apply = reinstall
+			}
+			findings = append(findings, finding)
+		case err != nil:
+			flagged[s.ShortName] = true
+			findings = append(findings, doctorFinding{
+				area: "services", subject: s.ShortName, problem: err.Error(),
+				fix: "regenerate the unit", apply: reinstall,
+			})
+		default:
+			if _, err := os.Stat(current); err != nil {
+				flagged[s.ShortName] = true
+				finding := doctorFinding{
+					area: "services", subject: s.ShortName, problem: fmt.Sprintf("stale unit: ExecStart %s does not exist", current),
+					manual: missingExecutable,
+				}
+				if canInstall {
+					finding.fix = fmt.Sprintf("regenerate the unit for %s", executable)
+					finding.apply = reinstall
+				}
+				findings = append(findings, finding)
+			}
+		}
+
+		if logPath, err := config.ExpandPath(s.GetLogPath()); err == nil {
+			if problem := logFileProblem(logPath); problem != "" {
+				findings = append(findings, doctorFinding{
+					area: "services", subject: s.ShortName, problem: problem,
+					fix:    "create the log file and make it writable",
+					apply:  func() error { return repairLogFile(logPath) },
+					manual: fmt.Sprintf("Run 'sudo chown $USER %s'.", logPath),
+				})
+			}
+		}
+
+		if holder, _ := utils.ServicePortConflict(s); holder != nil {
+			flagged[s.ShortName] = true
+			findings = append(findings, doctorFinding{
+				area: "services", subject: s.ShortName, problem: fmt.Sprintf("port %s is held by %s", s.Port, holder),
+				fix: "move it to a free port and restart it",
+				apply: func() error {
+					moved, err := utils.ReassignServicePort(s)
+					if err != nil {
+						return err
+					}
+					ui.PrintInfo(fmt.Sprintf("  %s now uses port %s", s.ShortName, moved.Port))
+					return utils.RestartAndVerify(ctx, moved)
+				},
+				manual: fmt.Sprintf("Stop %s, or change the port of %s in service-map.json.", holder, s.ShortName),
+			})
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	return findings
+}
+
+// doctorHealthFindings runs the infrastructure and service checks of 'dex verify'. An
+// unhealthy service can be restarted unless an earlier finding explains it.
+func doctorHealthFindings(ctx context.Context, flagged map[string]bool) []doctorFinding {
+	services, err := utils.GetConfiguredServices()
+	if err != nil {
+		return nil
+	}
+	byName := make(map[string]config.ServiceDefinition)
+	for _, s := range services {
+		byName[s.ShortName] = s
+	}
+
+	registry := &health.Registry{}
+	registerVerifyChecks(registry, services)
+	checks, err := registry.Select([]string{health.CategoryInfrastructure, health.CategoryServices})
+	if err != nil {
+		return nil
+	}
+
+	var findings []doctorFinding
+	for _, check := range checks {
+		result := health.RunCheck(ctx, check)
+		if result.Status != health.StatusFailed || flagged[result.Name] {
+			continue
+		}
+		finding := doctorFinding{
+			area: "health", subject: result.Name, problem: result.Message,
+			manual: result.Remediation,
+		}
+		if s, ok := byName[result.Name]; ok && s.SystemdName != "" {
+			finding.fix = "restart it and wait until healthy"
+			finding.apply = func() error { return utils.RestartAndVerify(ctx, s) }
+		}
+		findings = append(findings, finding)
+	}
+	return findings
+}
+
+// unitExecStart returns the executable a systemd unit file starts.
+func unitExecStart(unitPath string) (string, error) {
+	data, err := os.ReadFile(unitPath)
+	if err != nil {
+		return "", err
+	}
+	for _, line := range strings.Split(string(data), "\n") {
+		value, ok := strings.CutPrefix(strings.TrimSpace(line), "ExecStart=")
+		if !ok {
+			continue
+		}
+		// Drop systemd's executable prefixes such as '-' (ignore failure) or '@'
+		fields := strings.Fields(strings.TrimLeft(value, "-@:+!"))
+		if len(fields) > 0 {
+			return fields[0], nil
+		}
+	}
+	return "", errors.New("systemd unit has no ExecStart")
+}
+
+// logFileProblem describes why a service cannot append to its log file, if it cannot.
+func logFileProblem(logPath string) string {
+	file, err := os.OpenFile(logPath, os.O_WRONLY|os.O_APPEND, 0)
+	switch {
+	case os.IsNotExist(err):
+		return fmt.Sprintf("log file %s is missing", logPath)
+	case err != nil:
+		return fmt.Sprintf("log file %s is not writable", logPath)
+	}
+	_ = file.Close()
+	return ""
+}
+
+// repairLogFile creates a log file or gives its owner write access again.
+func repairLogFile(logPath string) error {
+	if err := os.MkdirAll(filepath.Dir(logPath), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+	if _, err := os.Stat(logPath); os.IsNotExist(err) {
+		file, err := os.Create(logPath)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", logPath, err)
+		}
+		return file.Close()
+	}
+	if err := os.Chmod(logPath, 0644); err != nil {
+		return fmt.Errorf("failed to make %s writable: %w", logPath, err)
+	}
+	if problem := logFileProblem(logPath); problem != "" {
+		return errors.New(problem)
+	}
+	return nil
+}
+
+func printDoctorHelp() {
+	ui.PrintHeader("Doctor Command Help")
+	ui.PrintInfo("Usage: dex doctor [--fix] [-y|--yes]")
+	fmt.Println()
+	ui.PrintInfo("Finds problems with config files, system packages, Python environments,")
+	ui.PrintInfo("systemd units, log files, ports and service health, and fixes what it can.")
+	fmt.Println()
+	ui.PrintInfo("Flags:")
+	ui.PrintInfo("  --fix        Offer each available fix and apply the ones you accept.")
+	ui.PrintInfo("  -y, --yes    Apply every available fix without asking (implies --fix).")
+	fmt.Println()
+	ui.PrintInfo("Without flags, problems are only reported. The command fails while any remain.")
+}
@@ -0,0 +1,392 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"os/signal"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/health"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+const openMetricsContentType = "application/openmetrics-text; version=1.0.0; charset=utf-8"
+
+// Metrics handles the 'metrics' command and its subcommands.
+func Metrics(args []string) error {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		ui.PrintHeader("Metrics Command Help")
+		ui.PrintInfo("Usage: dex metrics serve [--port 9464] [--interval 15s]")
+		fmt.Println()
+		ui.PrintInfo("Description:")
+		ui.PrintInfo("  Scrapes every configured service's health report and systemd stats on an")
+		ui.PrintInfo("  interval and exposes them in OpenMetrics text format on /metrics.")
+		return nil
+	}
+
+	switch args[0] {
+	case "serve":
+		return serveMetrics(args[1:])
+	default:
+		return fmt.Errorf("unknown metrics subcommand: %s", args[0])
+	}
+}
+
+func serveMetrics(args []string) error {
+	var port int
+	var interval time.Duration
+
+	flagSet := flag.NewFlagSet("metrics serve", flag.ContinueOnError)
+	flagSet.IntVar(&port, "port", 9464, "Port to listen on")
+	flagSet.IntVar(&port, "p", 9464, "Port to listen on (shorthand)")
+	flagSet.DurationVar(&interval, "interval", 15*time.Second, "How often services are scraped")
+	flagSet.Usage = func() {
+		ui.PrintHeader("Metrics Serve Help")
+		ui.PrintInfo("Usage: dex metrics serve [--port 9464] [--interval 15s]")
+		fmt.Println()
+		ui.PrintInfo("Flags:")
+		ui.PrintInfo("  -p, --port   Port to listen on (default 9464)")
+		ui.PrintInfo("  --interval   How often services are scraped (default 15s)")
+	}
+
+	if err := flagSet.Parse(args); err != nil {
+		if err == flag.ErrHelp {
+			return nil
+		}
+		return fmt.Errorf("failed to parse flags for metrics serve: %w", err)
+	}
+	if port < 1 || port > 65535 {
+		return fmt.Errorf("invalid port: %d", port)
+	}
+	if interval < time.Second {
+		return fmt.Errorf("interval must be at least 1s, got %s", interval)
+	}
+
+	services, err := utils.GetConfiguredServices()
+	if err != nil {
+		return fmt.Errorf("failed to get configured services: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// The exporter runs indefinitely; don't accumulate its log output in the event capture buffer
+	ui.StopCapturing()
+
+	exporter := &metricsExporter{services: services}
+	exporter.scrape(ctx)
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				exporter.scrape(ctx)
+			}
+		}
+	}()
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", openMetricsContentType)
+		_, _ = w.Write(exporter.snapshot())
+	})
+	mux.HandleFunc("/", func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = fmt.Fprintln(w, "dex metrics exporter: scrape /metrics")
+	})
+
+	addr := fmt.Sprintf(":%d", port)
+	srv := &http.Server{
+		Addr:         addr,
+		Handler:      mux,
+		ReadTimeout:  10 * time.Second,
+		WriteTimeout: 10 * time.Second,
+		IdleTimeout:  30 * time.Second,
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdownCtx)
+	}()
+
+	ui.PrintInfo(fmt.Sprintf("Exporting metrics for %d services on http://localhost%s/metrics (every %s)", len(services), addr, interval))
+	if err := srv.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		return fmt.Errorf("metrics server failed: %w", err)
+	}
+
+	fmt.Println()
+	ui.PrintInfo("Metrics exporter stopped.")
+	return nil
+}
+
+// metricsExporter scrapes services on demand and holds the last rendered exposition.
+type metricsExporter struct {
+	services []config.ServiceDefinition
+
+	mu     sync.RWMutex
+	latest []byte
+}
+
+func (e *metricsExporter) snapshot() []byte {
+	e.mu.RLock()
+	defer e.mu.RUnlock()
+	return e.latest
+}
+
+// scrape checks every service and replaces the exposition served on /metrics.
+func (e *metricsExporter) scrape(ctx context.Context) {
+	start := time.Now()
+	statuses := runStatusChecks(ctx, e.services, statusCheckTimeout)
+
+	var w openMetricsWriter
+	for i, service := range e.services {
+		status := statuses[i]
+		labels := metricLabels(status)
+
+		up := 0.0
+		if status.Status == "OK" || status.Status == "HEALTHY" {
+			up = 1
+		}
+		w.add("dex_service_up", "gauge", "Whether the service passed its health check (1) or not (0).", labels, up)
+		timedOut := 0.0
+		if status.Status == "TIMEOUT" {
+			timedOut = 1
+		}
+		w.add("dex_service_check_timeout", "gauge", "Whether the last health check timed out.", labels, timedOut)
+
+		if len(status.Report) > 0 {
+			writeReportMetrics(&w, labels, status.Report)
+		}
+		if stats, ok := readSystemdStats(service); ok {
+			writeSystemdMetrics(&w, labels, stats)
+		}
+	}
+	w.add("dex_exporter_scrape_duration_seconds", "gauge", "Time taken to scrape every service.", "", time.Since(start).Seconds())
+	w.add("dex_exporter_last_scrape_timestamp_seconds", "gauge", "Unix time of the last completed scrape.", "", float64(time.Now().Unix()))
+
+	e.mu.Lock()
+	e.latest = w.bytes()
+	e.mu.Unlock()
+}
+
+// metricLabels builds the service/type/version label set shared by every metric of a service.
+func metricLabels(status ServiceStatus) string {
+	version := status.Version
+	if version == "N/A" {
+		version = ""
+	}
+	return fmt.Sprintf(`service="%s",type="%s",version="%s"`, escapeLabel(status.Service), escapeLabel(status.Type), escapeLabel(version))
+}
+
+// writeReportMetrics exports the numbers found in a service's /service report.
+func writeReportMetrics(w *openMetricsWriter, labels string, raw json.RawMessage) {
+	var report struct {
+		Health struct {
+			Uptime  json.RawMessage `json:"uptime"`
+			Metrics health.Metrics  `json:"metrics"`
+		} `json:"health"`
+		Metrics struct {
+			CPU struct {
+				Avg *float64 `json:"avg"`
+			} `json:"cpu"`
+			Memory struct {
+				Avg *float64 `json:"avg"`
+			} `json:"memory"`
+		} `json:"metrics"`
+	}
+	if err := json.Unmarshal(raw, &report); err != nil {
+		return
+	}
+
+	if uptime, ok := parseReportUptime(report.Health.Uptime); ok {
+		w.add("dex_service_uptime_seconds", "gauge", "Uptime reported by the service.", labels, uptime)
+	}
+	if report.Metrics.CPU.Avg != nil {
+		w.add("dex_service_cpu_percent", "gauge", "Average CPU usage reported by the service.", labels, *report.Metrics.CPU.Avg)
+	}
+	if report.Metrics.Memory.Avg != nil {
+		w.add("dex_service_memory_megabytes", "gauge", "Average memory usage reported by the service.", labels, *report.Metrics.Memory.Avg)
+	}
+
+	m := report.Health.Metrics
+	if m.Goroutines > 0 {
+		w.add("dex_service_goroutines", "gauge", "Goroutines running in the service.", labels, float64(m.Goroutines))
+	}
+	if m.MemoryAllocMB > 0 {
+		w.add("dex_service_memory_alloc_megabytes", "gauge", "Heap memory allocated by the service.", labels, m.MemoryAllocMB)
+	}
+	if m.EventsReceived > 0 || m.EventsProcessed > 0 {
+		w.add("dex_service_events_received", "counter", "Events received by the service.", labels, float64(m.EventsReceived))
+		w.add("dex_service_events_processed", "counter", "Events processed by the service.", labels, float64(m.EventsProcessed))
+	}
+}
+
+// parseReportUptime accepts uptime as seconds or as a Go duration string ("1h2m3s").
+func parseReportUptime(raw json.RawMessage) (float64, bool) {
+	if len(raw) == 0 {
+		return 0, false
+	}
+	var seconds float64
+	if err := json.Unmarshal(raw, &seconds); err == nil {
+		return seconds, true
+	}
+	var text string
+	if err := json.Unmarshal(raw, &text); err != nil {
+		return 0, false
+	}
+	duration, err := time.ParseDuration(text)
+	if err != nil {
+		return 0, false
+	}
+	return duration.Seconds(), true
+}
+
+// systemdStats holds the raw counters systemd keeps for a unit.
+type systemdStats struct {
+	Active      bool
+	Started     time.Time
+	CPUNanos    int64
+	MemoryBytes int64
+	Restarts    int64
+}
+
+// readSystemdStats reads a service's unit properties. Dex services run as user units;
+// local Redis and Ollama run as system units, matching how 'dex status' inspects them.
+func readSystemdStats(service config.ServiceDefinition) (systemdStats, bool) {
+	var units []string
+	userUnit := false
+	switch {
+	case service.Type == "os" && isLocalAddress(service.Domain):
+		if strings.Contains(strings.ToLower(service.ID), "ollama") {
+			units = []string{"ollama"}
+		} else {
+			units = []string{"redis", "redis-server"}
+		}
+	case service.SystemdName != "":
+		units = []string{service.SystemdName}
+		userUnit = true
+	default:
+		return systemdStats{}, false
+	}
+
+	for _, unit := range units {
+		args := []string{"show", unit, "--property=ActiveState,ActiveEnterTimestamp,CPUUsageNSec,MemoryCurrent,NRestarts"}
+		if userUnit {
+			args = append([]string{"--user"}, args...)
+		}
+		output, err := exec.Command("systemctl", args...).Output()
+		if err != nil {
+			continue
+		}
+
+		var stats systemdStats
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			key, value, _ := strings.Cut(line, "=")
+			switch key {
+			case "ActiveState":
+				stats.Active = value == "active"
+			case "ActiveEnterTimestamp":
+				stats.Started, _ = time.Parse("Mon 2006-01-02 15:04:05 MST", value)
+			case "CPUUsageNSec":
+				stats.CPUNanos, _ = strconv.ParseInt(value, 10, 64)
+			case "MemoryCurrent":
+				stats.MemoryBytes, _ = strconv.ParseInt(value, 10, 64)
+			case "NRestarts":
+				stats.Restarts, _ = strconv.ParseInt(value, 10, 64)
+			}
+		}
+		if stats.Active {
+			return stats, true
+		}
+	}
+	return systemdStats{}, false
+}
+
+func writeSystemdMetrics(w *openMetricsWriter, labels string, stats systemdStats) {
+	if !stats.Started.IsZero() {
+		w.add("dex_systemd_uptime_seconds", "gauge", "Time since systemd last started the unit.", labels, time.Since(stats.Started).Seconds())
+	}
+	if stats.CPUNanos > 0 {
+		w.add("dex_systemd_cpu_seconds", "counter", "CPU time consumed by the unit.", labels, float64(stats.CPUNanos)/1e9)
+	}
+	if stats.MemoryBytes > 0 {
+		w.add("dex_systemd_memory_bytes", "gauge", "Memory currently used by the unit.", labels, float64(stats.MemoryBytes))
+	}
+	w.add("dex_systemd_restarts", "counter", "Times systemd has restarted the unit.", labels, float64(stats.Restarts))
+}
+
+// openMetricsWriter groups samples into metric families and renders OpenMetrics text.
+type openMetricsWriter struct {
+	families map[string]*metricFamily
+}
+
+type metricFamily struct {
+	typ     string
+	help    string
+	samples []string
+}
+
+func (w *openMetricsWriter) add(name, typ, help, labels string, value float64) {
+	if w.families == nil {
+		w.families = make(map[string]*metricFamily)
+	}
+	family, ok := w.families[name]
+	if !ok {
+		family = &metricFamily{typ: typ, help: help}
+		w.families[name] = family
+	}
+
+	sampleName := name
+	if typ == "counter" {
+		sampleName += "_total"
+	}
+	if labels != "" {
+		sampleName += "{" + labels + "}"
+	}
+	family.samples = append(family.samples, sampleName+" "+strconv.FormatFloat(value, 'g', -1, 64))
+}
+
+func (w *openMetricsWriter) bytes() []byte {
+	names := make([]string, 0, len(w.families))
+	for name := range w.families {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var b strings.Builder
+	for _, name := range names {
+		family := w.families[name]
+		fmt.Fprintf(&b, "# TYPE %s %s\n", name, family.typ)
+		fmt.Fprintf(&b, "# HELP %s %s\n", name, family.help)
+		for _, sample := range family.samples {
+			b.WriteString(sample + "\n")
+		}
+	}
+	b.WriteString("# EOF\n")
+	return []byte(b.String())
+}
+
+// escapeLabel escapes a label value per the OpenMetrics text format.
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
@@ -0,0 +1,248 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/release"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// Release inspects and maintains what is published to easter.company.
+func Release(args []string) error {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		ui.PrintHeader("Release Command Help")
+		ui.PrintInfo("Usage: dex release <list|show|gc|keygen>")
+		fmt.Println()
+		ui.PrintInfo("Subcommands:")
+		ui.PrintInfo("  list                  List published releases with their channels and size.")
+		ui.PrintInfo("  show <version>        Show a release's binaries for every platform.")
+		ui.PrintInfo("  gc [--dry-run]        Remove releases outside the retention policy and")
+		ui.PrintInfo("                        release directories data.json no longer lists.")
+		ui.PrintInfo("  keygen                Create or rotate the key that signs data.json.")
+		fmt.Println()
+		ui.PrintInfo("Retention:")
+		ui.PrintInfo("  Set release.retention.keep_minors and keep_majors in options.json.")
+		ui.PrintInfo(fmt.Sprintf("  Default: keep %s.", release.DefaultRetentionPolicy))
+		ui.PrintInfo("  The latest release on each channel is always kept.")
+		fmt.Println()
+		ui.PrintInfo("Rotating keys:")
+		ui.PrintInfo("  1. Run 'dex release keygen' and publish a release built with both keys trusted.")
+		ui.PrintInfo("  2. Once users have updated, remove the old key from trusted_keys.txt.")
+		return nil
+	}
+
+	switch args[0] {
+	case "list":
+		return releaseList()
+	case "show":
+		if len(args) != 2 {
+			return fmt.Errorf("usage: dex release show <version>")
+		}
+		return releaseShow(args[1])
+	case "gc":
+		return releaseGC(args[1:])
+	case "keygen":
+		return releaseKeygen()
+	default:
+		return fmt.Errorf("unknown release subcommand: %s", args[0])
+	}
+}
+
+// loadPublishedReleases reads data.json from the local easter.company checkout when there is
+// one, so unpushed changes are visible, and from easter.company otherwise.
+func loadPublishedReleases() (*release.ReleaseData, error) {
+	if repoPath, err := release.EasterCompanyRepoPath(); err == nil {
+		return release.LoadReleaseData(filepath.Join(repoPath, release.DataJSONPath))
+	}
+	return fetchReleaseData(false)
+}
+
+func releaseList() error {
+	data, err := loadPublishedReleases()
+	if err != nil {
+		return err
+	}
+	if len(data.Releases) == 0 {
+		ui.PrintInfo("No releases published.")
+		return nil
+	}
+
+	table := ui.NewTable([]string{"VERSION", "TYPE", "DATE", "CHANNELS", "PLATFORMS", "SIZE"})
+	for _, version := range data.SortedVersions() {
+		info := data.Releases[version]
+		channels := strings.Join(data.ChannelsFor(version), ", ")
+		if channels == "" {
+			channels = ui.Colorize("-", ui.ColorDarkGray)
+		} else {
+			channels = ui.Colorize(channels, ui.ColorGreen)
+		}
+		table.AddRow(ui.TableRow{
+			version,
+			info.Type,
+			formatReleaseDate(info.Date),
+			channels,
+			strings.Join(releasePlatforms(info), ", "),
+			utils.FormatBytes(info.Size()),
+		})
+	}
+	table.Render()
+	return nil
+}
+
+func releaseShow(version string) error {
+	data, err := loadPublishedReleases()
+	if err != nil {
+		return err
+	}
+	info, exists := data.Releases[version]
+	if !exists {
+		return fmt.Errorf("release %s not found in data.json (available: %s)", version, strings.Join(data.SortedVersions(), ", "))
+	}
+
+	channels := strings.Join(data.ChannelsFor(version), ", ")
+	if channels == "" {
+		channels = "none"
+	}
+	ui.PrintKeyValBlock("release "+version, []ui.KeyVal{
+		{Key: "Type", Value: info.Type},
+		{Key: "Date", Value: formatReleaseDate(info.Date)},
+		{Key: "Commit", Value: info.Commit},
+		{Key: "Channels", Value: channels},
+		{Key: "Size", Value: utils.FormatBytes(info.Size())},
+	})
+
+	table := ui.NewTable([]string{"SERVICE", "PLATFORM", "SIZE", "CHECKSUM", "PATH"})
+	for _, service := range sortedKeys(info.Binaries) {
+		for _, platform := range sortedKeys(info.Binaries[service]) {
+			binary := info.Binaries[service][platform]
+			table.AddRow(ui.TableRow{service, platform, utils.FormatBytes(binary.Size), shortChecksum(binary.Checksum), binary.Path})
+		}
+	}
+	table.Render()
+
+	for _, service := range sortedKeys(info.Changelog) {
+		changelog := info.Changelog[service]
+		fmt.Println()
+		if changelog.From != "" {
+			ui.PrintSubHeader(fmt.Sprintf("%s changes since %s", service, changelog.From))
+		} else {
+			ui.PrintSubHeader(fmt.Sprintf("%s (first release)", service))
+		}
+		if changelog.Summary != "" {
+			ui.PrintInfo(changelog.Summary)
+		}
+		for _, group := range changelog.Groups {
+			ui.PrintInfo(ui.Colorize(group.Title, ui.ColorCyan))
+			for _, entry := range group.Entries {
+				ui.PrintInfo("  - " + strings.ReplaceAll(entry, "**", ""))
+			}
+		}
+	}
+	return nil
+}
+
+func releaseGC(args []string) error {
+	dryRun := false
+	for _, arg := range args {
+		switch arg {
+		case "--dry-run", "-n":
+			dryRun = true
+		default:
+			return fmt.Errorf("unknown flag for release gc: %s", arg)
+		}
+	}
+
+	policy := release.LoadRetentionPolicy()
+	ui.PrintInfo(fmt.Sprintf("Retention policy: keep %s.", policy))
+
+	report, err := release.CollectGarbage(policy, dryRun)
+	if report == nil {
+		return err
+	}
+
+	if len(report.Expired) == 0 && len(report.Orphans) == 0 {
+		ui.PrintSuccess("Nothing to collect.")
+		return err
+	}
+	table := ui.NewTable([]string{"VERSION", "REASON"})
+	for _, version := range report.Expired {
+		table.AddRow(ui.TableRow{version, "outside retention policy"})
+	}
+	for _, version := range report.Orphans {
+		table.AddRow(ui.TableRow{version, "not listed in data.json"})
+	}
+	table.Render()
+
+	if dryRun {
+		ui.PrintInfo(fmt.Sprintf("%d releases would be removed, freeing %s. No changes made (--dry-run).",
+			len(report.Expired)+len(report.Orphans), utils.FormatBytes(report.Freed)))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	ui.PrintSuccess(fmt.Sprintf("Removed %d releases, freeing %s.", len(report.Expired)+len(report.Orphans), utils.FormatBytes(report.Freed)))
+	return nil
+}
+
+// releasePlatforms lists every platform a release has a binary for.
+func releasePlatforms(info release.ReleaseInfo) []string {
+	platforms := make(map[string]bool)
+	for _, binaries := range info.Binaries {
+		for platform := range binaries {
+			platforms[platform] = true
+		}
+	}
+	return sortedKeys(platforms)
+}
+
+// formatReleaseDate shortens a data.json RFC 3339 date for display.
+func formatReleaseDate(date string) string {
+	if t, err := time.Parse(time.RFC3339, date); err == nil {
+		return t.Local().Format("2006-01-02 15:04")
+	}
+	return date
+}
+
+func releaseKeygen() error {
+	publicKey, archived, err := release.GenerateSigningKey(time.Now().Format("20060102-150405"))
+	if err != nil {
+		return fmt.Errorf("failed to generate signing key: %w", err)
+	}
+	if archived != "" {
+		ui.PrintInfo(fmt.Sprintf("Previous key moved to %s", archived))
+	}
+	encoded := release.EncodePublicKey(publicKey)
+	ui.PrintSuccess("Generated a new release signing key.")
+	ui.PrintKeyValBlock("release key", []ui.KeyVal{
+		{Key: "Private", Value: release.SigningKeyPath},
+		{Key: "Public", Value: encoded},
+	})
+
+	trustedPath, err := config.ExpandPath(release.TrustedKeysSource)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(trustedPath); err != nil {
+		ui.PrintWarning(fmt.Sprintf("%s not found; add the public key to release/trusted_keys.txt by hand.", release.TrustedKeysSource))
+		return nil
+	}
+	line := fmt.Sprintf("%s added %s\n", encoded, time.Now().Format("2006-01-02"))
+	file, err := os.OpenFile(trustedPath, os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to update trusted keys: %w", err)
+	}
+	defer func() { _ = file.Close() }()
+	if _, err := file.WriteString(line); err != nil {
+		return fmt.Errorf("failed to update trusted keys: %w", err)
+	}
+	ui.PrintSuccess(fmt.Sprintf("Added the public key to %s", release.TrustedKeysSource))
+	ui.PrintInfo("Commit trusted_keys.txt and rebuild dex so it trusts the new key.")
+	return nil
+}
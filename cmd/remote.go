@@ -0,0 +1,206 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"slices"
+	"strconv"
+	"strings"
+	"syscall"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/remote"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// remoteCommands are the commands that may be run on a server from server-map.json.
+var remoteCommands = []string{"status", "logs", "start", "stop", "restart", "update", "version"}
+
+// SupportsRemote reports whether a command may be run on a server with --host.
+func SupportsRemote(command string) bool {
+	return slices.Contains(remoteCommands, command)
+}
+
+// Remote runs a dex command on a server from server-map.json over SSH.
+func Remote(args []string) error {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		ui.PrintHeader("Remote Command Help")
+		ui.PrintInfo("Usage: dex remote <host> <command> [args...]")
+		ui.PrintInfo("       dex remote <host> forward [service|port|local:remote]...")
+		ui.PrintInfo("       dex remote list")
+		ui.PrintInfo("       dex <command> --host <host> [args...]")
+		fmt.Println()
+		ui.PrintInfo("Description:")
+		ui.PrintInfo("  Runs a dex command on a server from server-map.json over SSH, using the")
+		ui.PrintInfo("  server's user and key. Output is streamed back as the remote dex prints it.")
+		ui.PrintInfo("  Host keys are pinned on first use in " + remote.KnownHostsFile + ".")
+		fmt.Println()
+		ui.PrintInfo("  'forward' tunnels the server's loopback-bound service ports to this machine")
+		ui.PrintInfo("  until Ctrl+C, so local commands such as 'dex status' can reach them.")
+		fmt.Println()
+		ui.PrintInfo("Commands: " + strings.Join(remoteCommands, ", "))
+		return nil
+	}
+
+	if args[0] == "list" {
+		return listRemoteHosts()
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("remote requires a host and a command (e.g. dex remote %s status)", args[0])
+	}
+
+	host, err := utils.FindRemoteHost(args[0])
+	if err != nil {
+		return err
+	}
+	command := args[1]
+	if command == "forward" {
+		return forwardRemotePorts(host, args[2:])
+	}
+	if !SupportsRemote(command) {
+		return fmt.Errorf("'%s' cannot be run remotely (supported: %s)", command, strings.Join(remoteCommands, ", "))
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// stdout carries the remote output alone, so --json and --format stay parseable
+	_, _ = fmt.Fprintf(os.Stderr, "%sRunning 'dex %s' on %s...%s\n", ui.ColorCyan, strings.Join(args[1:], " "), host.Name, ui.ColorReset)
+	return utils.RunRemoteDex(ctx, host, args[1:], os.Stdout, os.Stderr, isTerminal(os.Stdout))
+}
+
+// forwardRemotePorts tunnels ports bound to the server's loopback interface to this machine.
+// With no specs, every manageable service's port is forwarded.
+func forwardRemotePorts(host utils.RemoteHost, specs []string) error {
+	configuredServices, err := utils.GetConfiguredServices()
+	if err != nil {
+		return fmt.Errorf("failed to get configured services: %w", err)
+	}
+	if len(specs) == 0 {
+		for _, service := range configuredServices {
+			if service.IsManageable() && service.Port != "" {
+				specs = append(specs, service.ShortName)
+			}
+		}
+	}
+	if len(specs) == 0 {
+		return fmt.Errorf("no ports to forward")
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	client, err := utils.DialRemoteHost(ctx, host)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	table := ui.NewTable([]string{"NAME", "LOCAL", "REMOTE"})
+	for _, spec := range specs {
+		name, localPort, remotePort, err := parseForwardSpec(spec, configuredServices)
+		if err != nil {
+			return err
+		}
+		// Keep the same port when it is free, so local commands find the service where they expect it
+		if !utils.IsPortAvailable("127.0.0.1", localPort) {
+			ui.PrintWarning(fmt.Sprintf("Port %s is in use locally; forwarding %s on a random port instead.", localPort, name))
+			localPort = "0"
+		}
+		forward, err := client.Forward(net.JoinHostPort("127.0.0.1", localPort), net.JoinHostPort("127.0.0.1", remotePort))
+		if err != nil {
+			return err
+		}
+		defer func() { _ = forward.Close() }()
+		table.AddRow(ui.TableRow{name, forward.Local, fmt.Sprintf("%s:%s", host.Name, forward.Remote)})
+	}
+
+	ui.PrintSuccess(fmt.Sprintf("Connected to %s via %s", host.Name, client.Address))
+	table.Render()
+	ui.PrintInfo("Forwarding until Ctrl+C...")
+	<-ctx.Done()
+	return nil
+}
+
+// parseForwardSpec reads a forward spec: a service short name, a port, or local:remote ports.
+func parseForwardSpec(spec string, configuredServices []config.ServiceDefinition) (name, localPort, remotePort string, err error) {
+	if service, found := config.FindService(configuredServices, spec); found {
+		if service.Port == "" {
+			return "", "", "", fmt.Errorf("service '%s' has no port", spec)
+		}
+		return service.ShortName, service.Port, service.Port, nil
+	}
+
+	localPort, remotePort, hasLocal := strings.Cut(spec, ":")
+	if !hasLocal {
+		remotePort = localPort
+	}
+	for _, port := range []string{localPort, remotePort} {
+		if n, err := strconv.Atoi(port); err != nil || n < 1 || n > 65535 {
+			return "", "", "", fmt.Errorf("invalid forward '%s': expected a service, a port or local:remote", spec)
+		}
+	}
+	return spec, localPort, remotePort, nil
+}
+
+// ExtractHostFlag removes '--host <name>' or '--host=<name>' from args.
+// It returns an empty host if the flag is not present.
+func ExtractHostFlag(args []string) (string, []string, error) {
+	var host string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--host":
+			if i+1 >= len(args) {
+				return "", nil, fmt.Errorf("--host requires a server name from server-map.json")
+			}
+			i++
+			host = args[i]
+		case strings.HasPrefix(arg, "--host="):
+			host = strings.TrimPrefix(arg, "--host=")
+		default:
+			rest = append(rest, arg)
+		}
+	}
+	return host, rest, nil
+}
+
+// listRemoteHosts prints the servers in server-map.json.
+func listRemoteHosts() error {
+	hosts, err := utils.LoadRemoteHosts()
+	if err != nil {
+		return err
+	}
+	if len(hosts) == 0 {
+		ui.PrintInfo("No servers configured in server-map.json.")
+		return nil
+	}
+	table := ui.NewTable([]string{"HOST", "USER", "ADDRESS", "KEY"})
+	for _, host := range hosts {
+		table.AddRow(ui.TableRow{host.Name, host.User, colorizeNA(orNA(host.Address())), host.Key})
+	}
+	table.Render()
+	return nil
+}
+
+// orNA substitutes "N/A" for an empty value.
+func orNA(s string) string {
+	if s == "" {
+		return "N/A"
+	}
+	return s
+}
+
+// isTerminal reports whether f is attached to a terminal.
+func isTerminal(f *os.File) bool {
+	info, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return info.Mode()&os.ModeCharDevice != 0
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// Rollback restores a previously installed binary for a service from the rollback store.
+func Rollback(args []string) error {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		ui.PrintHeader("Rollback Command Help")
+		ui.PrintInfo("Usage: dex rollback <service> [version|--list]")
+		fmt.Println()
+		ui.PrintInfo("Description:")
+		ui.PrintInfo("  Restores a previous binary kept by 'dex build' or 'dex update', restarts")
+		ui.PrintInfo("  the service and confirms it reports healthy on its /service endpoint.")
+		ui.PrintInfo("  Without a version, the most recent previous version is restored.")
+		fmt.Println()
+		ui.PrintInfo("Flags:")
+		ui.PrintInfo("  --list   Show the versions available to roll back to.")
+		return nil
+	}
+
+	configuredServices, err := utils.GetConfiguredServices()
+	if err != nil {
+		return fmt.Errorf("failed to get configured services: %w", err)
+	}
+	service, found := config.FindService(configuredServices, args[0])
+	if !found {
+		return fmt.Errorf("service '%s' not found in service-map.json", args[0])
+	}
+
+	version := ""
+	if len(args) > 1 {
+		version = args[1]
+	}
+
+	if version == "--list" {
+		entries, err := utils.ListRollbackVersions(service)
+		if err != nil {
+			return err
+		}
+		if len(entries) == 0 {
+			ui.PrintInfo(fmt.Sprintf("No previous versions of %s are stored.", service.ShortName))
+			return nil
+		}
+		table := ui.NewTable([]string{"VERSION", "STORED", "SIZE"})
+		for _, entry := range entries {
+			table.AddRow(ui.TableRow{entry.Version, entry.StoredAt.Format("2006-01-02 15:04:05"), utils.FormatBytes(fileSize(entry.Path))})
+		}
+		table.Render()
+		return nil
+	}
+
+	restored, err := utils.RestoreBinary(service, version)
+	if err != nil {
+		return err
+	}
+	ui.PrintSuccess(fmt.Sprintf("Restored %s %s", service.ShortName, restored))
+
+	if service.SystemdName == "" {
+		return nil
+	}
+
+	ui.PrintInfo(fmt.Sprintf("Restarting %s and waiting for it to report healthy...", service.ShortName))
+	if err := utils.RestartAndVerify(context.Background(), service); err != nil {
+		return fmt.Errorf("%s %s was restored but is not healthy: %w", service.ShortName, restored, err)
+	}
+	ui.PrintSuccess(fmt.Sprintf("%s is healthy on %s", service.ShortName, restored))
+	return nil
+}
+
+// fileSize returns the size of a file in bytes, or 0 if it cannot be read.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -4,18 +4,37 @@ import (
 	"context"
 	"fmt"
 	"os/exec"
+	"strings"
 	"sync"
+	"time"
 
 	"github.com/EasterCompany/dex-cli/cache"
 	"github.com/EasterCompany/dex-cli/config"
 	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
 )
 
+// serviceReadyTimeout bounds how long a dependency may take to report healthy after starting.
+const serviceReadyTimeout = 30 * time.Second
+
 // Service handles start, stop, and restart commands for manageable services.
 func Service(command string, args []string) error {
+	reassign := false
+	var names []string
+	for _, arg := range args {
+		if arg == "--reassign" {
+			reassign = true
+			continue
+		}
+		names = append(names, arg)
+	}
+	if reassign && command == "stop" {
+		return fmt.Errorf("--reassign only applies to start and restart")
+	}
+
 	serviceShortName := "all"
-	if len(args) > 0 {
-		serviceShortName = args[0]
+	if len(names) > 0 {
+		serviceShortName = names[0]
 	}
 
 	if serviceShortName == "all" {
@@ -24,32 +43,33 @@ func Service(command string, args []string) error {
 		ui.PrintInfo(fmt.Sprintf("Attempting to %s service %s...", command, serviceShortName))
 	}
 
-	serviceMap, err := config.LoadServiceMapConfig()
+	configuredServices, err := utils.GetConfiguredServices()
 	if err != nil {
-		return fmt.Errorf("failed to load service-map.json: %w", err)
+		return fmt.Errorf("failed to get configured services: %w", err)
 	}
 
 	var servicesToManage []config.ServiceDefinition
 	if serviceShortName == "all" {
-		for _, serviceList := range serviceMap.Services {
-			for _, serviceEntry := range serviceList {
-				def := config.GetServiceDefinition(serviceEntry.ID)
-				// Only manage services that are not "cli" or "os" and have a systemd name
-				if def.ID != "" && def.Type != "cli" && def.Type != "os" && def.SystemdName != "" {
-					servicesToManage = append(servicesToManage, def)
-				}
+		for _, def := range configuredServices {
+			// Only manage services that are not "cli" or "os" and have a systemd name
+			if def.IsManageable() && def.SystemdName != "" {
+				servicesToManage = append(servicesToManage, def)
 			}
 		}
 	} else {
-		// Find specific service
-		def, err := config.Resolve(serviceShortName)
-		if err != nil {
-			return err
+		// Find specific service, preferring the configured (service-map.json) definition
+		def, found := config.FindService(configuredServices, serviceShortName)
+		if !found {
+			resolved, err := config.Resolve(serviceShortName)
+			if err != nil {
+				return err
+			}
+			def = *resolved
 		}
 		if def.SystemdName == "" {
 			return fmt.Errorf("service '%s' is not manageable via systemd", serviceShortName)
 		}
-		servicesToManage = append(servicesToManage, *def)
+		servicesToManage = append(servicesToManage, def)
 	}
 
 	if len(servicesToManage) == 0 {
@@ -61,46 +81,219 @@ func Service(command string, args []string) error {
 		return nil
 	}
 
+	// Refuse to act on a dependency graph that can never be satisfied
+	if cycle := config.FindDependencyCycle(configuredServices); cycle != nil {
+		return fmt.Errorf("dependency cycle detected in service-map.json: %s", strings.Join(cycle, " -> "))
+	}
+	levels, err := config.OrderByDependencies(servicesToManage)
+	if err != nil {
+		return err
+	}
+
+	ctx := context.Background()
+	var failures []error
+	switch command {
+	case "stop":
+		failures = stopServicesInOrder(levels)
+	case "start":
+		failures = startServicesInOrder(ctx, levels, configuredServices, reassign)
+	case "restart":
+		failures = stopServicesInOrder(levels)
+		if len(failures) == 0 {
+			failures = startServicesInOrder(ctx, levels, configuredServices, reassign)
+		}
+	default:
+		return fmt.Errorf("unknown service command: %s", command)
+	}
+
+	for _, err := range failures {
+		ui.PrintError(err.Error())
+	}
+
+	if len(failures) > 0 {
+		return fmt.Errorf("one or more services failed to %s", command)
+	}
+
+	if serviceShortName == "all" {
+		ui.PrintSuccess(fmt.Sprintf("Successfully executed '%s' for all services.", command))
+	} else {
+		ui.PrintSuccess(fmt.Sprintf("Successfully executed '%s' for %s.", command, serviceShortName))
+	}
+
+	// Perform cleanup if stopping or restarting
+	if command == "stop" || command == "restart" {
+		cleanupProcesses()
+	}
+
+	return nil
+}
+
+// runSystemctlLevel runs a systemctl command for every service in a level in parallel.
+// It returns the IDs of the services that failed alongside their errors.
+func runSystemctlLevel(command string, level []config.ServiceDefinition) (map[string]bool, []error) {
 	var wg sync.WaitGroup
-	errors := make(chan error, len(servicesToManage))
+	var mu sync.Mutex
+	failed := make(map[string]bool)
+	var errs []error
 
-	for _, s := range servicesToManage {
+	for _, s := range level {
 		wg.Add(1)
 		go func(service config.ServiceDefinition) {
 			defer wg.Done()
 			ui.PrintInfo(fmt.Sprintf("Executing '%s' for %s...", command, service.ShortName))
 			cmd := exec.Command("systemctl", "--user", command, service.SystemdName)
 			if output, err := cmd.CombinedOutput(); err != nil {
-				errors <- fmt.Errorf("failed to %s %s: %s", command, service.ShortName, string(output))
+				mu.Lock()
+				failed[service.ID] = true
+				errs = append(errs, fmt.Errorf("failed to %s %s: %s", command, service.ShortName, strings.TrimSpace(string(output))))
+				mu.Unlock()
 			}
 		}(s)
 	}
 
 	wg.Wait()
-	close(errors)
+	return failed, errs
+}
 
-	hasErrors := false
-	for err := range errors {
-		hasErrors = true
-		ui.PrintError(err.Error())
+// stopServicesInOrder stops services in reverse dependency order, so dependents go down first.
+func stopServicesInOrder(levels [][]config.ServiceDefinition) []error {
+	var errs []error
+	for i := len(levels) - 1; i >= 0; i-- {
+		_, levelErrs := runSystemctlLevel("stop", levels[i])
+		errs = append(errs, levelErrs...)
 	}
+	return errs
+}
 
-	if hasErrors {
-		return fmt.Errorf("one or more services failed to %s", command)
+// startServicesInOrder starts services level by level. Before a service starts, each of its
+// dependencies must be ready: dependencies started in this run are gated on their health report,
+// and dependencies outside this run (e.g., Redis) are probed once. Its port must also be free;
+// with reassign, a taken port is swapped for a free one. A service that cannot start blocks
+// everything that depends on it.
+func startServicesInOrder(ctx context.Context, levels [][]config.ServiceDefinition, configuredServices []config.ServiceDefinition, reassign bool) []error {
+	var errs []error
+	targets := make(map[string]bool)
+	for _, level := range levels {
+		for _, s := range level {
+			targets[s.ID] = true
+		}
 	}
 
-	if serviceShortName == "all" {
-		ui.PrintSuccess(fmt.Sprintf("Successfully executed '%s' for all services.", command))
-	} else {
-		ui.PrintSuccess(fmt.Sprintf("Successfully executed '%s' for %s.", command, serviceShortName))
+	blocked := make(map[string]string) // service ID -> reason it is unavailable
+	external := make(map[string]error) // readiness of dependencies outside this run
+
+	for _, level := range levels {
+		var runnable []config.ServiceDefinition
+		for _, s := range level {
+			if reason := dependencyBlocker(s, targets, blocked, external, configuredServices); reason != "" {
+				blocked[s.ID] = reason
+				errs = append(errs, fmt.Errorf("cannot start %s: %s", s.ShortName, reason))
+				continue
+			}
+			s, reason := claimServicePort(s, reassign)
+			if reason != "" {
+				blocked[s.ID] = reason
+				errs = append(errs, fmt.Errorf("cannot start %s: %s", s.ShortName, reason))
+				continue
+			}
+			runnable = append(runnable, s)
+		}
+
+		failed, levelErrs := runSystemctlLevel("start", runnable)
+		errs = append(errs, levelErrs...)
+
+		// Gate: anything with dependents in this run must report healthy before we move on
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		for _, s := range runnable {
+			if failed[s.ID] {
+				blocked[s.ID] = "failed to start"
+				continue
+			}
+			if !hasDependents(s, targets, configuredServices) {
+				continue
+			}
+
+			wg.Add(1)
+			go func(service config.ServiceDefinition) {
+				defer wg.Done()
+				ui.PrintInfo(fmt.Sprintf("Waiting for %s to report healthy...", service.ShortName))
+				waitStart := time.Now()
+				if err := utils.WaitForServiceReady(ctx, service, serviceReadyTimeout); err != nil {
+					mu.Lock()
+					blocked[service.ID] = fmt.Sprintf("health check failed: %v", err)
+					errs = append(errs, fmt.Errorf("%s started but did not become ready: %v", service.ShortName, err))
+					mu.Unlock()
+					return
+				}
+				ui.PrintSuccess(fmt.Sprintf("%s is ready (%s)", service.ShortName, time.Since(waitStart).Round(time.Millisecond)))
+			}(s)
+		}
+		wg.Wait()
 	}
 
-	// Perform cleanup if stopping or restarting
-	if command == "stop" || command == "restart" {
-		cleanupProcesses()
+	return errs
+}
+
+// claimServicePort checks that no other process holds a service's port before it starts.
+// With reassign, the service is moved to a free port; otherwise the holder is reported.
+func claimServicePort(s config.ServiceDefinition, reassign bool) (config.ServiceDefinition, string) {
+	holder, err := utils.ServicePortConflict(s)
+	if err != nil || holder == nil {
+		return s, ""
 	}
+	if !reassign {
+		return s, fmt.Sprintf("port %s is held by %s (use --reassign to move it to a free port)", s.Port, holder)
+	}
+	moved, err := utils.ReassignServicePort(s)
+	if err != nil {
+		return s, fmt.Sprintf("port %s is held by %s and reassigning failed: %v", s.Port, holder, err)
+	}
+	ui.PrintWarning(fmt.Sprintf("Port %s is held by %s; moved %s to port %s in service-map.json.", s.Port, holder, s.ShortName, moved.Port))
+	return moved, ""
+}
 
-	return nil
+// dependencyBlocker returns why a service cannot start yet, or "" if all of its dependencies are ready.
+func dependencyBlocker(s config.ServiceDefinition, targets map[string]bool, blocked map[string]string, external map[string]error, configuredServices []config.ServiceDefinition) string {
+	for _, depName := range s.DependsOn {
+		dep, ok := config.FindService(configuredServices, depName)
+		if !ok {
+			return fmt.Sprintf("dependency '%s' is not configured in service-map.json", depName)
+		}
+
+		if targets[dep.ID] {
+			if reason, isBlocked := blocked[dep.ID]; isBlocked {
+				return fmt.Sprintf("blocked by %s (%s)", dep.ShortName, reason)
+			}
+			continue
+		}
+
+		// Dependency is not part of this run: it must already be up
+		err, checked := external[dep.ID]
+		if !checked {
+			err = utils.CheckServiceReady(dep)
+			external[dep.ID] = err
+		}
+		if err != nil {
+			return fmt.Sprintf("dependency %s is not ready (%v)", dep.ShortName, err)
+		}
+	}
+	return ""
+}
+
+// hasDependents checks if any service in this run depends on s.
+func hasDependents(s config.ServiceDefinition, targets map[string]bool, configuredServices []config.ServiceDefinition) bool {
+	for _, other := range configuredServices {
+		if !targets[other.ID] {
+			continue
+		}
+		for _, depName := range other.DependsOn {
+			if depName == s.ShortName || depName == s.ID {
+				return true
+			}
+		}
+	}
+	return false
 }
 
 func cleanupProcesses() {
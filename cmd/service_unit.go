@@ -0,0 +1,217 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// ServiceUnit inspects and regenerates the systemd units dex generates for services
+func ServiceUnit(args []string) error {
+	if len(args) == 0 || args[0] == "--help" || args[0] == "-h" {
+		printServiceUnitHelp()
+		return nil
+	}
+	if len(args) < 2 {
+		return fmt.Errorf("usage: dex service %s <service|all>", args[0])
+	}
+
+	services, err := unitServices(args[1])
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "diff":
+		for i, s := range services {
+			if i > 0 {
+				fmt.Println()
+			}
+			if err := diffServiceUnit(s); err != nil {
+				return err
+			}
+		}
+		return nil
+	case "apply":
+		return applyServiceUnits(services)
+	default:
+		return fmt.Errorf("unknown service command '%s'", args[0])
+	}
+}
+
+// unitServices resolves a short name, or "all", to the configured services that have a unit.
+func unitServices(name string) ([]config.ServiceDefinition, error) {
+	configuredServices, err := utils.GetConfiguredServices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get configured services: %w", err)
+	}
+	if name == "all" {
+		var services []config.ServiceDefinition
+		for _, s := range configuredServices {
+			if s.IsManageable() && s.SystemdName != "" {
+				services = append(services, s)
+			}
+		}
+		return services, nil
+	}
+	s, found := config.FindService(configuredServices, name)
+	if !found {
+		return nil, fmt.Errorf("service '%s' is not configured in service-map.json", name)
+	}
+	if s.SystemdName == "" {
+		return nil, fmt.Errorf("service '%s' is not managed by systemd", name)
+	}
+	return []config.ServiceDefinition{s}, nil
+}
+
+// diffServiceUnit prints how a service's installed unit and drop-in differ from the ones
+// dex would generate now.
+func diffServiceUnit(s config.ServiceDefinition) error {
+	unit, err := utils.RenderSystemdUnit(s)
+	if err != nil {
+		return err
+	}
+	files := []struct{ path, generated string }{
+		{utils.SystemdUnitPath(s), unit},
+		{utils.SystemdDropInPath(s), utils.RenderSystemdDropIn(s)},
+	}
+
+	ui.PrintHeader(fmt.Sprintf("%s (%s)", s.ShortName, s.SystemdName))
+	changed := false
+	for _, file := range files {
+		installed, err := os.ReadFile(file.path)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("failed to read %s: %w", file.path, err)
+		}
+		if string(installed) == file.generated {
+			continue
+		}
+		changed = true
+		fmt.Println(ui.Colorize("--- installed "+file.path, ui.ColorBrightRed))
+		fmt.Println(ui.Colorize("+++ generated "+file.path, ui.ColorGreen))
+		for _, line := range diffLines(splitUnitLines(string(installed)), splitUnitLines(file.generated)) {
+			switch line[0] {
+			case '-':
+				fmt.Println(ui.Colorize(line, ui.ColorBrightRed))
+			case '+':
+				fmt.Println(ui.Colorize(line, ui.ColorGreen))
+			default:
+				fmt.Println(ui.Colorize(line, ui.ColorDarkGray))
+			}
+		}
+	}
+
+	// Drop-ins written by hand also apply, but dex does not manage them
+	others, _ := filepath.Glob(filepath.Join(filepath.Dir(utils.SystemdDropInPath(s)), "*.conf"))
+	for _, other := range others {
+		if other != utils.SystemdDropInPath(s) {
+			ui.PrintWarning(fmt.Sprintf("%s also applies and is not managed by dex.", other))
+		}
+	}
+
+	if !changed {
+		ui.PrintSuccess("Installed unit matches the generated one.")
+	} else {
+		ui.PrintInfo(fmt.Sprintf("Run 'dex service apply %s' to install the generated unit.", s.ShortName))
+	}
+	return nil
+}
+
+// applyServiceUnits regenerates the units of services and restarts the ones that are running.
+func applyServiceUnits(services []config.ServiceDefinition) error {
+	ctx := context.Background()
+	var failures []string
+	for _, s := range services {
+		if err := utils.WriteSystemdUnit(s); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", s.ShortName, err))
+			continue
+		}
+		if !utils.IsServiceActive(s) {
+			ui.PrintSuccess(fmt.Sprintf("Regenerated %s; it will use the new unit when started.", s.ShortName))
+			continue
+		}
+		ui.PrintInfo(fmt.Sprintf("Regenerated %s, restarting...", s.ShortName))
+		if err := utils.RestartAndVerify(ctx, s); err != nil {
+			failures = append(failures, fmt.Sprintf("%s: %v", s.ShortName, err))
+			continue
+		}
+		ui.PrintSuccess(fmt.Sprintf("%s restarted with the new unit.", s.ShortName))
+	}
+	if len(failures) > 0 {
+		for _, failure := range failures {
+			ui.PrintError(failure)
+		}
+		return fmt.Errorf("failed to apply %d of %d units", len(failures), len(services))
+	}
+	return nil
+}
+
+// splitUnitLines splits a unit file into lines, ignoring the final newline.
+func splitUnitLines(text string) []string {
+	if text == "" {
+		return nil
+	}
+	return strings.Split(strings.TrimSuffix(text, "\n"), "\n")
+}
+
+// diffLines returns a line diff of two files, each line prefixed with ' ', '-' or '+'.
+// Unit files are short, so a plain longest-common-subsequence table is fine.
+func diffLines(before, after []string) []string {
+	lcs := make([][]int, len(before)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(after)+1)
+	}
+	for i := len(before) - 1; i >= 0; i-- {
+		for j := len(after) - 1; j >= 0; j-- {
+			if before[i] == after[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else {
+				lcs[i][j] = max(lcs[i+1][j], lcs[i][j+1])
+			}
+		}
+	}
+
+	var lines []string
+	i, j := 0, 0
+	for i < len(before) && j < len(after) {
+		switch {
+		case before[i] == after[j]:
+			lines = append(lines, " "+before[i])
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, "-"+before[i])
+			i++
+		default:
+			lines = append(lines, "+"+after[j])
+			j++
+		}
+	}
+	for ; i < len(before); i++ {
+		lines = append(lines, "-"+before[i])
+	}
+	for ; j < len(after); j++ {
+		lines = append(lines, "+"+after[j])
+	}
+	return lines
+}
+
+func printServiceUnitHelp() {
+	ui.PrintHeader("Service Command Help")
+	ui.PrintInfo("Usage: dex service <diff|apply> <service|all>")
+	fmt.Println()
+	ui.PrintInfo("Commands:")
+	ui.PrintInfo("  diff    Show how the installed unit and drop-in differ from the generated ones")
+	ui.PrintInfo("  apply   Regenerate the unit and drop-in, restarting the service if it is running")
+	fmt.Println()
+	ui.PrintInfo("Per-service settings live under 'unit' in service-map.json, e.g.")
+	ui.PrintInfo(`  "unit": {"memory_max": "2G", "cpu_quota": "150%", "restart": "on-failure",`)
+	ui.PrintInfo(`           "environment": {"LOG_LEVEL": "debug"}, "private_tmp": true}`)
+	ui.PrintInfo("They are written to <unit>.d/dex-overrides.conf next to the generated unit.")
+}
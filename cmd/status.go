@@ -2,6 +2,7 @@ package cmd
 
 import (
 	"bufio"
+	"context"
 	"crypto/tls"
 	"encoding/json"
 	"fmt"
@@ -11,6 +12,7 @@ import (
 	"os/exec"
 	"regexp"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/EasterCompany/dex-cli/config"
@@ -30,22 +32,142 @@ const (
 	maxUptimeLen  = 16
 )
 
+const (
+	// statusCheckWorkers bounds how many services are checked at once
+	statusCheckWorkers = 8
+	// statusCheckTimeout bounds a single check; the cache check alone can spend 5s dialing and 5s reading
+	statusCheckTimeout = 6 * time.Second
+	// statusDeadline bounds a whole status run, however many services are configured
+	statusDeadline = 15 * time.Second
+)
+
 const (
 	CheckMark = "✅"
 	CrossMark = "❌"
 )
 
+// ServiceStatus is the structured result of checking a single service.
+// The status table, JSON and YAML outputs are all rendered from it.
+type ServiceStatus struct {
+	Service string `json:"service"`
+	ID      string `json:"id"`
+	Type    string `json:"type"`
+	Address string `json:"address"`
+	Version string `json:"version"`
+	Branch  string `json:"branch"`
+	Commit  string `json:"commit"`
+	Status  string `json:"status"`
+	Uptime  string `json:"uptime"`
+	CPU     string `json:"cpu"`
+	Memory  string `json:"memory"`
+	// Error holds the reason a check failed, if any
+	Error string `json:"error,omitempty"`
+	// Report is the raw /service report (see health.ServiceReport) when the service exposes one
+	Report json.RawMessage `json:"report,omitempty"`
+}
+
+// newServiceStatus creates a status with every value unknown.
+func newServiceStatus(service config.ServiceDefinition) ServiceStatus {
+	return ServiceStatus{
+		Service: service.ShortName,
+		ID:      service.ID,
+		Type:    service.Type,
+		Address: service.GetHost(),
+		Version: "N/A",
+		Branch:  "N/A",
+		Commit:  "N/A",
+		Status:  "N/A",
+		Uptime:  "N/A",
+		CPU:     "N/A",
+		Memory:  "N/A",
+	}
+}
+
+// TableRow renders the status as the 9 columns of the service table:
+// SERVICE, ADDRESS, VERSION, BRANCH, COMMIT, STATUS, UPTIME, CPU, MEM.
+func (s ServiceStatus) TableRow() ui.TableRow {
+	address := s.Address
+	if address == "" {
+		address = "N/A"
+	}
+	return ui.TableRow{
+		ui.Truncate(s.Service, maxServiceLen),
+		colorizeNA(ui.Truncate(address, maxAddressLen)),
+		colorizeNA(ui.Truncate(s.Version, maxVersionLen)),
+		colorizeNA(ui.Truncate(s.Branch, maxBranchLen)),
+		colorizeNA(ui.Truncate(s.Commit, maxCommitLen)),
+		colorizeStatus(s.Status),
+		colorizeNA(ui.Truncate(s.Uptime, maxUptimeLen)),
+		colorizeNA(s.CPU),
+		colorizeNA(s.Memory),
+	}
+}
+
 // Status checks the health of one or all services
-func Status(serviceShortName string) error {
-	if serviceShortName == "--help" || serviceShortName == "-h" {
-		ui.PrintHeader("Status Command Help")
-		ui.PrintInfo("Usage: dex status [service|all]")
-		fmt.Println()
-		ui.PrintInfo("Description:")
-		ui.PrintInfo("  Check the status of CLI and services.")
-		ui.PrintInfo("  If no argument or 'all' is provided, checks all services.")
-		ui.PrintInfo("  Otherwise, checks the specified service.")
-		return nil
+func Status(args []string) error {
+	serviceShortName := "all"
+	format := "table"
+	watch := false
+	allHosts := false
+	interval := defaultWatchInterval
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--help", "-h":
+			ui.PrintHeader("Status Command Help")
+			ui.PrintInfo("Usage: dex status [service|all] [--json] [--format table|json|yaml] [--watch [--interval 5s]] [--all-hosts]")
+			fmt.Println()
+			ui.PrintInfo("Description:")
+			ui.PrintInfo("  Check the status of CLI and services.")
+			ui.PrintInfo("  If no argument or 'all' is provided, checks all services.")
+			ui.PrintInfo("  Otherwise, checks the specified service.")
+			fmt.Println()
+			ui.PrintInfo("Flags:")
+			ui.PrintInfo("  --json              Shorthand for --format json.")
+			ui.PrintInfo("  --format <format>   Output as a table (default), json or yaml.")
+			ui.PrintInfo("  --watch             Redraw the table on an interval until Ctrl+C.")
+			ui.PrintInfo("  --interval <dur>    Refresh interval for --watch (e.g. 2s, 1m; default 5s).")
+			ui.PrintInfo("  --all-hosts         Include every server in server-map.json, with a HOST column.")
+			return nil
+		case "--json":
+			format = "json"
+		case "--format":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--format requires a value (table, json or yaml)")
+			}
+			i++
+			format = args[i]
+		case "--watch", "-w":
+			watch = true
+		case "--all-hosts":
+			allHosts = true
+		case "--interval":
+			if i+1 >= len(args) {
+				return fmt.Errorf("--interval requires a duration (e.g. 5s)")
+			}
+			i++
+			parsed, err := parseWatchInterval(args[i])
+			if err != nil {
+				return err
+			}
+			interval = parsed
+		default:
+			if strings.HasPrefix(arg, "--format=") {
+				format = strings.TrimPrefix(arg, "--format=")
+			} else if strings.HasPrefix(arg, "-") {
+				return fmt.Errorf("unknown status flag: %s", arg)
+			} else {
+				serviceShortName = arg
+			}
+		}
+	}
+	if format != "table" && format != "json" && format != "yaml" {
+		return fmt.Errorf("invalid format '%s': must be 'table', 'json' or 'yaml'", format)
+	}
+	if watch && format != "table" {
+		return fmt.Errorf("--watch only supports table output")
+	}
+	if watch && allHosts {
+		return fmt.Errorf("--watch cannot be combined with --all-hosts")
 	}
 
 	logFile, err := config.LogFile()
@@ -60,100 +182,177 @@ func Status(serviceShortName string) error {
 
 	log(fmt.Sprintf("Checking status for service: %s", serviceShortName))
 
+	if allHosts {
+		return statusAllHosts(serviceShortName, format)
+	}
+
+	servicesToCheck, err := selectStatusServices(serviceShortName)
+	if err != nil {
+		return err
+	}
+
+	if watch {
+		return watchStatus(servicesToCheck, interval)
+	}
+
+	// Check status for all selected services
+	statuses := runStatusChecks(context.Background(), servicesToCheck, statusCheckTimeout)
+	for i, serviceDef := range servicesToCheck {
+		log(fmt.Sprintf("Service: %s, Type: %s, Address: %s, Status: %s", serviceDef.ID, serviceDef.Type, serviceDef.GetHost(), statuses[i].Status))
+	}
+
+	return renderStatuses(statuses, format)
+}
+
+// selectStatusServices returns the configured services matching a short name, or all of them.
+func selectStatusServices(serviceShortName string) ([]config.ServiceDefinition, error) {
 	// Get the list of services *from the service-map.json*
 	allServices, err := utils.GetConfiguredServices()
 	if err != nil {
-		return fmt.Errorf("failed to get configured services: %w", err)
+		return nil, fmt.Errorf("failed to get configured services: %w", err)
 	}
 
-	var rows []ui.TableRow
-	var servicesToCheck []config.ServiceDefinition
-
 	if serviceShortName == "all" || serviceShortName == "" {
-		servicesToCheck = allServices
-	} else {
-		// Find the specific service by its short name from the configured list
-		var foundService *config.ServiceDefinition
-		for i, s := range allServices {
-			if s.ShortName == serviceShortName {
-				// Use the address of the item in the slice
-				foundService = &allServices[i]
-				break
-			}
-		}
+		return allServices, nil
+	}
 
-		if foundService == nil {
-			return fmt.Errorf("service alias '%s' not found in configured services (service-map.json)", serviceShortName)
+	// Find the specific service by its short name from the configured list
+	for _, s := range allServices {
+		if s.ShortName == serviceShortName {
+			return []config.ServiceDefinition{s}, nil
 		}
-		servicesToCheck = append(servicesToCheck, *foundService)
 	}
+	return nil, fmt.Errorf("service alias '%s' not found in configured services (service-map.json)", serviceShortName)
+}
 
-	// Check status for all selected services
-	for _, serviceDef := range servicesToCheck {
-		row := checkServiceStatus(serviceDef)
-		rows = append(rows, row)
-		// NOTE: Status column is at index 5.
-		log(fmt.Sprintf("Service: %s, Type: %s, Address: %s, Status: %s", serviceDef.ID, serviceDef.Type, serviceDef.GetHost(), row[5]))
+// renderStatuses writes the statuses in the requested format.
+func renderStatuses(statuses []ServiceStatus, format string) error {
+	switch format {
+	case "json":
+		jsonData, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode status report: %w", err)
+		}
+		ui.PrintRaw(string(jsonData) + "\n")
+		return nil
+	case "yaml":
+		data, err := utils.MarshalYAML(statuses)
+		if err != nil {
+			return fmt.Errorf("failed to encode status report: %w", err)
+		}
+		ui.PrintRaw(string(data))
+		return nil
 	}
 
-	// Render table
+	var rows []ui.TableRow
+	for _, status := range statuses {
+		rows = append(rows, status.TableRow())
+	}
 	table := ui.CreateServiceTable(rows)
 	table.Render()
-
 	return nil
 }
 
 // checkServiceStatus acts as a dispatcher, routing to the correct status checker based on service type.
-// All handlers must return exactly 9 columns: SERVICE, ADDRESS, VERSION, BRANCH, COMMIT, STATUS, UPTIME, CPU, MEM.
-func checkServiceStatus(service config.ServiceDefinition) ui.TableRow {
-	serviceID := ui.Truncate(service.ShortName, maxServiceLen)
-	address := ui.Truncate(service.GetHost(), maxAddressLen)
-
+func checkServiceStatus(service config.ServiceDefinition) ServiceStatus {
 	switch service.Type {
 	case "cli":
-		return checkCLIStatus(service, serviceID, address)
+		return checkCLIStatus(service)
 	case "prd":
-		return checkProdStatus(service, serviceID, address)
+		return checkProdStatus(service)
 	case "os":
 		// Specialized handling for Ollama
 		if strings.Contains(strings.ToLower(service.ID), "ollama") || strings.Contains(strings.ToLower(service.ShortName), "ollama") {
-			return checkOllamaStatus(service, serviceID, address)
+			return checkOllamaStatus(service)
 		}
 		// Specialized handling for Upstash (REST API)
 		if strings.Contains(strings.ToLower(service.Domain), "upstash.io") {
-			return checkUpstashStatus(service, serviceID, address)
+			return checkUpstashStatus(service)
 		}
-		return checkCacheStatus(service, serviceID, address)
+		return checkCacheStatus(service)
 	default: // All other service types are assumed to be HTTP-based (fe, be, cs, th)
-		return checkHTTPStatus(service, serviceID, address)
+		return checkHTTPStatus(service)
+	}
+}
+
+// runStatusChecks checks services on a bounded pool of workers and returns the results in
+// input order. Each check gets its own timeout and the whole run shares statusDeadline, so a
+// slow service is reported as TIMEOUT rather than holding up the rest. Abandoned checks are
+// left to finish on their own, as every checker has its own network timeouts.
+func runStatusChecks(ctx context.Context, services []config.ServiceDefinition, timeout time.Duration) []ServiceStatus {
+	ctx, cancel := context.WithTimeout(ctx, statusDeadline)
+	defer cancel()
+
+	statuses := make([]ServiceStatus, len(services))
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(statusCheckWorkers, len(services)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				statuses[i] = checkServiceStatusWithTimeout(ctx, services[i], timeout)
+			}
+		}()
+	}
+
+	for i := range services {
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+	return statuses
+}
+
+// checkServiceStatusWithTimeout runs checkServiceStatus, giving up after the timeout or once the context ends.
+func checkServiceStatusWithTimeout(ctx context.Context, service config.ServiceDefinition, timeout time.Duration) ServiceStatus {
+	if ctx.Err() == nil {
+		result := make(chan ServiceStatus, 1)
+		go func() { result <- checkServiceStatus(service) }()
+
+		select {
+		case status := <-result:
+			return status
+		case <-time.After(timeout):
+			status := newServiceStatus(service)
+			status.Status = "TIMEOUT"
+			status.Error = fmt.Sprintf("check timed out after %s", timeout)
+			return status
+		case <-ctx.Done():
+		}
+	}
+
+	status := newServiceStatus(service)
+	if ctx.Err() == context.DeadlineExceeded {
+		status.Status = "TIMEOUT"
+		status.Error = fmt.Sprintf("status deadline of %s exceeded", statusDeadline)
+	} else {
+		status.Error = ctx.Err().Error()
 	}
+	return status
 }
 
 // checkUpstashStatus checks an Upstash service via its HTTP REST API.
-func checkUpstashStatus(service config.ServiceDefinition, serviceID, address string) ui.TableRow {
-	badStatusRow := func(reason string) ui.TableRow {
+func checkUpstashStatus(service config.ServiceDefinition) ServiceStatus {
+	status := newServiceStatus(service)
+	status.Version = "Cloud"
+	status.Uptime = "∞"
+
+	badStatus := func(reason string) ServiceStatus {
 		// Log the failure reason for debugging
 		logFile, _ := config.LogFile()
 		if logFile != nil {
-			_, _ = fmt.Fprintf(logFile, "[%s] Upstash check failed: %s\n", serviceID, reason)
-		}
-		return []string{
-			serviceID,
-			address,
-			colorizeNA("Cloud"),   // VERSION
-			colorizeNA("--"),      // BRANCH
-			colorizeNA("--"),      // COMMIT
-			colorizeStatus("BAD"), // STATUS
-			colorizeNA("∞"),       // UPTIME
-			colorizeNA("--"),      // CPU
-			colorizeNA("--"),      // MEM
+			_, _ = fmt.Fprintf(logFile, "[%s] Upstash check failed: %s\n", service.ShortName, reason)
 		}
+		status.Status = "BAD"
+		status.Error = reason
+		return status
 	}
 
 	url := fmt.Sprintf("https://%s/ping", service.Domain)
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
-		return badStatusRow(fmt.Sprintf("failed to create request: %v", err))
+		return badStatus(fmt.Sprintf("failed to create request: %v", err))
 	}
 
 	if service.Credentials != nil && service.Credentials.Password != "" {
@@ -163,17 +362,17 @@ func checkUpstashStatus(service config.ServiceDefinition, serviceID, address str
 	client := &http.Client{Timeout: 5 * time.Second}
 	resp, err := client.Do(req)
 	if err != nil {
-		return badStatusRow(fmt.Sprintf("request failed: %v", err))
+		return badStatus(fmt.Sprintf("request failed: %v", err))
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return badStatusRow(fmt.Sprintf("HTTP %d", resp.StatusCode))
+		return badStatus(fmt.Sprintf("HTTP %d", resp.StatusCode))
 	}
 
 	body, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return badStatusRow(fmt.Sprintf("failed to read body: %v", err))
+		return badStatus(fmt.Sprintf("failed to read body: %v", err))
 	}
 
 	var result struct {
@@ -181,49 +380,35 @@ func checkUpstashStatus(service config.ServiceDefinition, serviceID, address str
 		Error  string `json:"error"`
 	}
 	if err := json.Unmarshal(body, &result); err != nil {
-		return badStatusRow(fmt.Sprintf("failed to parse JSON: %v", err))
+		return badStatus(fmt.Sprintf("failed to parse JSON: %v", err))
 	}
 
 	if result.Error != "" {
-		return badStatusRow(result.Error)
+		return badStatus(result.Error)
 	}
 
 	if result.Result != "PONG" {
-		return badStatusRow(fmt.Sprintf("unexpected result: %s", result.Result))
+		return badStatus(fmt.Sprintf("unexpected result: %s", result.Result))
 	}
 
-	return []string{
-		serviceID,
-		address,
-		colorizeNA("Cloud"), // VERSION
-		colorizeNA("--"),    // BRANCH
-		colorizeNA("--"),    // COMMIT
-		colorizeStatus("OK"),
-		colorizeNA("∞"),
-		colorizeNA("--"),
-		colorizeNA("--"),
-	}
+	status.Status = "OK"
+	return status
 }
 
 // checkProdStatus checks a production service via a simple HTTPS ping (ignoring port).
-func checkProdStatus(service config.ServiceDefinition, serviceID, address string) ui.TableRow {
-	badStatusRow := func(reason string) ui.TableRow {
+func checkProdStatus(service config.ServiceDefinition) ServiceStatus {
+	status := newServiceStatus(service)
+	status.Version = "Live"
+
+	badStatus := func(reason string) ServiceStatus {
 		// Log the failure reason for debugging
 		logFile, _ := config.LogFile()
 		if logFile != nil {
-			_, _ = fmt.Fprintf(logFile, "[%s] Production check failed: %s\n", serviceID, reason)
-		}
-		return []string{
-			serviceID,
-			address,
-			colorizeNA("Live"),    // VERSION
-			colorizeNA("--"),      // BRANCH
-			colorizeNA("--"),      // COMMIT
-			colorizeStatus("BAD"), // STATUS
-			colorizeNA("--"),      // UPTIME
-			colorizeNA("--"),      // CPU
-			colorizeNA("--"),      // MEM
+			_, _ = fmt.Fprintf(logFile, "[%s] Production check failed: %s\n", service.ShortName, reason)
 		}
+		status.Status = "BAD"
+		status.Error = reason
+		return status
 	}
 
 	// Use https://<domain> only, ignoring port for production sites
@@ -231,25 +416,17 @@ func checkProdStatus(service config.ServiceDefinition, serviceID, address string
 	client := &http.Client{Timeout: 5 * time.Second}
 	resp, err := client.Get(url)
 	if err != nil {
-		return badStatusRow(fmt.Sprintf("failed to reach production site: %v", err))
+		return badStatus(fmt.Sprintf("failed to reach production site: %v", err))
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return badStatusRow(fmt.Sprintf("HTTP %d", resp.StatusCode))
+		return badStatus(fmt.Sprintf("HTTP %d", resp.StatusCode))
 	}
 
-	return []string{
-		serviceID,
-		address,
-		colorizeNA("Live"), // VERSION
-		colorizeNA("--"),   // BRANCH
-		colorizeNA("--"),   // COMMIT
-		colorizeStatus("OK"),
-		colorizeNA("∞"),
-		colorizeNA("--"),
-		colorizeNA("--"),
-	}
+	status.Status = "OK"
+	status.Uptime = "∞"
+	return status
 }
 
 // colorizeNA colors "N/A" values dark gray, and leaves other values as-is.
@@ -283,43 +460,28 @@ func formatUptime(seconds int) string {
 }
 
 // checkCLIStatus checks if the CLI tool is installed and working
-// Returns 9 columns: SERVICE, ADDRESS, VERSION, BRANCH, COMMIT, STATUS, UPTIME, CPU, MEM
-func checkCLIStatus(service config.ServiceDefinition, serviceID, address string) ui.TableRow {
+func checkCLIStatus(service config.ServiceDefinition) ServiceStatus {
+	status := newServiceStatus(service)
+	// A local CLI has no address, even if host is set
+	status.Address = ""
+
 	cmd := exec.Command("dex", "version")
 	output, err := cmd.CombinedOutput()
 
-	status := "OK"
+	status.Status = "OK"
 	if err != nil {
-		status = "BAD"
+		status.Status = "BAD"
+		status.Error = strings.TrimSpace(string(output))
 	}
 
-	version := "N/A"
-	branch := "N/A"
-	commit := "N/A"
-
 	parsedVersion, err := git.Parse(strings.TrimSpace(string(output)))
 	if err == nil {
-		version = parsedVersion.Short()
-		branch = parsedVersion.Branch
-		commit = parsedVersion.Commit
-	}
-
-	// Ensure the ADDRESS column is N/A for a local CLI, even if host is set.
-	if address == "" {
-		address = "N/A"
+		status.Version = parsedVersion.Short()
+		status.Branch = parsedVersion.Branch
+		status.Commit = parsedVersion.Commit
 	}
 
-	return []string{
-		serviceID,
-		colorizeNA(address), // ADDRESS (Likely "N/A")
-		colorizeNA(ui.Truncate(version, maxVersionLen)),
-		colorizeNA(branch),
-		colorizeNA(commit),
-		colorizeStatus(status),
-		colorizeNA("N/A"), // UPTIME
-		colorizeNA("N/A"), // CPU
-		colorizeNA("N/A"), // MEM
-	}
+	return status
 }
 
 // isCloudDomain checks if the domain is a known cloud Redis provider requiring TLS.
@@ -485,20 +647,12 @@ func getTotalSystemMemory() (int64, error) {
 }
 
 // checkOllamaStatus checks an Ollama service via its HTTP API
-// Returns 9 columns: SERVICE, ADDRESS, VERSION, BRANCH, COMMIT, STATUS, UPTIME, CPU, MEM
-func checkOllamaStatus(service config.ServiceDefinition, serviceID, address string) ui.TableRow {
-	badStatusRow := func() ui.TableRow {
-		return []string{
-			serviceID,
-			address,
-			colorizeNA("N/A"),     // VERSION
-			colorizeNA("--"),      // BRANCH
-			colorizeNA("--"),      // COMMIT
-			colorizeStatus("BAD"), // STATUS
-			colorizeNA("N/A"),     // UPTIME
-			colorizeNA("N/A"),     // CPU
-			colorizeNA("N/A"),     // MEM
-		}
+func checkOllamaStatus(service config.ServiceDefinition) ServiceStatus {
+	status := newServiceStatus(service)
+	badStatus := func(reason string) ServiceStatus {
+		status.Status = "BAD"
+		status.Error = reason
+		return status
 	}
 
 	// Build the ollama version endpoint URL
@@ -508,7 +662,7 @@ func checkOllamaStatus(service config.ServiceDefinition, serviceID, address stri
 	// Try to fetch version info
 	resp, err := utils.FetchURL(url, 2*time.Second)
 	if err != nil {
-		return badStatusRow()
+		return badStatus(err.Error())
 	}
 
 	// Parse the JSON response
@@ -516,53 +670,35 @@ func checkOllamaStatus(service config.ServiceDefinition, serviceID, address stri
 		Version string `json:"version"`
 	}
 	if err := json.Unmarshal([]byte(resp), &versionData); err != nil {
-		return badStatusRow()
+		return badStatus(fmt.Sprintf("failed to parse version: %v", err))
+	}
+	status.Status = "OK"
+	if versionData.Version != "" {
+		status.Version = versionData.Version
 	}
 
 	// Get uptime, CPU, and memory from systemd if this is a local service
-	uptime := "N/A"
-	cpu := "N/A"
-	mem := "N/A"
 	if isLocalAddress(service.Domain) {
-		uptime = getSystemdServiceUptime("ollama")
-		cpu = getSystemdServiceCPU("ollama")
-		mem = getSystemdServiceMemory("ollama")
-	}
-
-	// Successful status row
-	return []string{
-		serviceID,
-		address,
-		colorizeNA(ui.Truncate(versionData.Version, maxVersionLen)),
-		colorizeNA("--"), // BRANCH
-		colorizeNA("--"), // COMMIT
-		colorizeStatus("OK"),
-		colorizeNA(uptime),
-		colorizeNA(cpu),
-		colorizeNA(mem),
+		status.Uptime = getSystemdServiceUptime("ollama")
+		status.CPU = getSystemdServiceCPU("ollama")
+		status.Memory = getSystemdServiceMemory("ollama")
 	}
+
+	return status
 }
 
 // checkCacheStatus checks a cache/db service (Redis/Valkey) with a simplified PING command.
-// Returns 9 columns: SERVICE, ADDRESS, VERSION, BRANCH, COMMIT, STATUS, UPTIME, CPU, MEM
-func checkCacheStatus(service config.ServiceDefinition, serviceID, address string) ui.TableRow {
-	badStatusRow := func(reason string) ui.TableRow {
+func checkCacheStatus(service config.ServiceDefinition) ServiceStatus {
+	status := newServiceStatus(service)
+	badStatus := func(reason string) ServiceStatus {
 		// Log the failure reason for debugging
 		logFile, _ := config.LogFile()
 		if logFile != nil {
-			_, _ = fmt.Fprintf(logFile, "[%s] Cache check failed: %s\n", serviceID, reason)
-		}
-		return []string{
-			serviceID,
-			address,
-			colorizeNA("N/A"),     // VERSION
-			colorizeNA("--"),      // BRANCH
-			colorizeNA("--"),      // COMMIT
-			colorizeStatus("BAD"), // STATUS
-			colorizeNA("N/A"),     // UPTIME
-			colorizeNA("N/A"),     // CPU
-			colorizeNA("N/A"),     // MEM
+			_, _ = fmt.Fprintf(logFile, "[%s] Cache check failed: %s\n", service.ShortName, reason)
 		}
+		status.Status = "BAD"
+		status.Error = reason
+		return status
 	}
 
 	dialer := &net.Dialer{Timeout: 5 * time.Second} // Increased timeout for cloud instances
@@ -592,12 +728,12 @@ func checkCacheStatus(service config.ServiceDefinition, serviceID, address strin
 	}
 
 	if err != nil {
-		return badStatusRow(fmt.Sprintf("connection failed (TLS=%v): %v", useTLS, err))
+		return badStatus(fmt.Sprintf("connection failed (TLS=%v): %v", useTLS, err))
 	}
 	defer func() { _ = conn.Close() }()
 
 	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err != nil {
-		return badStatusRow(fmt.Sprintf("failed to set deadline: %v", err))
+		return badStatus(fmt.Sprintf("failed to set deadline: %v", err))
 	}
 
 	reader := bufio.NewReader(conn)
@@ -612,12 +748,12 @@ func checkCacheStatus(service config.ServiceDefinition, serviceID, address strin
 		}
 
 		if _, err = conn.Write([]byte(authCmd)); err != nil {
-			return badStatusRow(fmt.Sprintf("failed to send AUTH command: %v", err))
+			return badStatus(fmt.Sprintf("failed to send AUTH command: %v", err))
 		}
 
 		response, err := reader.ReadString('\n')
 		if err != nil {
-			return badStatusRow(fmt.Sprintf("AUTH read error: %v", err))
+			return badStatus(fmt.Sprintf("AUTH read error: %v", err))
 		}
 
 		if !strings.HasPrefix(response, "+OK") {
@@ -632,26 +768,26 @@ func checkCacheStatus(service config.ServiceDefinition, serviceID, address strin
 					}
 				}
 			}
-			return badStatusRow(fmt.Sprintf("AUTH failed: %s", strings.TrimSpace(response)))
+			return badStatus(fmt.Sprintf("AUTH failed: %s", strings.TrimSpace(response)))
 		}
 	}
 
 authSuccess:
 	// 3. Ping check
 	if _, err = conn.Write([]byte("PING\r\n")); err != nil {
-		return badStatusRow(fmt.Sprintf("PING write failed: %v", err))
+		return badStatus(fmt.Sprintf("PING write failed: %v", err))
 	}
 	response, err := reader.ReadString('\n')
 	if err != nil {
-		return badStatusRow(fmt.Sprintf("PING read failed: %v", err))
+		return badStatus(fmt.Sprintf("PING read failed: %v", err))
 	}
 	if !strings.HasPrefix(response, "+PONG") {
-		return badStatusRow(fmt.Sprintf("PING response invalid: %s", strings.TrimSpace(response)))
+		return badStatus(fmt.Sprintf("PING response invalid: %s", strings.TrimSpace(response)))
 	}
 
-	// 3. Get Version and Uptime
-	version := "N/A"
-	uptime := "N/A"
+	status.Status = "OK"
+
+	// 4. Get Version and Uptime
 
 	// Reset deadline for INFO/Version fetch
 	if err := conn.SetDeadline(time.Now().Add(5 * time.Second)); err == nil {
@@ -673,7 +809,7 @@ authSuccess:
 				versionRe := regexp.MustCompile(`(redis_version|valkey_version):([0-9]+\.[0-9]+\.[0-9]+)`)
 				versionMatches := versionRe.FindStringSubmatch(infoStr)
 				if len(versionMatches) >= 3 && versionMatches[2] != "" {
-					version = versionMatches[2]
+					status.Version = versionMatches[2]
 				}
 
 				// Find uptime_in_seconds and format it
@@ -682,43 +818,29 @@ authSuccess:
 				if len(uptimeMatches) >= 2 {
 					uptimeSeconds := 0
 					_, _ = fmt.Sscanf(uptimeMatches[1], "%d", &uptimeSeconds)
-					uptime = formatUptime(uptimeSeconds)
+					status.Uptime = formatUptime(uptimeSeconds)
 				}
 			}
 		}
 	}
 
 	// Get CPU and memory from systemd for local Redis services
-	cpu := "N/A"
-	mem := "N/A"
 	if isLocalAddress(service.Domain) {
 		// Try common Redis systemd service names
-		cpu = getSystemdServiceCPU("redis")
-		mem = getSystemdServiceMemory("redis")
+		status.CPU = getSystemdServiceCPU("redis")
+		status.Memory = getSystemdServiceMemory("redis")
 		// If "redis" doesn't work, try "redis-server"
-		if cpu == "N/A" {
-			cpu = getSystemdServiceCPU("redis-server")
-			mem = getSystemdServiceMemory("redis-server")
+		if status.CPU == "N/A" {
+			status.CPU = getSystemdServiceCPU("redis-server")
+			status.Memory = getSystemdServiceMemory("redis-server")
 		}
 	}
 
-	// Successful status row
-	return []string{
-		serviceID,
-		address,
-		colorizeNA(ui.Truncate(version, maxVersionLen)),
-		colorizeNA("--"), // BRANCH
-		colorizeNA("--"), // COMMIT
-		colorizeStatus("OK"),
-		colorizeNA(uptime),
-		colorizeNA(cpu),
-		colorizeNA(mem),
-	}
+	return status
 }
 
 // checkHTTPStatus checks a service via its new, unified /service endpoint
-// Returns 9 columns: SERVICE, ADDRESS, VERSION, BRANCH, COMMIT, STATUS, UPTIME, CPU, MEM
-func checkHTTPStatus(service config.ServiceDefinition, serviceID, address string) ui.TableRow {
+func checkHTTPStatus(service config.ServiceDefinition) ServiceStatus {
 	// A struct to unmarshal the necessary fields for the status table
 	type serviceReport struct {
 		Version struct {
@@ -742,31 +864,25 @@ func checkHTTPStatus(service config.ServiceDefinition, serviceID, address string
 		} `json:"metrics"`
 	}
 
-	badStatusRow := func() ui.TableRow {
-		return []string{
-			serviceID,
-			address,
-			colorizeNA("N/A"),     // VERSION
-			colorizeNA("--"),      // BRANCH
-			colorizeNA("--"),      // COMMIT
-			colorizeStatus("BAD"), // STATUS
-			colorizeNA("N/A"),     // UPTIME
-			colorizeNA("N/A"),     // CPU
-			colorizeNA("N/A"),     // MEM
-		}
+	status := newServiceStatus(service)
+	badStatus := func(reason string) ServiceStatus {
+		status.Status = "BAD"
+		status.Error = reason
+		return status
 	}
 
 	// Get the full JSON service report
 	jsonResponse, err := utils.GetHTTPServiceReport(service)
 	if err != nil {
-		return badStatusRow()
+		return badStatus(err.Error())
 	}
 
 	var report serviceReport
 	if err := json.Unmarshal([]byte(jsonResponse), &report); err != nil {
 		// If parsing fails, return BAD status
-		return badStatusRow()
+		return badStatus(fmt.Sprintf("failed to parse service report: %v", err))
 	}
+	status.Report = json.RawMessage(jsonResponse)
 
 	// Extract remote version info
 	branch := report.Version.Obj.Branch
@@ -789,33 +905,29 @@ func checkHTTPStatus(service config.ServiceDefinition, serviceID, address string
 		commit = commit[:7]
 	}
 
-	// Use the parsed data for the table
-	shortVersion := utils.ParseToShortVersion(report.Version.Str)
-
-	// Cleanup Uptime for display
-	uptime := ui.Truncate(report.Health.Uptime, maxUptimeLen)
+	if shortVersion := utils.ParseToShortVersion(report.Version.Str); shortVersion != "" {
+		status.Version = shortVersion
+	}
+	if branch != "" {
+		status.Branch = branch
+	}
+	if commit != "" {
+		status.Commit = commit
+	}
+	status.Status = strings.ToUpper(report.Health.Status)
+	if report.Health.Uptime != "" {
+		status.Uptime = report.Health.Uptime
+	}
 
 	// Format CPU and Memory metrics
-	cpu := "N/A"
-	mem := "N/A"
 	if report.Metrics.CPU.Avg != nil {
-		cpu = fmt.Sprintf("%.1f%%", *report.Metrics.CPU.Avg)
+		status.CPU = fmt.Sprintf("%.1f%%", *report.Metrics.CPU.Avg)
 	}
 	if report.Metrics.Memory.Avg != nil {
-		mem = fmt.Sprintf("%.1f MB", *report.Metrics.Memory.Avg)
+		status.Memory = fmt.Sprintf("%.1f MB", *report.Metrics.Memory.Avg)
 	}
 
-	return []string{
-		serviceID,
-		address,
-		colorizeNA(ui.Truncate(shortVersion, maxVersionLen)),
-		colorizeNA(ui.Truncate(branch, maxBranchLen)),
-		colorizeNA(ui.Truncate(commit, maxCommitLen)),
-		colorizeStatus(strings.ToUpper(report.Health.Status)),
-		colorizeNA(uptime),
-		colorizeNA(cpu),
-		colorizeNA(mem),
-	}
+	return status
 }
 
 // colorizeStatus applies color coding to the status string.
@@ -825,6 +937,8 @@ func colorizeStatus(status string) string {
 		return fmt.Sprintf("%s%s%s", ui.ColorGreen, status, ui.ColorReset)
 	case "BAD":
 		return fmt.Sprintf("%s%s%s", ui.ColorBrightRed, status, ui.ColorReset)
+	case "TIMEOUT":
+		return fmt.Sprintf("%s%s%s", ui.ColorYellow, status, ui.ColorReset)
 	case "N/A":
 		return fmt.Sprintf("%s%s%s", ui.ColorDarkGray, status, ui.ColorReset)
 	default:
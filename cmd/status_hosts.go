@@ -0,0 +1,151 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+const (
+	// localHostName labels this machine's rows in 'dex status --all-hosts'
+	localHostName = "local"
+	// remoteStatusTimeout bounds a remote 'dex status --format json', which itself runs for up to statusDeadline
+	remoteStatusTimeout = statusDeadline + 15*time.Second
+	maxHostLen          = 20
+)
+
+// HostServiceStatus is a service status tagged with the host it was checked on.
+type HostServiceStatus struct {
+	Host string `json:"host"`
+	ServiceStatus
+}
+
+// TableRow renders the status as the service table with a leading HOST column.
+func (s HostServiceStatus) TableRow() ui.TableRow {
+	return append(ui.TableRow{ui.Truncate(s.Host, maxHostLen)}, s.ServiceStatus.TableRow()...)
+}
+
+// statusAllHosts checks services locally and on every server in server-map.json,
+// rendering the results as one combined report.
+func statusAllHosts(serviceShortName, format string) error {
+	hosts, err := utils.LoadRemoteHosts()
+	if err != nil {
+		return err
+	}
+
+	results := make([][]HostServiceStatus, len(hosts)+1)
+	var wg sync.WaitGroup
+	for i, host := range hosts {
+		wg.Add(1)
+		go func(i int, host utils.RemoteHost) {
+			defer wg.Done()
+			results[i+1] = fetchRemoteStatuses(host, serviceShortName)
+		}(i, host)
+	}
+
+	services, err := selectStatusServices(serviceShortName)
+	if err != nil {
+		results[0] = []HostServiceStatus{{Host: localHostName, ServiceStatus: failedHostStatus(localHostName, err)}}
+	} else {
+		for _, status := range runStatusChecks(context.Background(), services, statusCheckTimeout) {
+			results[0] = append(results[0], HostServiceStatus{Host: localHostName, ServiceStatus: status})
+		}
+	}
+	wg.Wait()
+
+	var statuses []HostServiceStatus
+	for _, hostStatuses := range results {
+		statuses = append(statuses, hostStatuses...)
+	}
+
+	switch format {
+	case "json":
+		jsonData, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return fmt.Errorf("failed to encode status report: %w", err)
+		}
+		ui.PrintRaw(string(jsonData) + "\n")
+		return nil
+	case "yaml":
+		data, err := utils.MarshalYAML(statuses)
+		if err != nil {
+			return fmt.Errorf("failed to encode status report: %w", err)
+		}
+		ui.PrintRaw(string(data))
+		return nil
+	}
+
+	table := ui.NewTable([]string{"HOST", "SERVICE", "ADDRESS", "VERSION", "BRANCH", "COMMIT", "STATUS", "UPTIME", "CPU", "MEM"})
+	for _, status := range statuses {
+		table.AddRow(status.TableRow())
+	}
+	table.Render()
+
+	for _, status := range statuses {
+		if status.Service == "-" && status.Error != "" {
+			ui.PrintWarning(fmt.Sprintf("%s: %s", status.Host, status.Error))
+		}
+	}
+	return nil
+}
+
+// fetchRemoteStatuses runs 'dex status --format json' on a host. An unreachable host is
+// reported as a single BAD row so it still shows up in the combined table.
+func fetchRemoteStatuses(host utils.RemoteHost, serviceShortName string) []HostServiceStatus {
+	ctx, cancel := context.WithTimeout(context.Background(), remoteStatusTimeout)
+	defer cancel()
+
+	// Only stdout carries the report; warnings on stderr are kept apart for error messages
+	var stdout, stderr bytes.Buffer
+	err := utils.RunRemoteDex(ctx, host, []string{"status", serviceShortName, "--format", "json", "--no-event"}, &stdout, &stderr, false)
+	var statuses []ServiceStatus
+	if err == nil {
+		err = decodeRemoteStatuses(stdout.Bytes(), &statuses)
+	} else if detail := lastLine(stdout.String()); detail != "" {
+		// dex prints its own errors on stdout before exiting
+		err = fmt.Errorf("%w (%s)", err, detail)
+	}
+	if err != nil {
+		if detail := lastLine(stderr.String()); detail != "" {
+			err = fmt.Errorf("%w (%s)", err, detail)
+		}
+		return []HostServiceStatus{{Host: host.Name, ServiceStatus: failedHostStatus(host.Address(), err)}}
+	}
+
+	tagged := make([]HostServiceStatus, len(statuses))
+	for i, status := range statuses {
+		tagged[i] = HostServiceStatus{Host: host.Name, ServiceStatus: status}
+	}
+	return tagged
+}
+
+// failedHostStatus is the single BAD row shown for a host whose services could not be checked.
+func failedHostStatus(address string, err error) ServiceStatus {
+	return ServiceStatus{Service: "-", Address: address, Version: "N/A", Branch: "N/A", Commit: "N/A", Status: "BAD", Uptime: "N/A", CPU: "N/A", Memory: "N/A", Error: err.Error()}
+}
+
+// decodeRemoteStatuses decodes the JSON status array a remote dex wrote to stdout. The report
+// must be the whole output, apart from the blank padding lines dex prints around commands.
+func decodeRemoteStatuses(output []byte, statuses *[]ServiceStatus) error {
+	output = bytes.TrimSpace(output)
+	if len(output) == 0 {
+		return fmt.Errorf("remote dex did not return a status report")
+	}
+	if err := json.Unmarshal(output, statuses); err != nil {
+		return fmt.Errorf("invalid status report from remote dex: %w", err)
+	}
+	return nil
+}
+
+// lastLine returns the last non-empty line of s, without colors.
+func lastLine(s string) string {
+	lines := strings.Split(strings.TrimSpace(ui.StripANSI(s)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1])
+}
@@ -0,0 +1,166 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/ui"
+)
+
+const (
+	defaultWatchInterval = 5 * time.Second
+	// watchHistoryLen is the number of past results shown in the HISTORY column
+	watchHistoryLen = 10
+	// watchHighlightRefreshes is how many refreshes a changed cell stays highlighted
+	watchHighlightRefreshes = 3
+	// watchMaxChanges is the number of recent state changes listed under the table
+	watchMaxChanges = 5
+)
+
+// watchedService tracks what the dashboard has seen of a single service across refreshes.
+type watchedService struct {
+	last          ServiceStatus
+	seen          bool
+	history       []string
+	statusChanged int // refresh number of the last status change
+	versionChange int // refresh number of the last version change
+}
+
+// parseWatchInterval accepts a Go duration ("2s", "1m") or a plain number of seconds.
+func parseWatchInterval(value string) (time.Duration, error) {
+	interval, err := time.ParseDuration(value)
+	if err != nil {
+		seconds, convErr := strconv.Atoi(value)
+		if convErr != nil {
+			return 0, fmt.Errorf("invalid interval '%s': use a duration such as 5s or 1m", value)
+		}
+		interval = time.Duration(seconds) * time.Second
+	}
+	if interval < time.Second {
+		return 0, fmt.Errorf("interval must be at least 1s, got %s", interval)
+	}
+	return interval, nil
+}
+
+// watchStatus redraws the status table every interval until interrupted.
+func watchStatus(services []config.ServiceDefinition, interval time.Duration) error {
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// A dashboard can run for hours; don't accumulate every redraw in the event capture buffer
+	ui.StopCapturing()
+
+	watched := make(map[string]*watchedService)
+	var changes []string
+
+	for refresh := 1; ; refresh++ {
+		statuses := runStatusChecks(ctx, services, statusCheckTimeout)
+		if ctx.Err() != nil {
+			break
+		}
+
+		now := time.Now().Format("15:04:05")
+		var rows []ui.TableRow
+		for _, status := range statuses {
+			w, ok := watched[status.ID]
+			if !ok {
+				w = &watchedService{}
+				watched[status.ID] = w
+			}
+
+			if w.seen {
+				if w.last.Status != status.Status {
+					w.statusChanged = refresh
+					changes = append(changes, fmt.Sprintf("%s  %s: %s → %s", now, status.Service, w.last.Status, status.Status))
+				}
+				if w.last.Version != status.Version {
+					w.versionChange = refresh
+					changes = append(changes, fmt.Sprintf("%s  %s: version %s → %s", now, status.Service, w.last.Version, status.Version))
+				}
+			}
+			w.last = status
+			w.seen = true
+			w.history = append(w.history, status.Status)
+			if len(w.history) > watchHistoryLen {
+				w.history = w.history[len(w.history)-watchHistoryLen:]
+			}
+
+			row := status.TableRow()
+			if w.statusChanged > 0 && refresh-w.statusChanged < watchHighlightRefreshes {
+				row[5] = highlightChange(row[5])
+			}
+			if w.versionChange > 0 && refresh-w.versionChange < watchHighlightRefreshes {
+				row[2] = highlightChange(row[2])
+			}
+			rows = append(rows, append(row, formatStatusHistory(w.history)))
+		}
+		if len(changes) > watchMaxChanges {
+			changes = changes[len(changes)-watchMaxChanges:]
+		}
+
+		renderWatchFrame(rows, changes, interval, now)
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(interval):
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+
+	fmt.Println()
+	ui.PrintInfo("Stopped watching.")
+	return nil
+}
+
+// renderWatchFrame clears the terminal and draws one refresh of the dashboard.
+func renderWatchFrame(rows []ui.TableRow, changes []string, interval time.Duration, timestamp string) {
+	ui.PrintRaw("\033[H\033[2J")
+	ui.PrintRaw(fmt.Sprintf("%sdex status --watch  every %s  last refresh %s  (Ctrl+C to exit)%s\n\n", ui.ColorDarkGray, interval, timestamp, ui.ColorReset))
+
+	table := ui.CreateServiceTable(nil)
+	table.Columns = append(table.Columns, ui.TableColumn{Header: "HISTORY", Width: len("HISTORY")})
+	for _, row := range rows {
+		table.AddRow(row)
+	}
+	table.Render()
+
+	if len(changes) > 0 {
+		fmt.Println()
+		ui.PrintRaw(fmt.Sprintf("%sRecent changes:%s\n", ui.ColorCyan, ui.ColorReset))
+		for _, change := range changes {
+			ui.PrintRaw(fmt.Sprintf("  %s\n", change))
+		}
+	}
+}
+
+// highlightChange marks a cell whose value changed recently.
+func highlightChange(cell string) string {
+	return ui.Colorize("*", ui.ColorYellow) + cell
+}
+
+// formatStatusHistory renders past results oldest-first as a strip of coloured dots.
+func formatStatusHistory(history []string) string {
+	var b strings.Builder
+	for _, status := range history {
+		switch status {
+		case "OK", "HEALTHY":
+			b.WriteString(ui.Colorize("●", ui.ColorGreen))
+		case "BAD":
+			b.WriteString(ui.Colorize("●", ui.ColorBrightRed))
+		case "N/A", "":
+			b.WriteString(ui.Colorize("·", ui.ColorDarkGray))
+		default:
+			b.WriteString(ui.Colorize("●", ui.ColorYellow))
+		}
+	}
+	return b.String()
+}
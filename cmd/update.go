@@ -3,13 +3,17 @@ package cmd
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
 	"os"
 	"os/exec"
+	"os/signal"
 	"path/filepath"
+	"sort"
 	"strings"
+	"syscall"
 
 	"github.com/EasterCompany/dex-cli/config"
 	"github.com/EasterCompany/dex-cli/release"
@@ -22,20 +26,152 @@ const (
 	TempDir     = "/tmp/dex-update"
 )
 
+// updateRequest describes which release 'dex update' should install.
+type updateRequest struct {
+	// Channel is the release channel to follow; empty means the environment's default
+	Channel string
+	// Version pins an exact release such as 2.3.0, overriding the channel
+	Version string
+	// Check reports what would change without changing anything
+	Check bool
+	// SkipVerify trusts data.json without checking its signature
+	SkipVerify bool
+}
+
 // Update performs different update strategies based on environment
 func Update(args []string) error {
-	// Wipe Redis to ensure a clean state
-	if err := utils.WipeRedis(context.Background()); err != nil {
-		ui.PrintWarning(fmt.Sprintf("Failed to wipe Redis: %v", err))
+	req, saveChannel, err := parseUpdateArgs(args)
+	if err != nil || req == nil {
+		return err
+	}
+
+	if saveChannel && !req.Check {
+		if err := saveUpdateChannel(req.Channel); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to save update channel: %v", err))
+		} else {
+			ui.PrintInfo(fmt.Sprintf("Following the %s channel from now on.", req.Channel))
+		}
+	}
+
+	if !req.Check {
+		// Wipe Redis to ensure a clean state
+		if err := utils.WipeRedis(context.Background()); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to wipe Redis: %v", err))
+		}
 	}
 
 	// Check if this is a developer environment
 	isDev := isDeveloperEnvironment()
 
 	if isDev {
-		return updateDeveloper()
+		return updateDeveloper(req)
+	}
+	return updateUser(req)
+}
+
+// parseUpdateArgs reads the update flags. It returns a nil request if help was shown, and
+// whether --channel was given explicitly (and so should be remembered).
+func parseUpdateArgs(args []string) (*updateRequest, bool, error) {
+	req := &updateRequest{}
+	saveChannel := false
+	for i := 0; i < len(args); i++ {
+		switch arg := args[i]; arg {
+		case "--help", "-h":
+			ui.PrintHeader("Update Command Help")
+			ui.PrintInfo("Usage: dex update [--channel stable|beta|dev] [--version X.Y.Z] [--check] [--insecure-skip-verify]")
+			fmt.Println()
+			ui.PrintInfo("Description:")
+			ui.PrintInfo("  Installs the latest release on your channel. Developers rebuild from source;")
+			ui.PrintInfo("  on the dev channel that is HEAD, otherwise the channel's release tag.")
+			fmt.Println()
+			ui.PrintInfo("Flags:")
+			ui.PrintInfo("  --channel <name>    Follow stable (majors), beta (majors and minors) or dev.")
+			ui.PrintInfo("                      The channel is saved in options.json for later updates.")
+			ui.PrintInfo("  --version <X.Y.Z>   Install an exact release from data.json.")
+			ui.PrintInfo("  --check             Report what would change without changing anything.")
+			ui.PrintInfo("  --insecure-skip-verify")
+			ui.PrintInfo("                      Install even if data.json is unsigned or its signature does not")
+			ui.PrintInfo("                      match a trusted key. Only for recovering from a broken release.")
+			return nil, false, nil
+		case "--channel":
+			if i+1 >= len(args) {
+				return nil, false, fmt.Errorf("--channel requires a value (%s)", strings.Join(release.Channels, ", "))
+			}
+			i++
+			req.Channel = args[i]
+			saveChannel = true
+		case "--version":
+			if i+1 >= len(args) {
+				return nil, false, fmt.Errorf("--version requires a release (e.g. 2.3.0)")
+			}
+			i++
+			req.Version = strings.TrimPrefix(args[i], "v")
+		case "--check":
+			req.Check = true
+		case "--insecure-skip-verify":
+			req.SkipVerify = true
+		default:
+			return nil, false, fmt.Errorf("unknown update argument: %s", arg)
+		}
+	}
+
+	if req.Channel != "" && !release.IsChannel(req.Channel) {
+		return nil, false, fmt.Errorf("unknown release channel '%s' (expected %s)", req.Channel, strings.Join(release.Channels, ", "))
+	}
+	if req.Version != "" && extractShortVersion(req.Version) != req.Version {
+		return nil, false, fmt.Errorf("invalid version '%s': expected X.Y.Z", req.Version)
+	}
+	if req.Channel == "" {
+		req.Channel = savedUpdateChannel()
+	}
+	return req, saveChannel, nil
+}
+
+// savedUpdateChannel returns the channel stored in options.json, if any.
+func savedUpdateChannel() string {
+	options, err := config.LoadOptionsConfig()
+	if err != nil {
+		return ""
+	}
+	return options.Update.Channel
+}
+
+// saveUpdateChannel stores the channel in options.json so later updates stay on it.
+func saveUpdateChannel(channel string) error {
+	options, err := config.LoadOptionsConfig()
+	if err != nil {
+		if !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		options = config.DefaultOptionsConfig()
+	}
+	options.Update.Channel = channel
+	return config.SaveOptionsConfig(options)
+}
+
+// resolveUpdateRelease picks the release to install: the pinned version if one was given,
+// otherwise the latest on the channel. It returns the full version when known, and the
+// short X.Y.Z key into data.Releases.
+func resolveUpdateRelease(data *release.ReleaseData, req *updateRequest, channel string) (fullVersion, shortVersion string, err error) {
+	if req.Version != "" {
+		if _, exists := data.Releases[req.Version]; !exists {
+			return "", "", fmt.Errorf("release %s not found in data.json (available: %s)", req.Version, strings.Join(data.SortedVersions(), ", "))
+		}
+		return "", req.Version, nil
+	}
+
+	fullVersion, err = data.Latest.ForChannel(channel)
+	if err != nil {
+		return "", "", err
+	}
+	if fullVersion == "" {
+		return "", "", fmt.Errorf("no %s version found in data.json", channel)
+	}
+	shortVersion = extractShortVersion(fullVersion)
+	if shortVersion == "" {
+		return "", "", fmt.Errorf("failed to parse version: %s", fullVersion)
 	}
-	return updateUser()
+	return fullVersion, shortVersion, nil
 }
 
 // isDeveloperEnvironment checks if ~/EasterCompany exists
@@ -47,23 +183,37 @@ func isDeveloperEnvironment() bool {
 
 // updateDeveloper performs nuclear fresh install from source
 // SCORCHED EARTH: Clone fresh → build via Makefile → install ALL binaries
-func updateDeveloper() error {
+// The dev channel builds HEAD; other channels and pinned versions build their release tag.
+func updateDeveloper(req *updateRequest) error {
+	channel := req.Channel
+	if channel == "" {
+		channel = release.ChannelDev
+	}
+
 	ui.PrintHeader("Developer Update - Nuclear Fresh Install")
-	ui.PrintWarning("Cloning fresh source and rebuilding everything from scratch...")
 
 	// Fetch data.json
-	ui.PrintInfo("Fetching latest dev version from easter.company...")
-	data, err := fetchReleaseData()
+	ui.PrintInfo(fmt.Sprintf("Fetching latest %s version from easter.company...", channel))
+	data, err := fetchReleaseData(req.SkipVerify)
 	if err != nil {
 		return fmt.Errorf("failed to fetch release data: %w", err)
 	}
 
-	if data.Latest.Dev == "" {
-		return fmt.Errorf("no dev version found in data.json")
+	ref := "" // Empty clones HEAD
+	if req.Version != "" || channel != release.ChannelDev {
+		_, shortVersion, err := resolveUpdateRelease(data, req, channel)
+		if err != nil {
+			return err
+		}
+		ref = shortVersion
+		ui.PrintInfo(fmt.Sprintf("Target release: %s", shortVersion))
+	} else {
+		if data.Latest.Dev == "" {
+			return fmt.Errorf("no dev version found in data.json")
+		}
+		ui.PrintInfo(fmt.Sprintf("Latest dev version: %s", data.Latest.Dev))
 	}
 
-	ui.PrintInfo(fmt.Sprintf("Latest dev version: %s", data.Latest.Dev))
-
 	// Get list of services to update
 	services := config.GetAllServices()
 	var buildableServices []config.ServiceDefinition
@@ -73,6 +223,20 @@ func updateDeveloper() error {
 		}
 	}
 
+	if req.Check {
+		source := "HEAD"
+		if ref != "" {
+			source = "tag " + ref
+		}
+		ui.PrintInfo(fmt.Sprintf("Would rebuild %d services from %s:", len(buildableServices), source))
+		for _, service := range buildableServices {
+			ui.PrintInfo(fmt.Sprintf("  %s (currently %s)", service.ShortName, utils.GetBinaryVersion(service)))
+		}
+		ui.PrintInfo("No changes made (--check).")
+		return nil
+	}
+
+	ui.PrintWarning("Cloning fresh source and rebuilding everything from scratch...")
 	ui.PrintInfo(fmt.Sprintf("Updating %d services from source...", len(buildableServices)))
 
 	// Clean temp directory
@@ -84,7 +248,7 @@ func updateDeveloper() error {
 
 	// Process each service
 	for _, service := range buildableServices {
-		if err := updateServiceFromSource(service); err != nil {
+		if err := updateServiceFromSource(service, ref); err != nil {
 			ui.PrintWarning(fmt.Sprintf("Failed to update %s: %v", service.ShortName, err))
 			ui.PrintWarning("Continuing with other services...")
 		}
@@ -96,8 +260,9 @@ func updateDeveloper() error {
 	return nil
 }
 
-// updateServiceFromSource clones, builds via Makefile, and installs ALL binaries
-func updateServiceFromSource(service config.ServiceDefinition) error {
+// updateServiceFromSource clones, builds via Makefile, and installs ALL binaries.
+// A non-empty ref clones that tag instead of HEAD.
+func updateServiceFromSource(service config.ServiceDefinition, ref string) error {
 	ui.PrintInfo(fmt.Sprintf("Updating %s...", service.ShortName))
 
 	// Construct GitHub URL
@@ -106,11 +271,23 @@ func updateServiceFromSource(service config.ServiceDefinition) error {
 
 	// Clone fresh (depth 1 for speed)
 	ui.PrintInfo(fmt.Sprintf("  Cloning %s...", service.ID))
-	cloneCmd := exec.Command("git", "clone", "--depth", "1", repoURL, tempServiceDir)
+	cloneArgs := []string{"clone", "--depth", "1"}
+	if ref != "" {
+		cloneArgs = append(cloneArgs, "--branch", ref)
+	}
+	cloneCmd := exec.Command("git", append(cloneArgs, repoURL, tempServiceDir)...)
 	if output, err := cloneCmd.CombinedOutput(); err != nil {
 		return fmt.Errorf("git clone failed:\n%s", string(output))
 	}
 
+	// Keep the binary being replaced so it can be restored with 'dex rollback', or
+	// automatically if the new one is unhealthy
+	wasActive := utils.IsServiceActive(service)
+	previous, err := utils.SnapshotBinary(service)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("  Could not keep the current %s for rollback: %v", service.ShortName, err))
+	}
+
 	// Build via Makefile (source of truth for ALL binaries)
 	ui.PrintInfo(fmt.Sprintf("  Building %s via Makefile...", service.ShortName))
 	buildCmd := exec.Command("make", "build")
@@ -123,63 +300,122 @@ func updateServiceFromSource(service config.ServiceDefinition) error {
 	// Makefile's "make install" target handles copying ALL binaries to ~/Dexter/bin
 	// So if the build succeeded, binaries are already installed!
 
+	// Running services are restarted onto the new binary and rolled back if it is unhealthy
+	if wasActive {
+		if err := utils.RestartOrRollback(context.Background(), service, previous); err != nil {
+			return err
+		}
+		ui.PrintSuccess(fmt.Sprintf("  ✓ %s restarted and healthy", service.ShortName))
+	}
+
 	ui.PrintSuccess(fmt.Sprintf("  ✓ %s updated (all binaries installed)", service.ShortName))
 	return nil
 }
 
 // updateUser downloads and installs binaries from easter.company
-func updateUser() error {
+func updateUser(req *updateRequest) error {
+	channel := req.Channel
+	if channel == "" {
+		channel = release.ChannelStable
+	}
+
 	ui.PrintHeader("User Update - Download Latest Release")
 
 	// Fetch data.json
 	ui.PrintInfo("Checking for updates...")
-	data, err := fetchReleaseData()
+	data, err := fetchReleaseData(req.SkipVerify)
 	if err != nil {
 		return fmt.Errorf("failed to fetch release data: %w", err)
 	}
 
-	if data.Latest.User == "" {
-		return fmt.Errorf("no user version found in data.json")
+	latestVersion, shortVersion, err := resolveUpdateRelease(data, req, channel)
+	if err != nil {
+		return err
 	}
 
 	// Get current version
 	currentVersion := RunningVersion
-	latestVersion := data.Latest.User
 
 	ui.PrintInfo(fmt.Sprintf("Current version: %s", currentVersion))
-	ui.PrintInfo(fmt.Sprintf("Latest version:  %s", latestVersion))
+	if req.Version != "" {
+		ui.PrintInfo(fmt.Sprintf("Pinned version:  %s", shortVersion))
+	} else {
+		ui.PrintInfo(fmt.Sprintf("Latest version:  %s (%s channel)", latestVersion, channel))
+	}
 
 	// Compare versions
-	if currentVersion == latestVersion {
-		ui.PrintSuccess("Already running the latest version!")
+	if currentVersion == latestVersion || (req.Version != "" && extractShortVersion(currentVersion) == shortVersion) {
+		ui.PrintSuccess("Already running the requested version!")
 		return nil
 	}
 
-	ui.PrintInfo("Update available! Downloading binaries...")
-
-	// Extract short version (e.g., "2.1.0" from full version string)
-	shortVersion := extractShortVersion(latestVersion)
-	if shortVersion == "" {
-		return fmt.Errorf("failed to parse version: %s", latestVersion)
-	}
-
 	// Get release info to find all binaries
 	releaseInfo, exists := data.Releases[shortVersion]
 	if !exists {
 		return fmt.Errorf("release %s not found in data.json", shortVersion)
 	}
 
-	// Download and install each binary from the release
-	for serviceName, platforms := range releaseInfo.Binaries {
-		// We only support linux-amd64
-		binary, exists := platforms["linux-amd64"]
+	if err := checkReleasePlatform(releaseInfo, shortVersion); err != nil {
+		return err
+	}
+
+	if req.Check {
+		return reportUpdatePlan(releaseInfo)
+	}
+
+	ui.PrintInfo("Update available! Downloading binaries...")
+
+	configuredServices, err := utils.GetConfiguredServices()
+	if err != nil {
+		return fmt.Errorf("failed to get configured services: %w", err)
+	}
+
+	platform := release.HostPlatform().String()
+	var downloads []*binaryDownload
+	for _, serviceName := range sortedKeys(releaseInfo.Binaries) {
+		binary, exists := releaseInfo.Binaries[serviceName][platform]
 		if !exists {
-			ui.PrintWarning(fmt.Sprintf("No linux-amd64 binary for %s", serviceName))
+			ui.PrintWarning(fmt.Sprintf("No %s binary for %s", platform, serviceName))
 			continue
 		}
 
-		if err := downloadAndInstallBinary(serviceName, binary.Path, binary.Checksum); err != nil {
-			ui.PrintWarning(fmt.Sprintf("Failed to update %s: %v", serviceName, err))
+		service, known := config.FindService(configuredServices, serviceName)
+		if !known {
+			service = config.ServiceDefinition{ID: filepath.Base(binary.Path), ShortName: serviceName}
+		}
+		downloads = append(downloads, newBinaryDownload(serviceName, service, binary))
+	}
+
+	// Everything is downloaded before anything is installed, so an interrupted update leaves
+	// the installed binaries untouched and the partial files ready to resume
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	downloadBinaries(ctx, downloads)
+	if ctx.Err() != nil {
+		return fmt.Errorf("update interrupted; partial downloads are kept and will resume on the next 'dex update'")
+	}
+
+	for _, download := range downloads {
+		if download.err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to download %s: %v", download.name, download.err))
+			continue
+		}
+
+		service := download.service
+		wasActive := utils.IsServiceActive(service)
+		previous, err := installDownloadedBinary(download)
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to update %s: %v", download.name, err))
+			continue
+		}
+
+		// Running services are restarted onto the new binary and rolled back if it is unhealthy
+		if wasActive {
+			if err := utils.RestartOrRollback(context.Background(), service, previous); err != nil {
+				ui.PrintWarning(err.Error())
+				continue
+			}
+			ui.PrintSuccess(fmt.Sprintf("  ✓ %s restarted and healthy", download.name))
 		}
 	}
 
@@ -189,95 +425,103 @@ func updateUser() error {
 	return nil
 }
 
-// downloadAndInstallBinary downloads and installs a single binary with checksum verification
-func downloadAndInstallBinary(serviceName, binaryPath, expectedChecksum string) error {
-	ui.PrintInfo(fmt.Sprintf("Downloading %s...", serviceName))
-
-	// Construct download URL
-	url := fmt.Sprintf("https://easter.company%s", binaryPath)
-
-	// Download to temp file
-	binaryName := filepath.Base(binaryPath)
-	tempFile := filepath.Join(os.TempDir(), binaryName)
-	if err := downloadFile(url, tempFile); err != nil {
-		return err
-	}
-	defer func() { _ = os.Remove(tempFile) }()
-
-	// Verify checksum
-	actualChecksum, err := release.CalculateChecksum(tempFile)
-	if err != nil {
-		return fmt.Errorf("failed to calculate checksum: %w", err)
+// checkReleasePlatform fails when a release has no binaries at all for this machine's platform.
+func checkReleasePlatform(releaseInfo release.ReleaseInfo, version string) error {
+	platform := release.HostPlatform().String()
+	available := make(map[string]bool)
+	for _, platforms := range releaseInfo.Binaries {
+		if _, exists := platforms[platform]; exists {
+			return nil
+		}
+		for name := range platforms {
+			available[name] = true
+		}
 	}
-
-	if actualChecksum != expectedChecksum {
-		return fmt.Errorf("checksum mismatch (expected %s, got %s)", expectedChecksum, actualChecksum)
+	if len(available) == 0 {
+		return fmt.Errorf("release %s has no binaries", version)
 	}
+	return fmt.Errorf("release %s has no %s build (available: %s)", version, platform, strings.Join(sortedKeys(available), ", "))
+}
 
-	// Install to ~/Dexter/bin
-	destPath := filepath.Join(os.Getenv("HOME"), "Dexter", "bin", binaryName)
-	if err := copyFile(tempFile, destPath); err != nil {
-		return err
+// reportUpdatePlan lists which installed binaries differ from a release, without downloading anything.
+func reportUpdatePlan(releaseInfo release.ReleaseInfo) error {
+	platform := release.HostPlatform().String()
+	table := ui.NewTable([]string{"BINARY", "SIZE", "CHANGE"})
+	changes := 0
+	for _, serviceName := range sortedKeys(releaseInfo.Binaries) {
+		binary, exists := releaseInfo.Binaries[serviceName][platform]
+		if !exists {
+			table.AddRow(ui.TableRow{serviceName, "-", ui.Colorize("no "+platform+" build", ui.ColorDarkGray)})
+			continue
+		}
+		installed := filepath.Join(os.Getenv("HOME"), "Dexter", "bin", filepath.Base(binary.Path))
+		change := ui.Colorize("install", ui.ColorGreen)
+		if checksum, err := release.CalculateChecksum(installed); err == nil {
+			if checksum == binary.Checksum {
+				change = ui.Colorize("unchanged", ui.ColorDarkGray)
+				table.AddRow(ui.TableRow{filepath.Base(binary.Path), utils.FormatBytes(binary.Size), change})
+				continue
+			}
+			change = ui.Colorize("update", ui.ColorYellow)
+		}
+		changes++
+		table.AddRow(ui.TableRow{filepath.Base(binary.Path), utils.FormatBytes(binary.Size), change})
 	}
+	table.Render()
+	ui.PrintInfo(fmt.Sprintf("%d binaries would change. No changes made (--check).", changes))
+	return nil
+}
 
-	// Make executable
-	if err := os.Chmod(destPath, 0755); err != nil {
-		return err
+// sortedKeys returns a map's keys in order.
+func sortedKeys[V any](m map[string]V) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
 	}
-
-	ui.PrintSuccess(fmt.Sprintf("  ✓ %s updated", binaryName))
-	return nil
+	sort.Strings(keys)
+	return keys
 }
 
-// fetchReleaseData fetches data.json from easter.company
-func fetchReleaseData() (*release.ReleaseData, error) {
-	resp, err := http.Get(DataJSONURL)
+// fetchReleaseData fetches data.json from easter.company and verifies its signature
+// against the keys built into the CLI before any of its checksums are trusted. A missing or
+// bad signature is an error unless skipVerify is set.
+func fetchReleaseData(skipVerify bool) (*release.ReleaseData, error) {
+	body, err := fetchBytes(DataJSONURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to fetch data.json: %w", err)
 	}
-	defer func() { _ = resp.Body.Close() }()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
+	if skipVerify {
+		ui.PrintWarning("Skipping the data.json signature check (--insecure-skip-verify).")
+	} else {
+		signature, err := fetchBytes(DataJSONURL + release.SignatureSuffix)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch data.json signature: %w", err)
+		}
+		if err := release.VerifySignature(body, signature); err != nil {
+			return nil, fmt.Errorf("refusing to trust data.json: %w", err)
+		}
 	}
 
 	var data release.ReleaseData
-	if err := json.NewDecoder(resp.Body).Decode(&data); err != nil {
+	if err := json.Unmarshal(body, &data); err != nil {
 		return nil, fmt.Errorf("failed to parse data.json: %w", err)
 	}
 
 	return &data, nil
 }
 
-// downloadFile downloads a file from a URL
-func downloadFile(url, destination string) error {
+// fetchBytes downloads a small file into memory.
+func fetchBytes(url string) ([]byte, error) {
 	resp, err := http.Get(url)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	defer func() { _ = resp.Body.Close() }()
 
 	if resp.StatusCode != http.StatusOK {
-		return fmt.Errorf("server returned status %d", resp.StatusCode)
-	}
-
-	out, err := os.Create(destination)
-	if err != nil {
-		return err
-	}
-	defer func() { _ = out.Close() }()
-
-	_, err = io.Copy(out, resp.Body)
-	return err
-}
-
-// copyFile copies a file from src to dst
-func copyFile(src, dst string) error {
-	input, err := os.ReadFile(src)
-	if err != nil {
-		return err
+		return nil, fmt.Errorf("server returned status %d", resp.StatusCode)
 	}
-	return os.WriteFile(dst, input, 0755)
+	return io.ReadAll(resp.Body)
 }
 
 // extractShortVersion extracts "X.Y.Z" from full version string
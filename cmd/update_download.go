@@ -0,0 +1,208 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/release"
+	"github.com/EasterCompany/dex-cli/ui"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+const (
+	// updateDownloadConcurrency is how many binaries are downloaded at once
+	updateDownloadConcurrency = 3
+	// updateProgressInterval is how often the progress bars are redrawn
+	updateProgressInterval = 200 * time.Millisecond
+)
+
+// binaryDownload is one release binary being fetched into ~/Dexter/bin.
+type binaryDownload struct {
+	name    string
+	service config.ServiceDefinition
+	binary  release.Binary
+	// part is where the download is written until its checksum has been verified
+	part    string
+	written atomic.Int64
+	total   atomic.Int64
+	done    atomic.Bool
+	err     error
+}
+
+// newBinaryDownload prepares a download. The partial file sits next to the installed binary,
+// so the final rename is atomic, and is named after the checksum, so a partial file is only
+// ever resumed for the same build.
+func newBinaryDownload(name string, service config.ServiceDefinition, binary release.Binary) *binaryDownload {
+	d := &binaryDownload{name: name, service: service, binary: binary}
+	d.part = filepath.Join(binDir(), fmt.Sprintf(".%s.%s.part", filepath.Base(binary.Path), shortChecksum(binary.Checksum)))
+	d.total.Store(binary.Size)
+	return d
+}
+
+// binDir is where release binaries are installed.
+func binDir() string {
+	return filepath.Join(os.Getenv("HOME"), "Dexter", "bin")
+}
+
+// shortChecksum abbreviates a checksum for use in a file name.
+func shortChecksum(checksum string) string {
+	if len(checksum) > 12 {
+		return checksum[:12]
+	}
+	return checksum
+}
+
+// downloadBinaries fetches every download, updateDownloadConcurrency at a time, showing a
+// progress bar for each. Failures are recorded on the download rather than returned.
+func downloadBinaries(ctx context.Context, downloads []*binaryDownload) {
+	if err := os.MkdirAll(binDir(), 0755); err != nil {
+		for _, d := range downloads {
+			d.err = err
+		}
+		return
+	}
+
+	interactive := isTerminal(os.Stdout)
+	stopProgress := func() {}
+	if interactive {
+		stopProgress = renderDownloadProgress(downloads)
+	}
+
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, updateDownloadConcurrency)
+	for _, d := range downloads {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				d.err = ctx.Err()
+				d.done.Store(true)
+				return
+			}
+			defer func() { <-slots }()
+
+			removeStaleParts(d)
+			url := fmt.Sprintf("https://easter.company%s", d.binary.Path)
+			d.err = utils.DownloadFile(ctx, url, d.part, d.binary.Size, func(written, total int64) {
+				d.written.Store(written)
+				if total > 0 {
+					d.total.Store(total)
+				}
+			})
+			d.done.Store(true)
+			if !interactive && d.err == nil {
+				ui.PrintSuccess(fmt.Sprintf("  ✓ %s downloaded (%s)", filepath.Base(d.binary.Path), utils.FormatBytes(d.written.Load())))
+			}
+		}()
+	}
+	wg.Wait()
+	stopProgress()
+}
+
+// removeStaleParts deletes partial downloads of other builds of the same binary.
+func removeStaleParts(d *binaryDownload) {
+	matches, _ := filepath.Glob(filepath.Join(binDir(), fmt.Sprintf(".%s.*.part", filepath.Base(d.binary.Path))))
+	for _, match := range matches {
+		if match != d.part {
+			_ = os.Remove(match)
+		}
+	}
+}
+
+// renderDownloadProgress redraws one progress bar per download until the returned function
+// is called, which draws the final state and returns.
+func renderDownloadProgress(downloads []*binaryDownload) func() {
+	width := 0
+	for _, d := range downloads {
+		width = max(width, len(filepath.Base(d.binary.Path)))
+	}
+
+	drawn := false
+	draw := func() {
+		if drawn {
+			ui.PrintRaw(fmt.Sprintf("\033[%dA", len(downloads)))
+		}
+		drawn = true
+		for _, d := range downloads {
+			ui.ClearLine()
+			written, total := d.written.Load(), d.total.Load()
+			percent := 0
+			if total > 0 {
+				percent = int(written * 100 / total)
+			}
+			label := fmt.Sprintf("%-*s %9s / %-9s", width, filepath.Base(d.binary.Path), utils.FormatBytes(written), utils.FormatBytes(total))
+			if d.done.Load() && d.err != nil {
+				label += " failed"
+				percent = min(percent, 99)
+			} else if d.done.Load() {
+				percent = 100
+			} else {
+				percent = min(percent, 99)
+			}
+			ui.PrintProgressBar(label, percent)
+			if percent < 100 {
+				ui.PrintRaw("\n")
+			}
+		}
+	}
+
+	stop := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(updateProgressInterval)
+		defer ticker.Stop()
+		draw()
+		for {
+			select {
+			case <-stop:
+				draw()
+				return
+			case <-ticker.C:
+				draw()
+			}
+		}
+	}()
+	return func() {
+		close(stop)
+		<-finished
+	}
+}
+
+// installDownloadedBinary verifies a finished download and renames it over the installed
+// binary. The binary being replaced is kept in the rollback store; its version is returned.
+func installDownloadedBinary(d *binaryDownload) (string, error) {
+	actualChecksum, err := release.CalculateChecksum(d.part)
+	if err != nil {
+		return "", fmt.Errorf("failed to calculate checksum: %w", err)
+	}
+	if actualChecksum != d.binary.Checksum {
+		// A corrupt partial file would otherwise be resumed forever
+		_ = os.Remove(d.part)
+		return "", fmt.Errorf("checksum mismatch (expected %s, got %s)", d.binary.Checksum, actualChecksum)
+	}
+
+	previous, err := utils.SnapshotBinary(d.service)
+	if err != nil {
+		ui.PrintWarning(fmt.Sprintf("Could not keep the current %s for rollback: %v", d.service.ShortName, err))
+	}
+
+	if err := os.Chmod(d.part, 0755); err != nil {
+		return "", err
+	}
+	destPath := filepath.Join(binDir(), filepath.Base(d.binary.Path))
+	if err := os.Rename(d.part, destPath); err != nil {
+		return "", fmt.Errorf("failed to install %s: %w", filepath.Base(destPath), err)
+	}
+
+	ui.PrintSuccess(fmt.Sprintf("  ✓ %s updated", filepath.Base(destPath)))
+	return previous, nil
+}
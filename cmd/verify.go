@@ -2,187 +2,199 @@ package cmd
 
 import (
 	"context"
-	"encoding/json"
 	"fmt"
+	"io"
+	"os"
+	"os/signal"
 	"strings"
 	"time"
 
-	"github.com/EasterCompany/dex-cli/cache"
-	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/health"
 	"github.com/EasterCompany/dex-cli/ui"
 	"github.com/EasterCompany/dex-cli/utils"
 )
 
-// Verify runs a deep diagnostic check of the system
-func Verify() error {
-	start := time.Now()
-	ui.PrintHeader("SYSTEM DIAGNOSTIC VERIFICATION")
-	fmt.Println()
+// verifyCategoryTitles are the section headings of the text report
+var verifyCategoryTitles = map[string]string{
+	health.CategoryInfrastructure: "Infrastructure Dependencies",
+	health.CategoryServices:       "Service Mesh Topology",
+	health.CategoryDeep:           "Deep System Verification",
+}
 
-	issues := 0
+// SilentError fails a command without the error being printed again, for commands whose
+// output must stay machine-readable
+type SilentError struct {
+	Err error
+}
 
-	// --- 1. INFRASTRUCTURE ---
-	ui.PrintInfo("1. Infrastructure Dependencies")
+func (e *SilentError) Error() string {
+	return e.Err.Error()
+}
 
-	// Redis Check
-	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-	defer cancel()
+func (e *SilentError) Unwrap() error {
+	return e.Err
+}
 
-	redisClient, err := cache.GetLocalClient(ctx)
-	if err != nil {
-		ui.PrintError(fmt.Sprintf("  %-20s %s (%v)", "Redis", ui.Colorize("FAILED", ui.ColorBrightRed), err))
-		issues++
-	} else {
-		ping := redisClient.Ping(ctx).Val()
-		if ping == "PONG" {
-			ui.PrintSuccess(fmt.Sprintf("  %-20s %s", "Redis", ui.Colorize("OK", ui.ColorGreen)))
-		} else {
-			ui.PrintError(fmt.Sprintf("  %-20s %s (Response: %s)", "Redis", ui.Colorize("FAILED", ui.ColorBrightRed), ping))
-			issues++
+// Verify runs a deep diagnostic check of the system
+func Verify(args []string) error {
+	format := "text"
+	outputPath := ""
+	var only []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		switch {
+		case arg == "--help" || arg == "-h":
+			printVerifyHelp()
+			return nil
+		case arg == "--format" || arg == "--only" || arg == "--output" || arg == "-o":
+			if i+1 >= len(args) {
+				return fmt.Errorf("%s requires a value", arg)
+			}
+			i++
+			switch arg {
+			case "--format":
+				format = args[i]
+			case "--only":
+				only = append(only, splitCheckNames(args[i])...)
+			default:
+				outputPath = args[i]
+			}
+		case strings.HasPrefix(arg, "--format="):
+			format = strings.TrimPrefix(arg, "--format=")
+		case strings.HasPrefix(arg, "--only="):
+			only = append(only, splitCheckNames(strings.TrimPrefix(arg, "--only="))...)
+		case strings.HasPrefix(arg, "--output="):
+			outputPath = strings.TrimPrefix(arg, "--output=")
+		default:
+			return fmt.Errorf("unknown argument '%s'", arg)
 		}
-		_ = redisClient.Close()
 	}
-
-	// Ollama Check
-	// Attempt to connect to local Ollama
-	ollamaDef, _ := config.Resolve("ollama") // Using "ollama" alias
-	ollamaUrl := fmt.Sprintf("http://%s:%s/api/version", ollamaDef.Domain, ollamaDef.Port)
-	ollamaResp, _, err := utils.GetHTTPBody(ollamaUrl)
-	if err != nil {
-		ui.PrintError(fmt.Sprintf("  %-20s %s (%v)", "Ollama", ui.Colorize("FAILED", ui.ColorBrightRed), err))
-		issues++
-	} else {
-		var oVer struct {
-			Version string `json:"version"`
-		}
-		if json.Unmarshal(ollamaResp, &oVer) == nil {
-			ui.PrintSuccess(fmt.Sprintf("  %-20s %s (v%s)", "Ollama", ui.Colorize("OK", ui.ColorGreen), oVer.Version))
-		} else {
-			ui.PrintSuccess(fmt.Sprintf("  %-20s %s (Connected)", "Ollama", ui.Colorize("OK", ui.ColorGreen)))
-		}
+	if format != "text" && format != "json" && format != "junit" {
+		return fmt.Errorf("invalid format '%s': must be 'text', 'json' or 'junit'", format)
 	}
 
-	fmt.Println()
-
-	// --- 2. SERVICE MESH ---
-	ui.PrintInfo("2. Service Mesh Topology")
 	services, err := utils.GetConfiguredServices()
 	if err != nil {
 		return fmt.Errorf("failed to load services: %w", err)
 	}
+	registry := &health.Registry{}
+	registerVerifyChecks(registry, services)
+	checks, err := registry.Select(only)
+	if err != nil {
+		return err
+	}
 
-	eventServiceOnline := false
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
 
-	for _, s := range services {
-		// Skip CLI, Prod, OS services (handled above or ignored)
-		if s.Type == "cli" || s.Type == "prd" || s.Type == "os" {
-			continue
-		}
-
-		// Check service health
-		report, err := utils.GetHTTPServiceReport(s)
-		if err != nil {
-			ui.PrintError(fmt.Sprintf("  %-20s %s", s.ShortName, ui.Colorize("OFFLINE", ui.ColorBrightRed)))
-			issues++
-			continue
+	start := time.Now()
+	var results []health.Result
+	if format == "text" {
+		ui.PrintHeader("SYSTEM DIAGNOSTIC VERIFICATION")
+		section := 0
+		category := ""
+		for _, check := range checks {
+			if check.Category() != category {
+				if category != "" {
+					fmt.Println()
+				}
+				category = check.Category()
+				section++
+				title, ok := verifyCategoryTitles[category]
+				if !ok {
+					title = ui.TitleCase(category) + " Checks"
+				}
+				ui.PrintInfo(fmt.Sprintf("%d. %s", section, title))
+			}
+			result := health.RunCheck(ctx, check)
+			printVerifyResult(result)
+			results = append(results, result)
 		}
-
-		// Parse basic report to check status
-		var statusStruct struct {
-			Health struct {
-				Status string `json:"status"`
-			} `json:"health"`
-			Version struct {
-				Str string `json:"str"`
-			} `json:"version"`
+	} else {
+		for _, check := range checks {
+			results = append(results, health.RunCheck(ctx, check))
 		}
-		_ = json.Unmarshal([]byte(report), &statusStruct)
-
-		status := strings.ToUpper(statusStruct.Health.Status)
-		if status == "OK" || status == "HEALTHY" {
-			ui.PrintSuccess(fmt.Sprintf("  %-20s %s (%s)", s.ShortName, ui.Colorize("OK", ui.ColorGreen), statusStruct.Version.Str))
-			if s.ShortName == "event" {
-				eventServiceOnline = true
+	}
+	report := health.NewReport(results, time.Since(start))
+
+	if format != "text" {
+		var out io.Writer = os.Stdout
+		if outputPath != "" {
+			file, err := os.Create(outputPath)
+			if err != nil {
+				return fmt.Errorf("failed to create %s: %w", outputPath, err)
 			}
+			defer func() { _ = file.Close() }()
+			out = file
+		}
+		if format == "json" {
+			err = report.WriteJSON(out)
 		} else {
-			ui.PrintError(fmt.Sprintf("  %-20s %s (%s)", s.ShortName, ui.Colorize(status, ui.ColorYellow), statusStruct.Version.Str))
-			issues++
+			err = report.WriteJUnit(out)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to write %s report: %w", format, err)
 		}
+		if !report.Passed {
+			// The report already names the failed checks; keep stdout machine-readable
+			return &SilentError{Err: fmt.Errorf("verification failed with %d issues", report.Counts.Failed)}
+		}
+		return nil
 	}
 
 	fmt.Println()
-
-	// --- 3. DEEP VERIFY (Synthetic Transaction) ---
-	ui.PrintInfo("3. Deep System Verification")
-
-	if !eventServiceOnline {
-		ui.PrintError("  Cannot perform deep verify: Event Service is offline.")
-		issues++
-	} else {
-		// Send a diagnostic ping
-		pingId := fmt.Sprintf("verify-%d", time.Now().Unix())
-
-		fmt.Print("  Sending synthetic event to Event Bus... ")
-		utils.SuppressEvents = false // Ensure we can send
-
-		// We use a custom SendEvent logic here to capture the error directly rather than logging it
-		// But utils.SendEvent handles it. Let's just use it and assume success if no panic,
-		// but ideally we want to know if it worked.
-		// utils.SendEvent doesn't return error.
-		// Let's use PostHTTP directly to control it.
-
-		eventDef := config.GetServiceDefinition("dex-event-service")
-		url := eventDef.GetHTTP("/events")
-
-		body := map[string]interface{}{
-			"service": "dex-cli",
-			"event": map[string]interface{}{
-				"type":      "system.diagnostic.ping",
-				"timestamp": time.Now().Format(time.RFC3339Nano),
-				"ping_id":   pingId,
-				"source":    "dex-verify",
-			},
+	ui.PrintHeader("VERIFICATION SUMMARY")
+	if report.Passed {
+		ui.PrintSuccess(fmt.Sprintf("System is FULLY OPERATIONAL. (Duration: %v)", report.Duration.Round(time.Millisecond)))
+		if report.Counts.Warnings > 0 {
+			ui.PrintWarning(fmt.Sprintf("%d non-critical checks failed.", report.Counts.Warnings))
 		}
-		jsonBody, _ := json.Marshal(body)
-
-		resp, code, err := utils.PostHTTP(url, jsonBody)
-
-		if err != nil {
-			fmt.Printf("%s\n", ui.Colorize("FAILED", ui.ColorBrightRed))
-			ui.PrintError(fmt.Sprintf("    Network Error: %v", err))
-			issues++
-		} else if code != 201 {
-			fmt.Printf("%s\n", ui.Colorize("FAILED", ui.ColorBrightRed))
-			ui.PrintError(fmt.Sprintf("    HTTP Error: %d - %s", code, string(resp)))
-			issues++
-		} else {
-			fmt.Printf("%s\n", ui.Colorize("OK", ui.ColorGreen))
+		return nil
+	}
+	ui.PrintError(fmt.Sprintf("System has %d ISSUES. (Duration: %v)", report.Counts.Failed, report.Duration.Round(time.Millisecond)))
+	return fmt.Errorf("verification failed with %d issues", report.Counts.Failed)
+}
 
-			// Optional: Verify persistence in Redis if we have a client
-			// This proves the event service successfully processed it and stored it.
-			if redisClient != nil {
-				// Wait a moment for async processing
-				time.Sleep(100 * time.Millisecond)
+// printVerifyResult prints one check of the text report with its timing.
+func printVerifyResult(result health.Result) {
+	duration := result.Duration.Round(time.Millisecond)
+	switch {
+	case result.Status == health.StatusPassed:
+		ui.PrintSuccess(fmt.Sprintf("  %-20s %s (%v) %s", result.Name, ui.Colorize("OK", ui.ColorGreen), duration, result.Message))
+	case result.Status == health.StatusSkipped:
+		ui.PrintInfo(fmt.Sprintf("  %-20s %s (%s)", result.Name, ui.Colorize("SKIPPED", ui.ColorDarkGray), result.Message))
+	case result.Failing():
+		ui.PrintError(fmt.Sprintf("  %-20s %s (%v) %s", result.Name, ui.Colorize("FAILED", ui.ColorBrightRed), duration, result.Message))
+	default:
+		ui.PrintWarning(fmt.Sprintf("  %-20s %s (%v) %s", result.Name, ui.Colorize("WARNING", ui.ColorYellow), duration, result.Message))
+	}
+	if result.Status == health.StatusFailed && result.Remediation != "" {
+		ui.PrintInfo(ui.Colorize("    → "+result.Remediation, ui.ColorDarkGray))
+	}
+}
 
-				// Check recent events stream or list?
-				// Event service stores events in `events` stream or list.
-				// For now, let's assume HTTP 201 from Event Service means it worked.
-				// True "End-to-End" verification would be reading it back.
-				ui.PrintSuccess("  Event Bus accepted transaction.")
-			}
+// splitCheckNames splits a comma-separated --only value.
+func splitCheckNames(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
 		}
 	}
+	return names
+}
 
+func printVerifyHelp() {
+	ui.PrintHeader("Verify Command Help")
+	ui.PrintInfo("Usage: dex verify [--only <checks>] [--format text|json|junit] [-o <file>]")
 	fmt.Println()
-	duration := time.Since(start)
-
-	ui.PrintHeader("VERIFICATION SUMMARY")
-	if issues == 0 {
-		ui.PrintSuccess(fmt.Sprintf("System is FULLY OPERATIONAL. (Duration: %v)", duration.Round(time.Millisecond)))
-	} else {
-		ui.PrintError(fmt.Sprintf("System has %d ISSUES. (Duration: %v)", issues, duration.Round(time.Millisecond)))
-		return fmt.Errorf("verification failed with %d issues", issues)
-	}
-
-	return nil
+	ui.PrintInfo("Flags:")
+	ui.PrintInfo("  --only <checks>       Comma-separated check names or categories to run,")
+	ui.PrintInfo("                        e.g. 'redis,ollama' or 'deep'.")
+	ui.PrintInfo("  --format <format>     text (default), json, or junit for CI.")
+	ui.PrintInfo("  -o, --output <file>   Write the json/junit report to a file instead of stdout.")
+	fmt.Println()
+	ui.PrintInfo("Categories: infrastructure, services, deep.")
+	ui.PrintInfo("Services can declare extra HTTP checks under 'checks' in service-map.json;")
+	ui.PrintInfo("they run as '<service>.<name>'. Only failed critical checks fail the command.")
 }
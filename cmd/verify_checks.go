@@ -0,0 +1,201 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/cache"
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/health"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+// registerVerifyChecks registers the built-in diagnostics, a health check for every configured
+// service, and the checks services declare in service-map.json.
+func registerVerifyChecks(registry *health.Registry, services []config.ServiceDefinition) {
+	// --- Infrastructure ---
+	registry.Register(
+		health.NewCheck("redis", health.CategoryInfrastructure, health.SeverityCritical,
+			"Start the local Redis server and check the local-cache-0 credentials in service-map.json.",
+			checkRedis),
+		health.NewCheck("ollama", health.CategoryInfrastructure, health.SeverityCritical,
+			"Start Ollama ('ollama serve') and make sure it listens on the local-ollama-0 address.",
+			checkOllama),
+	)
+
+	// --- Service mesh ---
+	installed := make(map[string]config.ServiceDefinition)
+	for _, s := range services {
+		// CLI, Prod and OS services are covered by the infrastructure checks or ignored
+		if s.Type == "cli" || s.Type == "prd" || s.Type == "os" {
+			continue
+		}
+		installed[s.ShortName] = s
+		registry.Register(health.NewCheck(s.ShortName, health.CategoryServices, health.SeverityCritical,
+			fmt.Sprintf("Run 'dex restart %s' and inspect 'dex logs %s'.", s.ShortName, s.ShortName),
+			serviceHealthCheck(s)))
+	}
+
+	// --- Deep verification (synthetic transactions) ---
+	// Each follows a request through a service and back; it is skipped when the service is
+	// not part of this install
+	withService := func(name string, probe func(ctx context.Context, def config.ServiceDefinition) (string, error)) func(ctx context.Context) (string, error) {
+		return func(ctx context.Context) (string, error) {
+			def, ok := installed[name]
+			if !ok {
+				return "", health.Skip("%s is not installed", name)
+			}
+			return probe(ctx, def)
+		}
+	}
+	speech := &speechRoundTrip{}
+	if tts, ok := installed["tts"]; ok {
+		speech.tts = &tts
+	}
+	registry.Register(
+		health.NewCheck("event-roundtrip", health.CategoryDeep, health.SeverityCritical,
+			"Check that the event service can reach Redis and inspect 'dex logs event'.",
+			withService("event", probeEventRoundTrip)),
+		// Fetching a public page needs internet access, which offline hosts do not have
+		health.NewCheck("web-metadata", health.CategoryDeep, health.SeverityWarning,
+			"Check that the web service can reach the internet and inspect 'dex logs web'.",
+			withService("web", probeWebMetadata)),
+		health.NewCheck("tts-synthesis", health.CategoryDeep, health.SeverityCritical,
+			"Check the tts model and device in options.json and inspect 'dex logs tts'.",
+			speech.probeTTS),
+		health.NewCheck("stt-transcription", health.CategoryDeep, health.SeverityCritical,
+			"Check the stt model and device in options.json and inspect 'dex logs stt'.",
+			withService("stt", speech.probeSTT)),
+		health.NewCheck("ollama-generate", health.CategoryDeep, health.SeverityCritical,
+			"Run 'dex ollama pull' to create the custom models, then retry.",
+			probeOllamaGenerate),
+	)
+
+	// --- Checks declared in service-map.json ---
+	for _, s := range services {
+		if _, ok := installed[s.ShortName]; !ok {
+			continue
+		}
+		for _, check := range s.Checks {
+			severity := health.SeverityCritical
+			if check.Severity == string(health.SeverityWarning) {
+				severity = health.SeverityWarning
+			}
+			remediation := check.Remediation
+			if remediation == "" {
+				remediation = fmt.Sprintf("Inspect 'dex logs %s'.", s.ShortName)
+			}
+			registry.Register(health.NewCheck(s.ShortName+"."+check.Name, health.CategoryServices, severity, remediation,
+				serviceMapCheck(s, check)))
+		}
+	}
+}
+
+// checkRedis pings the local Redis.
+func checkRedis(ctx context.Context) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = client.Close() }()
+
+	if ping := client.Ping(ctx).Val(); ping != "PONG" {
+		return "", fmt.Errorf("unexpected response: %s", ping)
+	}
+	return "PONG", nil
+}
+
+// checkOllama asks the local Ollama for its version.
+func checkOllama(ctx context.Context) (string, error) {
+	body, code, err := probeRequest(ctx, http.MethodGet, ollamaURL()+"/api/version", "", nil, 5*time.Second)
+	if err != nil {
+		return "", err
+	}
+	if code != http.StatusOK {
+		return "", fmt.Errorf("HTTP %d", code)
+	}
+	var version struct {
+		Version string `json:"version"`
+	}
+	if json.Unmarshal(body, &version) == nil && version.Version != "" {
+		return "v" + version.Version, nil
+	}
+	return "Connected", nil
+}
+
+// ollamaURL returns the Ollama address from the service map, falling back to the default.
+func ollamaURL() string {
+	ollamaDef, err := config.Resolve("ollama") // Using "ollama" alias
+	if err != nil {
+		return utils.DefaultOllamaURL
+	}
+	return ollamaDef.GetHTTP("")
+}
+
+// serviceHealthCheck reads a service's report and requires a healthy status.
+func serviceHealthCheck(s config.ServiceDefinition) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		report, err := utils.GetHTTPServiceReport(s)
+		if err != nil {
+			return "", fmt.Errorf("OFFLINE: %w", err)
+		}
+
+		// Parse basic report to check status
+		var statusStruct struct {
+			Health struct {
+				Status string `json:"status"`
+			} `json:"health"`
+			Version struct {
+				Str string `json:"str"`
+			} `json:"version"`
+		}
+		_ = json.Unmarshal([]byte(report), &statusStruct)
+
+		status := strings.ToUpper(statusStruct.Health.Status)
+		if status != "OK" && status != "HEALTHY" {
+			return "", fmt.Errorf("status %s (%s)", orNA(status), statusStruct.Version.Str)
+		}
+		return statusStruct.Version.Str, nil
+	}
+}
+
+// serviceMapCheck runs an HTTP check declared for a service in service-map.json.
+func serviceMapCheck(s config.ServiceDefinition, check config.ServiceCheck) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		method := strings.ToUpper(check.Method)
+		if method == "" {
+			method = http.MethodGet
+		}
+		timeout := 5 * time.Second
+		if check.TimeoutSeconds > 0 {
+			timeout = time.Duration(check.TimeoutSeconds) * time.Second
+		}
+		expect := check.ExpectStatus
+		if expect == 0 {
+			expect = http.StatusOK
+		}
+
+		contentType := ""
+		if check.Body != "" {
+			contentType = "application/json"
+		}
+		body, code, err := probeRequest(ctx, method, s.GetHTTP(check.Path), contentType, []byte(check.Body), timeout)
+		if err != nil {
+			return "", err
+		}
+		if code != expect {
+			return "", fmt.Errorf("%s %s returned HTTP %d, expected %d", method, check.Path, code, expect)
+		}
+		if check.Contains != "" && !strings.Contains(string(body), check.Contains) {
+			return "", fmt.Errorf("%s %s response does not contain %q", method, check.Path, check.Contains)
+		}
+		return fmt.Sprintf("%s %s -> %d", method, check.Path, code), nil
+	}
+}
@@ -0,0 +1,224 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/cache"
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/health"
+	"github.com/EasterCompany/dex-cli/utils"
+)
+
+const (
+	// verifyEventTimeout is how long the event bus has to make a ping readable
+	verifyEventTimeout = 5 * time.Second
+	// verifyPollInterval is how often the event bus is polled for the ping
+	verifyPollInterval = 100 * time.Millisecond
+	// verifyTimelineDepth is how many of the newest timeline entries are searched in Redis
+	verifyTimelineDepth = 50
+	// verifyMetadataURL is the page the web service is asked to describe
+	verifyMetadataURL = "https://example.com"
+	// verifySpeechText is what tts is asked to say, and what stt should hear back
+	verifySpeechText = "Dexter verification test."
+	// verifyOllamaModel is the smallest of the custom models, so the smoke test stays quick
+	verifyOllamaModel = "dex-fast-summary-model"
+)
+
+// probeEventRoundTrip posts a diagnostic ping and waits until it can be read back from the
+// event service, or failing that straight from the Redis timeline. The duration is the time
+// from posting to the ping being visible.
+func probeEventRoundTrip(ctx context.Context, event config.ServiceDefinition) (string, error) {
+	pingID := fmt.Sprintf("verify-%d", time.Now().UnixNano())
+	body, _ := json.Marshal(map[string]interface{}{
+		"service": "dex-cli",
+		"event": map[string]interface{}{
+			"type":      "system.diagnostic.ping",
+			"timestamp": time.Now().Format(time.RFC3339Nano),
+			"ping_id":   pingID,
+			"source":    "dex-verify",
+		},
+	})
+
+	utils.SuppressEvents = false // Ensure we can send
+	resp, code, err := probeRequest(ctx, http.MethodPost, event.GetHTTP("/events"), "application/json", body, 10*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to post ping: %w", err)
+	}
+	if code != http.StatusCreated {
+		return "", fmt.Errorf("event service rejected ping: HTTP %d - %s", code, strings.TrimSpace(string(resp)))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, verifyEventTimeout)
+	defer cancel()
+	queryURL := fmt.Sprintf("%s?ml=20&format=json&event.type=system.diagnostic.ping", event.GetHTTP("/events"))
+	for {
+		if body, code, err := probeRequest(ctx, http.MethodGet, queryURL, "", nil, verifyEventTimeout); err == nil && code == http.StatusOK && bytes.Contains(body, []byte(pingID)) {
+			return fmt.Sprintf("ping %s read back from /events", pingID), nil
+		}
+		if timelineContains(ctx, pingID) {
+			return fmt.Sprintf("ping %s read back from the Redis timeline", pingID), nil
+		}
+		select {
+		case <-ctx.Done():
+			return "", fmt.Errorf("ping %s was accepted but never appeared on the bus within %v", pingID, verifyEventTimeout)
+		case <-time.After(verifyPollInterval):
+		}
+	}
+}
+
+// timelineContains searches the newest entries of the Redis event timeline for a string.
+// Entries are either the event itself or the ID of an event stored under event:<id>.
+func timelineContains(ctx context.Context, needle string) bool {
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = client.Close() }()
+
+	entries, err := client.ZRevRange(ctx, "events:timeline", 0, verifyTimelineDepth-1).Result()
+	if err != nil {
+		return false
+	}
+	for _, entry := range entries {
+		if strings.Contains(entry, needle) {
+			return true
+		}
+		if stored, err := client.Get(ctx, "event:"+entry).Result(); err == nil && strings.Contains(stored, needle) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeWebMetadata asks the web service to fetch and describe a page.
+func probeWebMetadata(ctx context.Context, web config.ServiceDefinition) (string, error) {
+	body, code, err := probeRequest(ctx, http.MethodGet, fmt.Sprintf("%s?url=%s", web.GetHTTP("/metadata"), url.QueryEscape(verifyMetadataURL)), "", nil, 30*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach /metadata: %w", err)
+	}
+	if code != http.StatusOK {
+		return "", fmt.Errorf("/metadata returned HTTP %d", code)
+	}
+	var meta MetadataResponse
+	if err := json.Unmarshal(body, &meta); err != nil {
+		return "", fmt.Errorf("failed to parse metadata: %w", err)
+	}
+	if meta.Error != "" {
+		return "", fmt.Errorf("web service error: %s", meta.Error)
+	}
+	if meta.Title == "" && meta.Content == "" {
+		return "", fmt.Errorf("metadata for %s is empty", verifyMetadataURL)
+	}
+	return fmt.Sprintf("%s -> %q", verifyMetadataURL, meta.Title), nil
+}
+
+// speechRoundTrip carries the audio tts produced over to the stt check
+type speechRoundTrip struct {
+	tts   *config.ServiceDefinition
+	audio []byte
+}
+
+// probeTTS asks tts to speak a sentence and keeps the audio for the stt check.
+func (s *speechRoundTrip) probeTTS(ctx context.Context) (string, error) {
+	if s.tts == nil {
+		return "", health.Skip("tts is not installed")
+	}
+	body, _ := json.Marshal(map[string]string{"text": verifySpeechText})
+	data, code, err := probeRequest(ctx, http.MethodPost, s.tts.GetHTTP("/generate"), "application/json", body, 60*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach /generate: %w", err)
+	}
+	if code != http.StatusOK {
+		return "", fmt.Errorf("/generate returned HTTP %d", code)
+	}
+	if len(data) == 0 {
+		return "", fmt.Errorf("/generate returned no audio")
+	}
+	s.audio = data
+	return fmt.Sprintf("%d bytes of audio", len(data)), nil
+}
+
+// probeSTT transcribes the audio the tts check produced, closing the speech round trip.
+// When the tts check did not run, it asks tts for the audio itself.
+func (s *speechRoundTrip) probeSTT(ctx context.Context, stt config.ServiceDefinition) (string, error) {
+	if s.audio == nil {
+		if _, err := s.probeTTS(ctx); err != nil {
+			return "", fmt.Errorf("no audio to transcribe: tts: %w", err)
+		}
+	}
+	data, code, err := probeRequest(ctx, http.MethodPost, stt.GetHTTP("/transcribe"), "audio/wav", s.audio, 60*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach /transcribe: %w", err)
+	}
+	if code != http.StatusOK {
+		return "", fmt.Errorf("/transcribe returned HTTP %d", code)
+	}
+	var transcript struct {
+		Text string `json:"text"`
+	}
+	if err := json.Unmarshal(data, &transcript); err != nil {
+		transcript.Text = string(data)
+	}
+	text := strings.TrimSpace(transcript.Text)
+	if text == "" {
+		return "", fmt.Errorf("transcript is empty")
+	}
+	if !strings.Contains(strings.ToLower(text), "verification") {
+		return "", fmt.Errorf("transcript %q does not match %q", text, verifySpeechText)
+	}
+	return fmt.Sprintf("heard %q", text), nil
+}
+
+// probeOllamaGenerate runs a one-word generation on the smallest custom model.
+func probeOllamaGenerate(ctx context.Context) (string, error) {
+	body, _ := json.Marshal(utils.GenerateRequest{Model: verifyOllamaModel, Prompt: "Reply with the single word: pong"})
+	data, code, err := probeRequest(ctx, http.MethodPost, ollamaURL()+"/api/generate", "application/json", body, 120*time.Second)
+	if err != nil {
+		return "", fmt.Errorf("failed to reach Ollama: %w", err)
+	}
+	if code != http.StatusOK {
+		return "", fmt.Errorf("%s: HTTP %d - %s", verifyOllamaModel, code, strings.TrimSpace(string(data)))
+	}
+	var generated utils.GenerateResponse
+	if err := json.Unmarshal(data, &generated); err != nil {
+		return "", fmt.Errorf("failed to parse generate response: %w", err)
+	}
+	response := strings.TrimSpace(generated.Response)
+	if response == "" {
+		return "", fmt.Errorf("%s returned an empty response", verifyOllamaModel)
+	}
+	if len(response) > 40 {
+		response = response[:40] + "..."
+	}
+	return fmt.Sprintf("%s replied %q", verifyOllamaModel, response), nil
+}
+
+// probeRequest makes an HTTP request bounded by both the context and a timeout; the utils
+// helpers use fixed timeouts that are too short for model-backed services.
+func probeRequest(ctx context.Context, method, endpoint, contentType string, body []byte, timeout time.Duration) ([]byte, int, error) {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, method, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, 0, err
+	}
+	if contentType != "" {
+		req.Header.Set("Content-Type", contentType)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	data, err := io.ReadAll(resp.Body)
+	return data, resp.StatusCode, err
+}
@@ -67,6 +67,11 @@ func GetCommandRequirements() map[string]CommandRequirement {
 			Description: "Proxy for the system ollama executable",
 			Check:       HasOllama,
 		},
+		"service": {
+			Name:        "service",
+			Description: "Inspect and regenerate service systemd units",
+			Check:       HasAnySystemdService,
+		},
 		"logs": {
 			Name:        "logs",
 			Description: "View service logs",
@@ -137,6 +142,11 @@ func GetCommandRequirements() map[string]CommandRequirement {
 			Description: "Run deep system diagnostics",
 			Check:       func() bool { return true },
 		},
+		"doctor": {
+			Name:        "doctor",
+			Description: "Find and fix configuration, system and service problems",
+			Check:       func() bool { return true }, // Always available, it repairs the rest
+		},
 	}
 }
 
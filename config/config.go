@@ -5,8 +5,6 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
-
-	"github.com/EasterCompany/dex-cli/ui"
 )
 
 const (
@@ -179,7 +177,7 @@ func EnsureLinterConfigs() error {
 
 // EnsureServiceLogFiles creates empty log files for all services if they don't exist
 func EnsureServiceLogFiles() error {
-	for _, def := range serviceDefinitions {
+	for _, def := range GetAllServices() {
 		logPath, err := ExpandPath(def.GetLogPath())
 		if err != nil {
 			continue
@@ -295,24 +293,22 @@ func healServiceMapConfig(userMap *ServiceMapConfig, defaultMap *ServiceMapConfi
 // healOptionsConfig merges the default config into the user's config to add missing fields.
 // It modifies the userOpts object directly. Returns true if changes were made.
 func healOptionsConfig(userOpts *OptionsConfig, defaultOpts *OptionsConfig) bool {
-	// Check top-level fields
-	if userOpts.Editor == "" {
-		userOpts.Editor = defaultOpts.Editor
-	}
-	if userOpts.Theme == "" {
-		userOpts.Theme = defaultOpts.Theme
+	healed := false
+	fill := func(field *string, value string) {
+		if *field == "" && value != "" {
+			*field = value
+			healed = true
+		}
 	}
 
+	// Check top-level fields
+	fill(&userOpts.Editor, defaultOpts.Editor)
+	fill(&userOpts.Theme, defaultOpts.Theme)
+
 	// Check Discord options
-	if userOpts.Discord.Token == "" {
-		userOpts.Discord.Token = defaultOpts.Discord.Token
-	}
-	if userOpts.Discord.ServerID == "" {
-		userOpts.Discord.ServerID = defaultOpts.Discord.ServerID
-	}
-	if userOpts.Discord.DebugChannelID == "" {
-		userOpts.Discord.DebugChannelID = defaultOpts.Discord.DebugChannelID
-	}
+	fill(&userOpts.Discord.Token, defaultOpts.Discord.Token)
+	fill(&userOpts.Discord.ServerID, defaultOpts.Discord.ServerID)
+	fill(&userOpts.Discord.DebugChannelID, defaultOpts.Discord.DebugChannelID)
 
 	// Check Services
 	if userOpts.Services == nil {
@@ -321,32 +317,19 @@ func healOptionsConfig(userOpts *OptionsConfig, defaultOpts *OptionsConfig) bool
 	for svcName, defConfig := range defaultOpts.Services {
 		if _, exists := userOpts.Services[svcName]; !exists {
 			userOpts.Services[svcName] = defConfig
+			healed = true
 		} else {
 			// Merge nested map (ensure keys exist if missing)
 			for k, v := range defConfig {
 				if _, ok := userOpts.Services[svcName][k]; !ok {
 					userOpts.Services[svcName][k] = v
+					healed = true
 				}
 			}
 		}
 	}
 
-	// Server Map
-	_, serverMapErr := LoadServerMapConfig()
-	if serverMapErr != nil {
-		if os.IsNotExist(serverMapErr) {
-			fmt.Println("Creating default server-map.json...")
-			if err := SaveServerMapConfig(DefaultServerMapConfig()); err != nil {
-				ui.PrintInfo("failed to save default server-map.json")
-				return false
-			}
-		} else {
-			ui.PrintInfo("failed to load server-map.json")
-			return false
-		}
-	}
-
-	return true
+	return healed
 }
 
 // LogFile returns a file handle to the dex-cli log file.
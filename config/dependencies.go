@@ -0,0 +1,115 @@
+// config/dependencies.go
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// FindService looks up a service in a list by its short name or ID.
+func FindService(services []ServiceDefinition, name string) (ServiceDefinition, bool) {
+	for _, def := range services {
+		if def.ShortName == name || def.ID == name {
+			return def, true
+		}
+	}
+	return ServiceDefinition{}, false
+}
+
+// FindDependencyCycle walks the dependency graph and returns the first cycle found
+// as a list of short names (e.g., ["a", "b", "a"]), or nil if the graph is acyclic.
+// Dependencies that are not in the list are ignored.
+func FindDependencyCycle(services []ServiceDefinition) []string {
+	const (
+		unvisited = iota
+		visiting
+		visited
+	)
+	state := make(map[string]int)
+	var stack []string
+	var cycle []string
+
+	var visit func(def ServiceDefinition) bool
+	visit = func(def ServiceDefinition) bool {
+		state[def.ID] = visiting
+		stack = append(stack, def.ShortName)
+		for _, depName := range def.DependsOn {
+			dep, ok := FindService(services, depName)
+			if !ok {
+				continue
+			}
+			switch state[dep.ID] {
+			case visiting:
+				// Slice the stack from the first occurrence of dep to close the loop
+				for i, name := range stack {
+					if name == dep.ShortName {
+						cycle = append(append([]string{}, stack[i:]...), dep.ShortName)
+						break
+					}
+				}
+				return true
+			case unvisited:
+				if visit(dep) {
+					return true
+				}
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[def.ID] = visited
+		return false
+	}
+
+	for _, def := range services {
+		if state[def.ID] == unvisited && visit(def) {
+			return cycle
+		}
+	}
+	return nil
+}
+
+// OrderByDependencies groups services into start levels. Every service in a level
+// only depends on services in earlier levels, so a level can be started in parallel
+// once the previous one is ready. Dependencies outside the list are not ordered.
+// Reverse the levels to get a safe stop order.
+func OrderByDependencies(services []ServiceDefinition) ([][]ServiceDefinition, error) {
+	if cycle := FindDependencyCycle(services); cycle != nil {
+		return nil, fmt.Errorf("dependency cycle detected: %s", strings.Join(cycle, " -> "))
+	}
+
+	remaining := make(map[string]ServiceDefinition)
+	for _, def := range services {
+		remaining[def.ID] = def
+	}
+
+	var levels [][]ServiceDefinition
+	placed := make(map[string]bool)
+	for len(remaining) > 0 {
+		var level []ServiceDefinition
+		for _, def := range remaining {
+			ready := true
+			for _, depName := range def.DependsOn {
+				dep, ok := FindService(services, depName)
+				if ok && !placed[dep.ID] {
+					ready = false
+					break
+				}
+			}
+			if ready {
+				level = append(level, def)
+			}
+		}
+
+		// Keep the order within a level stable for predictable output
+		sort.Slice(level, func(i, j int) bool {
+			return level[i].ShortName < level[j].ShortName
+		})
+		for _, def := range level {
+			placed[def.ID] = true
+			delete(remaining, def.ID)
+		}
+		levels = append(levels, level)
+	}
+
+	return levels, nil
+}
@@ -0,0 +1,188 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+)
+
+// ServiceMapDrift is an entry of service-map.json that no longer matches the built-in
+// service definitions or the schema.
+type ServiceMapDrift struct {
+	ID      string
+	Problem string
+	// Fix repairs the map in place. It is nil when the entry needs manual attention.
+	Fix func(serviceMap *ServiceMapConfig)
+}
+
+// FindServiceMapDrift reports entries of a service map that have drifted: built-in services
+// listed under the wrong type, listed twice, or carrying a repo, source or short name that
+// differs from the built-in definition (and is silently ignored), required built-in services
+// that are missing, and user-defined entries that fail validation.
+func FindServiceMapDrift(serviceMap *ServiceMapConfig) []ServiceMapDrift {
+	var drift []ServiceMapDrift
+	seen := make(map[string]string)
+
+	for _, serviceType := range serviceMapTypes(serviceMap) {
+		for _, entry := range serviceMap.Services[serviceType] {
+			if entry.ID == "" {
+				drift = append(drift, ServiceMapDrift{
+					ID:      fmt.Sprintf("<%s entry>", serviceType),
+					Problem: "entry has no 'id'",
+				})
+				continue
+			}
+			if other, ok := seen[entry.ID]; ok {
+				id := entry.ID
+				drift = append(drift, ServiceMapDrift{
+					ID:      id,
+					Problem: fmt.Sprintf("listed under both '%s' and '%s'", other, serviceType),
+					Fix:     func(m *ServiceMapConfig) { dedupeServiceEntry(m, id) },
+				})
+				continue
+			}
+			seen[entry.ID] = serviceType
+
+			master, builtIn := builtInDefinition(entry.ID)
+			if !builtIn {
+				if err := entry.Validate(serviceType); err != nil {
+					drift = append(drift, ServiceMapDrift{ID: entry.ID, Problem: err.Error()})
+				}
+				continue
+			}
+
+			if serviceType != master.Type {
+				drift = append(drift, ServiceMapDrift{
+					ID:      entry.ID,
+					Problem: fmt.Sprintf("listed under '%s' instead of '%s'", serviceType, master.Type),
+					Fix:     func(m *ServiceMapConfig) { dedupeServiceEntry(m, master.ID) },
+				})
+			}
+			for _, field := range []struct{ name, got, want string }{
+				{"short_name", entry.ShortName, master.ShortName},
+				{"repo", entry.Repo, master.Repo},
+				{"source", entry.Source, master.Source},
+			} {
+				if field.got == "" || field.got == field.want {
+					continue
+				}
+				drift = append(drift, ServiceMapDrift{
+					ID:      entry.ID,
+					Problem: fmt.Sprintf("%s '%s' differs from the built-in '%s' and is ignored", field.name, field.got, field.want),
+					Fix:     func(m *ServiceMapConfig) { resetBuiltInFields(m, master) },
+				})
+			}
+		}
+	}
+
+	// CLI and OS services cannot be added or removed, so they must always be listed
+	for _, def := range serviceDefinitions {
+		if def.IsManageable() {
+			continue
+		}
+		if _, ok := seen[def.ID]; ok {
+			continue
+		}
+		master := def
+		drift = append(drift, ServiceMapDrift{
+			ID:      def.ID,
+			Problem: fmt.Sprintf("required '%s' service is missing", def.Type),
+			Fix: func(m *ServiceMapConfig) {
+				if m.Services == nil {
+					m.Services = make(map[string][]ServiceEntry)
+				}
+				if _, _, ok := findServiceEntry(m, master.ID); !ok {
+					m.Services[master.Type] = append(m.Services[master.Type], master.ToServiceEntry())
+				}
+			},
+		})
+	}
+	return drift
+}
+
+// Types returns the types of a service map, known types first in canonical order, so that
+// walking the map gives the same order every time.
+func (s *ServiceMapConfig) Types() []string {
+	return serviceMapTypes(s)
+}
+
+// serviceMapTypes returns the types of a service map, known types first in canonical order.
+func serviceMapTypes(serviceMap *ServiceMapConfig) []string {
+	types := append([]string{}, ServiceTypes...)
+	var unknown []string
+	for serviceType := range serviceMap.Services {
+		if !IsValidServiceType(serviceType) {
+			unknown = append(unknown, serviceType)
+		}
+	}
+	sort.Strings(unknown)
+	return append(types, unknown...)
+}
+
+// builtInDefinition returns the hardcoded definition of a built-in service.
+func builtInDefinition(id string) (ServiceDefinition, bool) {
+	for _, def := range serviceDefinitions {
+		if def.ID == id {
+			return def, true
+		}
+	}
+	return ServiceDefinition{}, false
+}
+
+// findServiceEntry returns the type and index of the first entry with an ID.
+func findServiceEntry(serviceMap *ServiceMapConfig, id string) (string, int, bool) {
+	for _, serviceType := range serviceMapTypes(serviceMap) {
+		for i, entry := range serviceMap.Services[serviceType] {
+			if entry.ID == id {
+				return serviceType, i, true
+			}
+		}
+	}
+	return "", 0, false
+}
+
+// dedupeServiceEntry leaves a single entry for an ID. A built-in service keeps the entry under
+// its own type, moving it there if needed; anything else keeps its first entry.
+func dedupeServiceEntry(serviceMap *ServiceMapConfig, id string) {
+	keepType, keepIndex, ok := findServiceEntry(serviceMap, id)
+	if !ok {
+		return
+	}
+	if master, builtIn := builtInDefinition(id); builtIn {
+		for i, entry := range serviceMap.Services[master.Type] {
+			if entry.ID == id {
+				keepType, keepIndex = master.Type, i
+				break
+			}
+		}
+	}
+	kept := serviceMap.Services[keepType][keepIndex]
+
+	for serviceType, entries := range serviceMap.Services {
+		filtered := entries[:0]
+		for _, entry := range entries {
+			if entry.ID != id {
+				filtered = append(filtered, entry)
+			}
+		}
+		serviceMap.Services[serviceType] = filtered
+	}
+
+	if master, builtIn := builtInDefinition(id); builtIn {
+		keepType = master.Type
+	}
+	serviceMap.Services[keepType] = append(serviceMap.Services[keepType], kept)
+}
+
+// resetBuiltInFields restores the fields of a built-in service's entry that the CLI always
+// takes from the built-in definition.
+func resetBuiltInFields(serviceMap *ServiceMapConfig, master ServiceDefinition) {
+	for _, entries := range serviceMap.Services {
+		for i := range entries {
+			if entries[i].ID == master.ID {
+				entries[i].ShortName = master.ShortName
+				entries[i].Repo = master.Repo
+				entries[i].Source = master.Source
+			}
+		}
+	}
+}
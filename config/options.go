@@ -16,6 +16,40 @@ type OptionsConfig struct {
 	Discord  DiscordOptions                    `json:"discord"`
 	Services map[string]map[string]interface{} `json:"services"`
 	Ollama   OllamaOptions                     `json:"ollama"`
+	Update   UpdateOptions                     `json:"update"`
+	Build    BuildOptions                      `json:"build"`
+	Release  ReleaseOptions                    `json:"release"`
+}
+
+// ReleaseOptions holds settings for publishing releases to easter.company
+type ReleaseOptions struct {
+	Retention RetentionOptions `json:"retention"`
+}
+
+// RetentionOptions decides which published releases 'dex release gc' and 'dex build' keep.
+// Unset fields use the defaults: every major, and the newest minor of each major.
+type RetentionOptions struct {
+	// KeepMinors is how many minor releases are kept for each major version
+	KeepMinors *int `json:"keep_minors,omitempty"`
+	// KeepMajors is how many major releases are kept; 0 keeps every major
+	KeepMajors *int `json:"keep_majors,omitempty"`
+}
+
+// BuildOptions holds settings for 'dex build'
+type BuildOptions struct {
+	// Platforms lists the GOOS-GOARCH targets Go services are compiled for, e.g. "linux-arm64".
+	// The host platform is always built. Empty means linux-amd64 and linux-arm64.
+	Platforms []string `json:"platforms,omitempty"`
+	// Jobs is how many services are built at once when -j is not given.
+	// Zero means the number of CPUs, up to four.
+	Jobs int `json:"jobs,omitempty"`
+}
+
+// UpdateOptions holds the settings 'dex update' remembers between runs
+type UpdateOptions struct {
+	// Channel is the release channel to follow: "stable", "beta" or "dev".
+	// Empty means stable for users and dev (HEAD) for developers.
+	Channel string `json:"channel,omitempty"`
 }
 
 // OllamaOptions holds configuration for model placement and optimization
@@ -100,3 +134,9 @@ func SaveOptionsConfig(options *OptionsConfig) error {
 
 	return os.WriteFile(optionsPath, data, 0o644)
 }
+
+// HealOptionsConfig adds any missing default values to the user's options.
+// Returns true if changes were made.
+func HealOptionsConfig(userOpts *OptionsConfig) bool {
+	return healOptionsConfig(userOpts, DefaultOptionsConfig())
+}
@@ -0,0 +1,282 @@
+// config/schema.go
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// ServiceTypes lists the service-map.json categories in their canonical order.
+var ServiceTypes = []string{"cli", "fe", "cs", "be", "th", "prd", "os"}
+
+// Build kinds understood by the build pipeline.
+const (
+	BuildKindGo       = "go"
+	BuildKindPython   = "python"
+	BuildKindFrontend = "frontend"
+	BuildKindNone     = "none"
+)
+
+// BuildKinds lists the accepted values for a service entry's "build" field.
+var BuildKinds = []string{BuildKindGo, BuildKindPython, BuildKindFrontend, BuildKindNone}
+
+// IsValidServiceType checks if a category name is a known service type.
+func IsValidServiceType(serviceType string) bool {
+	for _, t := range ServiceTypes {
+		if t == serviceType {
+			return true
+		}
+	}
+	return false
+}
+
+// Validate checks a single service entry against the service-map.json schema.
+// Built-in services only need an ID; user-defined services must carry enough
+// information for the CLI to manage them.
+func (e *ServiceEntry) Validate(serviceType string) error {
+	var problems []string
+
+	if !IsValidServiceType(serviceType) {
+		problems = append(problems, fmt.Sprintf("unknown service type '%s' (expected one of %s)", serviceType, strings.Join(ServiceTypes, ", ")))
+	}
+	if e.ID == "" {
+		problems = append(problems, "missing 'id'")
+	}
+	if e.Port != "" {
+		if port, err := strconv.Atoi(e.Port); err != nil || port < 1 || port > 65535 {
+			problems = append(problems, fmt.Sprintf("invalid 'port' %q", e.Port))
+		}
+	}
+	if e.BuildKind != "" {
+		valid := false
+		for _, kind := range BuildKinds {
+			if kind == e.BuildKind {
+				valid = true
+				break
+			}
+		}
+		if !valid {
+			problems = append(problems, fmt.Sprintf("invalid 'build' %q (expected one of %s)", e.BuildKind, strings.Join(BuildKinds, ", ")))
+		}
+	}
+	if e.HealthPath != "" && !strings.HasPrefix(e.HealthPath, "/") {
+		problems = append(problems, fmt.Sprintf("'health_path' %q must start with '/'", e.HealthPath))
+	}
+	if e.SystemdName != "" && !strings.HasSuffix(e.SystemdName, ".service") {
+		problems = append(problems, fmt.Sprintf("'systemd_name' %q must end with '.service'", e.SystemdName))
+	}
+	for _, dep := range e.DependsOn {
+		if strings.TrimSpace(dep) == "" {
+			problems = append(problems, "'depends_on' contains an empty name")
+		} else if dep == e.ID || (e.ShortName != "" && dep == e.ShortName) {
+			problems = append(problems, "'depends_on' cannot reference the service itself")
+		}
+	}
+	for _, dep := range e.BuildAfter {
+		if strings.TrimSpace(dep) == "" {
+			problems = append(problems, "'build_after' contains an empty name")
+		} else if dep == e.ID || (e.ShortName != "" && dep == e.ShortName) {
+			problems = append(problems, "'build_after' cannot reference the service itself")
+		}
+	}
+	checkNames := make(map[string]bool)
+	for _, check := range e.Checks {
+		switch {
+		case strings.TrimSpace(check.Name) == "":
+			problems = append(problems, "'checks' entry is missing a 'name'")
+		case checkNames[check.Name]:
+			problems = append(problems, fmt.Sprintf("'checks' has more than one check named %q", check.Name))
+		}
+		checkNames[check.Name] = true
+		if !strings.HasPrefix(check.Path, "/") {
+			problems = append(problems, fmt.Sprintf("check %q: 'path' %q must start with '/'", check.Name, check.Path))
+		}
+		if method := strings.ToUpper(check.Method); method != "" && method != "GET" && method != "POST" {
+			problems = append(problems, fmt.Sprintf("check %q: invalid 'method' %q (expected GET or POST)", check.Name, check.Method))
+		}
+		if check.Severity != "" && check.Severity != "critical" && check.Severity != "warning" {
+			problems = append(problems, fmt.Sprintf("check %q: invalid 'severity' %q (expected critical or warning)", check.Name, check.Severity))
+		}
+		if check.ExpectStatus < 0 || check.TimeoutSeconds < 0 {
+			problems = append(problems, fmt.Sprintf("check %q: 'expect_status' and 'timeout_seconds' cannot be negative", check.Name))
+		}
+	}
+	if e.Unit != nil {
+		problems = append(problems, e.Unit.validate()...)
+	}
+	if e.Backup != nil {
+		for _, artifact := range e.Backup.Artifacts {
+			if strings.TrimSpace(artifact) == "" {
+				problems = append(problems, "'backup.artifacts' contains an empty path")
+				break
+			}
+		}
+	}
+
+	// User-defined services have no master definition to fall back on
+	if e.ID != "" && !isBuiltInService(e.ID) {
+		if e.ShortName == "" {
+			problems = append(problems, "missing 'short_name'")
+		}
+		if serviceType != "cli" && serviceType != "os" && serviceType != "prd" && e.SystemdName == "" {
+			problems = append(problems, "missing 'systemd_name'")
+		}
+		if e.BuildKind != BuildKindNone && e.BuildKind != "" && e.Source == "" {
+			problems = append(problems, "missing 'source' for a buildable service")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// Validate checks every entry in the service map and ensures IDs and short names are unique.
+func (s *ServiceMapConfig) Validate() error {
+	if _, problems := s.ValidEntries(); len(problems) > 0 {
+		return fmt.Errorf("service-map.json failed validation:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return nil
+}
+
+// ValidEntries returns a copy of the service map without the entries that fail validation,
+// and one problem per entry left out. An entry whose ID or short name is already taken by an
+// earlier entry fails; types are walked in canonical order.
+func (s *ServiceMapConfig) ValidEntries() (*ServiceMapConfig, []string) {
+	valid := &ServiceMapConfig{Doc: s.Doc, Services: make(map[string][]ServiceEntry)}
+	var problems []string
+	seenIDs := make(map[string]string)
+	seenNames := make(map[string]string)
+
+	for _, serviceType := range serviceMapTypes(s) {
+		entries, ok := s.Services[serviceType]
+		if !ok {
+			continue
+		}
+		valid.Services[serviceType] = []ServiceEntry{}
+		for _, entry := range entries {
+			label := entry.ID
+			if label == "" {
+				label = fmt.Sprintf("<%s entry>", serviceType)
+			}
+
+			if err := entry.Validate(serviceType); err != nil {
+				problems = append(problems, fmt.Sprintf("%s: %v", label, err))
+				continue
+			}
+			if other, ok := seenIDs[entry.ID]; ok {
+				problems = append(problems, fmt.Sprintf("%s: duplicate id (also listed under '%s')", label, other))
+				continue
+			}
+
+			shortName := entry.ShortName
+			if master, builtIn := builtInDefinition(entry.ID); builtIn {
+				if shortName == "" {
+					shortName = master.ShortName
+				}
+			} else if owner := builtInShortNameOwner(shortName); owner != "" {
+				problems = append(problems, fmt.Sprintf("%s: short_name '%s' is reserved by built-in service %s", label, shortName, owner))
+				continue
+			}
+			if other, ok := seenNames[shortName]; ok && shortName != "" {
+				problems = append(problems, fmt.Sprintf("%s: short_name '%s' is already used by %s", label, shortName, other))
+				continue
+			}
+
+			seenIDs[entry.ID] = serviceType
+			if shortName != "" {
+				seenNames[shortName] = entry.ID
+			}
+			valid.Services[serviceType] = append(valid.Services[serviceType], entry)
+		}
+	}
+	return valid, problems
+}
+
+// UnitRestartPolicies lists the accepted values for a unit's "restart" field.
+var UnitRestartPolicies = []string{"no", "always", "on-success", "on-failure", "on-abnormal", "on-abort", "on-watchdog"}
+
+// unitSizePattern matches systemd sizes such as "512M", "2G", "50%" or "infinity"
+var unitSizePattern = regexp.MustCompile(`^([0-9]+(\.[0-9]+)?[KMGT]?|[0-9]+(\.[0-9]+)?%|infinity)$`)
+
+// validate checks a service's unit settings and returns its problems.
+func (u *UnitSettings) validate() []string {
+	var problems []string
+	for key, value := range u.Environment {
+		if key == "" || strings.ContainsAny(key, "= ") || hasControlChars(key) {
+			problems = append(problems, fmt.Sprintf("unit: invalid environment variable name %q", key))
+		}
+		if hasControlChars(value) {
+			problems = append(problems, fmt.Sprintf("unit: environment variable %q contains control characters", key))
+		}
+	}
+	if hasControlChars(u.EnvironmentFile) {
+		problems = append(problems, "unit: 'environment_file' must be a single path")
+	}
+	for _, path := range u.ReadWritePaths {
+		if strings.TrimSpace(path) == "" || strings.Contains(path, " ") || hasControlChars(path) {
+			problems = append(problems, fmt.Sprintf("unit: invalid 'read_write_paths' entry %q", path))
+		}
+	}
+	if u.MemoryMax != "" && !unitSizePattern.MatchString(u.MemoryMax) {
+		problems = append(problems, fmt.Sprintf("unit: invalid 'memory_max' %q (e.g. 512M, 2G or 50%%)", u.MemoryMax))
+	}
+	if u.CPUQuota != "" {
+		if quota, err := strconv.Atoi(strings.TrimSuffix(u.CPUQuota, "%")); err != nil || !strings.HasSuffix(u.CPUQuota, "%") || quota < 1 {
+			problems = append(problems, fmt.Sprintf("unit: invalid 'cpu_quota' %q (e.g. 150%%)", u.CPUQuota))
+		}
+	}
+	if u.Nice != nil && (*u.Nice < -20 || *u.Nice > 19) {
+		problems = append(problems, fmt.Sprintf("unit: 'nice' %d must be between -20 and 19", *u.Nice))
+	}
+	if u.Restart != "" && !containsString(UnitRestartPolicies, u.Restart) {
+		problems = append(problems, fmt.Sprintf("unit: invalid 'restart' %q (expected one of %s)", u.Restart, strings.Join(UnitRestartPolicies, ", ")))
+	}
+	if (u.RestartSec != nil && *u.RestartSec < 0) || u.RestartSteps < 0 || u.RestartMaxDelaySec < 0 || u.StartLimitBurst < 0 ||
+		(u.StartLimitIntervalSec != nil && *u.StartLimitIntervalSec < 0) {
+		problems = append(problems, "unit: restart and start limit settings cannot be negative")
+	}
+	if u.RestartSteps > 0 && u.RestartMaxDelaySec == 0 {
+		problems = append(problems, "unit: 'restart_steps' needs 'restart_max_delay_sec'")
+	}
+	if u.ProtectSystem != "" && !containsString([]string{"true", "false", "full", "strict"}, u.ProtectSystem) {
+		problems = append(problems, fmt.Sprintf("unit: invalid 'protect_system' %q (expected true, false, full or strict)", u.ProtectSystem))
+	}
+	if u.ProtectHome != "" && !containsString([]string{"true", "false", "read-only", "tmpfs"}, u.ProtectHome) {
+		problems = append(problems, fmt.Sprintf("unit: invalid 'protect_home' %q (expected true, false, read-only or tmpfs)", u.ProtectHome))
+	}
+	return problems
+}
+
+// hasControlChars reports whether a value contains control characters such as newlines, which
+// would end the line in a unit file and start a directive of their own.
+func hasControlChars(value string) bool {
+	return strings.IndexFunc(value, unicode.IsControl) >= 0
+}
+
+// containsString reports whether a list contains a value.
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// builtInShortNameOwner returns the ID of the built-in service using a short name, if any.
+func builtInShortNameOwner(shortName string) string {
+	if shortName == "" {
+		return ""
+	}
+	for _, def := range serviceDefinitions {
+		if def.ShortName == shortName {
+			return def.ID
+		}
+	}
+	return ""
+}
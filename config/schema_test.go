@@ -0,0 +1,33 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestUnitSettingsValidate(t *testing.T) {
+	zero, negative := 0, -1
+	tests := []struct {
+		name  string
+		unit  UnitSettings
+		wants string
+	}{
+		{"valid", UnitSettings{Environment: map[string]string{"LOG_LEVEL": "debug"}, RestartSec: &zero}, ""},
+		{"newline in environment value", UnitSettings{Environment: map[string]string{"A": "x\nExecStartPre=/bin/true"}}, "control characters"},
+		{"tab in environment value", UnitSettings{Environment: map[string]string{"A": "x\ty"}}, "control characters"},
+		{"control character in environment name", UnitSettings{Environment: map[string]string{"A\rB": "x"}}, "invalid environment variable name"},
+		{"control character in environment file", UnitSettings{EnvironmentFile: "~/a.env\x00"}, "environment_file"},
+		{"negative restart delay", UnitSettings{RestartSec: &negative}, "cannot be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			problems := strings.Join(tt.unit.validate(), "; ")
+			if tt.wants == "" && problems != "" {
+				t.Errorf("validate() = %s, want no problems", problems)
+			}
+			if tt.wants != "" && !strings.Contains(problems, tt.wants) {
+				t.Errorf("validate() = %q, want a problem mentioning %q", problems, tt.wants)
+			}
+		})
+	}
+}
@@ -8,6 +8,9 @@ import (
 	"path/filepath"
 	"sort"
 	"strconv"
+	"sync"
+
+	"github.com/EasterCompany/dex-cli/ui"
 )
 
 // ServiceDefinition is the universal, hardcoded definition for all services.
@@ -32,12 +35,87 @@ type ServiceDefinition struct {
 	Credentials *ServiceCredentials
 	// Backup configuration
 	Backup *BackupConfig
+	// BuildKind selects the build pipeline, e.g., "go", "python", "frontend" or "none".
+	// Empty means the pipeline is detected from the source tree.
+	BuildKind string
+	// HealthPath is the HTTP path of the service report, defaults to "/service"
+	HealthPath string
+	// DependsOn lists the short names of services that must be ready before this one starts
+	DependsOn []string
+	// BuildAfter lists the short names of services 'dex build' must finish building before
+	// this one starts; everything else is built in parallel
+	BuildAfter []string
+	// Checks are extra diagnostics 'dex verify' runs against the service
+	Checks []ServiceCheck
+	// Unit holds systemd settings written to the service's drop-in override file
+	Unit *UnitSettings
 }
 
 // BackupConfig defines the backup settings for a service.
 type BackupConfig struct {
 	// Artifacts is a list of paths to back up.
-	Artifacts []string
+	Artifacts []string `json:"artifacts"`
+}
+
+// UnitSettings are per-service systemd settings declared in service-map.json. They are written
+// to a drop-in file next to the generated unit, so they survive its regeneration.
+type UnitSettings struct {
+	// Environment sets variables for the service process
+	Environment map[string]string `json:"environment,omitempty"`
+	// EnvironmentFile is a file of KEY=value lines to load; a leading "-" makes it optional
+	EnvironmentFile string `json:"environment_file,omitempty"`
+	// MemoryMax is a hard memory limit, e.g. "2G" or "50%"
+	MemoryMax string `json:"memory_max,omitempty"`
+	// CPUQuota caps CPU time relative to one core, e.g. "150%"
+	CPUQuota string `json:"cpu_quota,omitempty"`
+	// Nice is the scheduling priority, from -20 to 19
+	Nice *int `json:"nice,omitempty"`
+	// Restart is the restart policy, e.g. "on-failure"; the generated unit uses "always"
+	Restart string `json:"restart,omitempty"`
+	// RestartSec is the delay before the first restart; the generated unit uses 5. It is a
+	// pointer so that 0 (restart immediately) can be set
+	RestartSec *int `json:"restart_sec,omitempty"`
+	// RestartSteps and RestartMaxDelaySec grow the delay between restarts up to a maximum
+	RestartSteps       int `json:"restart_steps,omitempty"`
+	RestartMaxDelaySec int `json:"restart_max_delay_sec,omitempty"`
+	// StartLimitBurst restarts within StartLimitIntervalSec make systemd give up on the service;
+	// an interval of 0 turns the limit off
+	StartLimitBurst       int  `json:"start_limit_burst,omitempty"`
+	StartLimitIntervalSec *int `json:"start_limit_interval_sec,omitempty"`
+	// ProtectSystem makes /usr and /etc read-only: "true", "full" or "strict"
+	ProtectSystem string `json:"protect_system,omitempty"`
+	// ProtectHome hides or protects home directories: "true", "read-only" or "tmpfs"
+	ProtectHome string `json:"protect_home,omitempty"`
+	// ReadWritePaths stay writable under ProtectSystem=strict or ProtectHome=read-only,
+	// e.g. "~/Dexter"
+	ReadWritePaths []string `json:"read_write_paths,omitempty"`
+	// PrivateTmp gives the service its own /tmp
+	PrivateTmp bool `json:"private_tmp,omitempty"`
+	// NoNewPrivileges stops the service and its children from gaining privileges
+	NoNewPrivileges bool `json:"no_new_privileges,omitempty"`
+}
+
+// ServiceCheck is a diagnostic declared in service-map.json: an HTTP request to the service
+// and the response it must give.
+type ServiceCheck struct {
+	// Name identifies the check; 'dex verify' registers it as "<short_name>.<name>"
+	Name string `json:"name"`
+	// Path is the HTTP path to request, e.g. "/queue"
+	Path string `json:"path"`
+	// Method is GET (the default) or POST
+	Method string `json:"method,omitempty"`
+	// Body is sent as JSON with a POST
+	Body string `json:"body,omitempty"`
+	// ExpectStatus is the required status code, 200 if unset
+	ExpectStatus int `json:"expect_status,omitempty"`
+	// Contains is text the response body must include
+	Contains string `json:"contains,omitempty"`
+	// Severity is "critical" (the default) or "warning", which is reported without failing
+	Severity string `json:"severity,omitempty"`
+	// Remediation is shown when the check fails
+	Remediation string `json:"remediation,omitempty"`
+	// TimeoutSeconds bounds the request, 5 seconds if unset
+	TimeoutSeconds int `json:"timeout_seconds,omitempty"`
 }
 
 // ToServiceEntry converts a hardcoded Definition to a ServiceEntry for saving.
@@ -45,11 +123,19 @@ func (def *ServiceDefinition) ToServiceEntry() ServiceEntry {
 	return ServiceEntry{
 		ID:          def.ID,
 		ShortName:   def.ShortName,
+		SystemdName: def.SystemdName,
 		Repo:        def.Repo,
 		Source:      def.Source,
 		Domain:      def.Domain,
 		Port:        def.Port,
 		Credentials: def.Credentials,
+		BuildKind:   def.BuildKind,
+		HealthPath:  def.HealthPath,
+		Backup:      def.Backup,
+		DependsOn:   def.DependsOn,
+		BuildAfter:  def.BuildAfter,
+		Checks:      def.Checks,
+		Unit:        def.Unit,
 	}
 }
 
@@ -66,6 +152,26 @@ func (def *ServiceDefinition) GetHTTP(path string) string {
 	return fmt.Sprintf("http://%s%s", def.GetHost(), path)
 }
 
+// GetHealthPath returns the HTTP path of the service's health report.
+func (def *ServiceDefinition) GetHealthPath() string {
+	if def.HealthPath == "" {
+		return "/service"
+	}
+	return def.HealthPath
+}
+
+// GetBuildKind returns the build pipeline for the service.
+// Frontend services default to "frontend"; everything else is detected from source.
+func (def *ServiceDefinition) GetBuildKind() string {
+	if def.BuildKind != "" {
+		return def.BuildKind
+	}
+	if def.Type == "fe" {
+		return BuildKindFrontend
+	}
+	return ""
+}
+
 // GetWS returns the full WebSocket address.
 func (def *ServiceDefinition) GetWS(path string) string {
 	return fmt.Sprintf("ws://%s%s", def.GetHost(), path)
@@ -97,9 +203,17 @@ func (def *ServiceDefinition) IsManageable() bool {
 
 // IsBuildable indicates if a service is built from source.
 func (def *ServiceDefinition) IsBuildable() bool {
+	if def.BuildKind == BuildKindNone {
+		return false
+	}
 	return def.Type == "cli" || def.IsManageable()
 }
 
+// IsBuiltIn indicates if a service is part of the hardcoded master list.
+func (def *ServiceDefinition) IsBuiltIn() bool {
+	return isBuiltInService(def.ID)
+}
+
 // IsTestable indicates if a service supports testing/formatting/linting (primarily Go programs).
 func (def *ServiceDefinition) IsTestable() bool {
 	// Skip Frontend (HTML/JS) and OS services
@@ -125,6 +239,7 @@ var serviceDefinitions = []ServiceDefinition{
 		Type:        "cs",
 		Repo:        "git@github.com:EasterCompany/dex-event-service.git",
 		Source:      "~/EasterCompany/dex-event-service",
+		DependsOn:   []string{"cache0"},
 		Domain:      "127.0.0.1", Port: "8100",
 	},
 	// Backend Services (be)
@@ -135,6 +250,7 @@ var serviceDefinitions = []ServiceDefinition{
 		Type:        "be",
 		Repo:        "git@github.com:EasterCompany/dex-tts-service.git",
 		Source:      "~/EasterCompany/dex-tts-service",
+		DependsOn:   []string{"event"},
 		Domain:      "127.0.0.1", Port: "8200",
 	},
 	{
@@ -144,6 +260,7 @@ var serviceDefinitions = []ServiceDefinition{
 		Type:        "be",
 		Repo:        "git@github.com:EasterCompany/dex-web-service.git",
 		Source:      "~/EasterCompany/dex-web-service",
+		DependsOn:   []string{"event"},
 		Domain:      "127.0.0.1", Port: "8201",
 	},
 	{
@@ -153,6 +270,7 @@ var serviceDefinitions = []ServiceDefinition{
 		Type:        "be",
 		Repo:        "git@github.com:EasterCompany/dex-stt-service.git",
 		Source:      "~/EasterCompany/dex-stt-service",
+		DependsOn:   []string{"event"},
 		Domain:      "127.0.0.1", Port: "8202",
 	},
 	// 3rd Party (th)
@@ -163,6 +281,7 @@ var serviceDefinitions = []ServiceDefinition{
 		Type:        "th",
 		Repo:        "git@github.com:EasterCompany/dex-discord-service.git",
 		Source:      "~/EasterCompany/dex-discord-service",
+		DependsOn:   []string{"event", "cache0"},
 		Domain:      "127.0.0.1", Port: "8300",
 	},
 	// Frontend (fe)
@@ -173,6 +292,7 @@ var serviceDefinitions = []ServiceDefinition{
 		Type:        "fe",
 		Repo:        "git@github.com:EasterCompany/easter.company.git",
 		Source:      "~/EasterCompany/easter.company",
+		BuildAfter:  []string{"cli"}, // Ships alongside the CLI release, so it builds last
 		Domain:      "127.0.0.1", Port: "8000",
 	},
 	{
@@ -227,14 +347,95 @@ var serviceDefinitions = []ServiceDefinition{
 	},
 }
 
-// GetAllServices returns a copy of the master service list.
+// GetAllServices returns a copy of the master service list, followed by any
+// user-defined services registered in service-map.json.
 func GetAllServices() []ServiceDefinition {
 	// Return a copy to prevent modification of the original slice
 	defs := make([]ServiceDefinition, len(serviceDefinitions))
 	copy(defs, serviceDefinitions)
+	return append(defs, GetUserDefinedServices()...)
+}
+
+// GetUserDefinedServices returns the services declared in service-map.json that are
+// not part of the hardcoded master list. Entries that fail validation are skipped.
+func GetUserDefinedServices() []ServiceDefinition {
+	serviceMap, err := LoadValidServiceMap()
+	if err != nil {
+		return nil
+	}
+
+	defs := []ServiceDefinition{}
+	for _, serviceType := range ServiceTypes {
+		for _, entry := range serviceMap.Services[serviceType] {
+			if isBuiltInService(entry.ID) {
+				continue
+			}
+			defs = append(defs, entry.ToServiceDefinition(serviceType))
+		}
+	}
 	return defs
 }
 
+// validServiceMap caches service-map.json for the rest of the command, so it is read and
+// validated once. SaveServiceMapConfig clears it.
+var validServiceMap struct {
+	sync.Mutex
+	loaded     bool
+	serviceMap *ServiceMapConfig
+	err        error
+}
+
+// LoadValidServiceMap returns service-map.json, or the default map when there is none, with
+// the entries that fail validation left out. Each entry left out is reported once, on stderr
+// and in the log. The result is shared by every caller and must not be modified.
+func LoadValidServiceMap() (*ServiceMapConfig, error) {
+	validServiceMap.Lock()
+	defer validServiceMap.Unlock()
+	if validServiceMap.loaded {
+		return validServiceMap.serviceMap, validServiceMap.err
+	}
+
+	serviceMap, err := LoadServiceMapConfig()
+	if os.IsNotExist(err) {
+		serviceMap, err = DefaultServiceMapConfig(), nil
+	}
+	if err != nil {
+		err = fmt.Errorf("failed to load service-map.json: %w", err)
+		Log(err.Error())
+	} else {
+		var problems []string
+		serviceMap, problems = serviceMap.ValidEntries()
+		for _, problem := range problems {
+			message := fmt.Sprintf("Skipping invalid service-map.json entry %s", problem)
+			Log(message)
+			_, _ = fmt.Fprintf(os.Stderr, "%s! %s%s\n", ui.ColorYellow, message, ui.ColorReset)
+		}
+	}
+
+	validServiceMap.loaded = true
+	validServiceMap.serviceMap, validServiceMap.err = serviceMap, err
+	return serviceMap, err
+}
+
+// ReloadServiceMap drops the cached service map, so the next lookup reads service-map.json
+// again. Call it after replacing the file other than through SaveServiceMapConfig.
+func ReloadServiceMap() {
+	validServiceMap.Lock()
+	defer validServiceMap.Unlock()
+	validServiceMap.loaded = false
+	validServiceMap.serviceMap, validServiceMap.err = nil, nil
+}
+
+// isBuiltInService checks if an ID belongs to the hardcoded master list.
+func isBuiltInService(id string) bool {
+	for _, def := range serviceDefinitions {
+		if def.ID == id {
+			return true
+		}
+	}
+	return false
+}
+
 // GetManageableServices returns all services that can be managed (not cli or os).
 func GetManageableServices() []ServiceDefinition {
 	defs := []ServiceDefinition{}
@@ -264,6 +465,11 @@ func GetServiceDefinition(id string) ServiceDefinition {
 			return def
 		}
 	}
+	for _, def := range GetUserDefinedServices() {
+		if def.ID == id {
+			return def
+		}
+	}
 	return ServiceDefinition{}
 }
 
@@ -331,15 +537,46 @@ func (s *ServiceMapConfig) MarshalJSON() ([]byte, error) {
 	return json.Marshal(&alias)
 }
 
-// ServiceEntry represents a single service in the service map
+// ServiceEntry represents a single service in the service map.
+// The service type is the category key the entry is listed under.
 type ServiceEntry struct {
 	ID          string              `json:"id"`
 	ShortName   string              `json:"short_name,omitempty"`
+	SystemdName string              `json:"systemd_name,omitempty"`
 	Repo        string              `json:"repo"`
 	Source      string              `json:"source"`
 	Domain      string              `json:"domain,omitempty"`
 	Port        string              `json:"port,omitempty"`
 	Credentials *ServiceCredentials `json:"credentials,omitempty"`
+	BuildKind   string              `json:"build,omitempty"`
+	HealthPath  string              `json:"health_path,omitempty"`
+	Backup      *BackupConfig       `json:"backup,omitempty"`
+	DependsOn   []string            `json:"depends_on,omitempty"`
+	BuildAfter  []string            `json:"build_after,omitempty"`
+	Checks      []ServiceCheck      `json:"checks,omitempty"`
+	Unit        *UnitSettings       `json:"unit,omitempty"`
+}
+
+// ToServiceDefinition converts a user-defined ServiceEntry into a full Definition.
+func (e *ServiceEntry) ToServiceDefinition(serviceType string) ServiceDefinition {
+	return ServiceDefinition{
+		ID:          e.ID,
+		ShortName:   e.ShortName,
+		SystemdName: e.SystemdName,
+		Type:        serviceType,
+		Repo:        e.Repo,
+		Source:      e.Source,
+		Domain:      e.Domain,
+		Port:        e.Port,
+		Credentials: e.Credentials,
+		Backup:      e.Backup,
+		BuildKind:   e.BuildKind,
+		HealthPath:  e.HealthPath,
+		DependsOn:   e.DependsOn,
+		BuildAfter:  e.BuildAfter,
+		Checks:      e.Checks,
+		Unit:        e.Unit,
+	}
 }
 
 // ServiceCredentials holds connection credentials for services (e.g., Redis)
@@ -399,5 +636,6 @@ func SaveServiceMapConfig(serviceMap *ServiceMapConfig) error {
 		return fmt.Errorf("failed to marshal service-map.json: %w", err)
 	}
 
+	defer ReloadServiceMap()
 	return os.WriteFile(serviceMapPath, data, 0o644)
 }
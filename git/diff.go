@@ -93,6 +93,42 @@ func GetCommitLogBetween(repoPath, oldRef, newRef string) (string, error) {
 	return log, nil
 }
 
+// Commit is a single entry from git log.
+type Commit struct {
+	Hash    string
+	Subject string
+}
+
+// GetCommitsBetween returns the commits reachable from newRef but not oldRef, newest first.
+// An empty or unknown oldRef lists every commit up to newRef.
+func GetCommitsBetween(repoPath, oldRef, newRef string) ([]Commit, error) {
+	revRange := newRef
+	if oldRef != "" {
+		revRange = fmt.Sprintf("%s..%s", oldRef, newRef)
+	}
+
+	// %h is the abbreviated hash, %s the subject; a unit separator keeps them apart
+	cmd := exec.Command("git", "log", "--no-merges", "--pretty=format:%h\x1f%s", revRange)
+	cmd.Dir = repoPath
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if oldRef != "" && strings.Contains(string(output), "bad revision") {
+			return GetCommitsBetween(repoPath, "", newRef)
+		}
+		return nil, fmt.Errorf("git log command failed: %w\nOutput: %s", err, string(output))
+	}
+
+	var commits []Commit
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, subject, ok := strings.Cut(line, "\x1f")
+		if !ok {
+			continue
+		}
+		commits = append(commits, Commit{Hash: hash, Subject: strings.TrimSpace(subject)})
+	}
+	return commits, nil
+}
+
 // GetCommitMessage returns the commit message for a specific commit reference.
 func GetCommitMessage(repoPath, commitRef string) (string, error) {
 	if commitRef == "" {
@@ -12,18 +12,48 @@ import (
 // It lists all tags, filters for semantic versions, sorts them, and returns the highest.
 // If no tags are found, it returns a default of "v0.0.0" so the build process can proceed.
 func GetLatestTag(repoPath string) (string, error) {
+	semverTags, err := listSemverTags(repoPath)
+	if err != nil {
+		return "", err
+	}
+	if len(semverTags) == 0 {
+		return "v0.0.0", nil
+	}
+	return semverTags[0], nil
+}
+
+// GetPreviousReleaseTag returns the highest minor or major release tag (X.Y.0) below version,
+// or "" if the repository has none.
+func GetPreviousReleaseTag(repoPath, version string) (string, error) {
+	semverTags, err := listSemverTags(repoPath)
+	if err != nil {
+		return "", err
+	}
+	current := parseVersion(version)
+	for _, tag := range semverTags {
+		v := parseVersion(tag)
+		if v[2] != 0 || compareVersions(v, current) >= 0 {
+			continue
+		}
+		return tag, nil
+	}
+	return "", nil
+}
+
+// listSemverTags returns the repository's X.Y.Z (or vX.Y.Z) tags, highest first.
+func listSemverTags(repoPath string) ([]string, error) {
 	// List all tags
 	cmd := exec.Command("git", "tag", "-l")
 	cmd.Dir = repoPath
 	output, err := cmd.CombinedOutput()
 	if err != nil {
-		return "", fmt.Errorf("git tag -l failed in %s: %w\nOutput: %s", repoPath, err, string(output))
+		return nil, fmt.Errorf("git tag -l failed in %s: %w\nOutput: %s", repoPath, err, string(output))
 	}
 
 	// Parse tags into semantic versions
 	tagsOutput := strings.TrimSpace(string(output))
 	if tagsOutput == "" {
-		return "v0.0.0", nil
+		return nil, nil
 	}
 
 	tags := strings.Split(tagsOutput, "\n")
@@ -46,24 +76,25 @@ func GetLatestTag(repoPath string) (string, error) {
 		}
 	}
 
-	if len(semverTags) == 0 {
-		return "v0.0.0", nil
-	}
-
 	// Sort tags by semantic version (highest first)
 	sort.Slice(semverTags, func(i, j int) bool {
-		vi := parseVersion(semverTags[i])
-		vj := parseVersion(semverTags[j])
-		if vi[0] != vj[0] {
-			return vi[0] > vj[0] // Major
-		}
-		if vi[1] != vj[1] {
-			return vi[1] > vj[1] // Minor
-		}
-		return vi[2] > vj[2] // Patch
+		return compareVersions(parseVersion(semverTags[i]), parseVersion(semverTags[j])) > 0
 	})
 
-	return semverTags[0], nil
+	return semverTags, nil
+}
+
+// compareVersions orders two parsed versions, returning -1, 0 or 1.
+func compareVersions(a, b [3]int) int {
+	for i := range a {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
 }
 
 // parseVersion parses a version string like "1.2.3" or "v1.2.3" into [major, minor, patch]
@@ -2,9 +2,13 @@ module github.com/EasterCompany/dex-cli
 
 go 1.25.3
 
-require github.com/redis/go-redis/v9 v9.5.4
+require (
+	github.com/redis/go-redis/v9 v9.5.4
+	golang.org/x/crypto v0.43.0
+)
 
 require (
 	github.com/cespare/xxhash/v2 v2.2.0 // indirect
 	github.com/dgryski/go-rendezvous v0.0.0-20200823014737-9f7001d12a5f // indirect
+	golang.org/x/sys v0.37.0 // indirect
 )
@@ -0,0 +1,203 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Severity decides whether a failed check fails the verification as a whole
+type Severity string
+
+const (
+	// SeverityCritical failures fail 'dex verify'
+	SeverityCritical Severity = "critical"
+	// SeverityWarning failures are reported but do not fail 'dex verify'
+	SeverityWarning Severity = "warning"
+)
+
+// Check categories, in the order they are run and reported
+const (
+	CategoryInfrastructure = "infrastructure"
+	CategoryServices       = "services"
+	CategoryDeep           = "deep"
+)
+
+// Categories lists the built-in check categories in run order
+var Categories = []string{CategoryInfrastructure, CategoryServices, CategoryDeep}
+
+// Status is the outcome of running a check
+type Status string
+
+const (
+	StatusPassed  Status = "passed"
+	StatusFailed  Status = "failed"
+	StatusSkipped Status = "skipped"
+)
+
+// Check is one diagnostic 'dex verify' can run.
+type Check interface {
+	// Name identifies the check for --only, e.g. "redis"
+	Name() string
+	// Category groups checks in reports, e.g. CategoryInfrastructure
+	Category() string
+	Severity() Severity
+	// Remediation tells the user what to do when the check fails
+	Remediation() string
+	// Run performs the check and returns a short description of what it found.
+	// Returning a SkipError marks the check as skipped rather than failed.
+	Run(ctx context.Context) (string, error)
+}
+
+// SkipError reports that a check does not apply, e.g. because its service is not installed
+type SkipError struct {
+	Reason string
+}
+
+func (e *SkipError) Error() string {
+	return e.Reason
+}
+
+// Skip returns a SkipError for a check's Run method
+func Skip(format string, args ...interface{}) error {
+	return &SkipError{Reason: fmt.Sprintf(format, args...)}
+}
+
+// Result is a check that has been run
+type Result struct {
+	Name        string        `json:"name"`
+	Category    string        `json:"category"`
+	Severity    Severity      `json:"severity"`
+	Status      Status        `json:"status"`
+	Message     string        `json:"message,omitempty"`
+	Remediation string        `json:"remediation,omitempty"`
+	Duration    time.Duration `json:"duration_ns"`
+}
+
+// Failing reports whether the result should fail the verification
+func (r Result) Failing() bool {
+	return r.Status == StatusFailed && r.Severity == SeverityCritical
+}
+
+// funcCheck is a Check built from a function
+type funcCheck struct {
+	name, category, remediation string
+	severity                    Severity
+	run                         func(ctx context.Context) (string, error)
+}
+
+// NewCheck creates a Check from a function.
+func NewCheck(name, category string, severity Severity, remediation string, run func(ctx context.Context) (string, error)) Check {
+	return &funcCheck{name: name, category: category, severity: severity, remediation: remediation, run: run}
+}
+
+func (c *funcCheck) Name() string                            { return c.name }
+func (c *funcCheck) Category() string                        { return c.category }
+func (c *funcCheck) Severity() Severity                      { return c.severity }
+func (c *funcCheck) Remediation() string                     { return c.remediation }
+func (c *funcCheck) Run(ctx context.Context) (string, error) { return c.run(ctx) }
+
+// Registry holds the checks 'dex verify' knows about, in registration order
+type Registry struct {
+	checks []Check
+}
+
+// Register adds checks to the registry. A check replaces an earlier one with the same name.
+func (r *Registry) Register(checks ...Check) {
+	for _, check := range checks {
+		replaced := false
+		for i, existing := range r.checks {
+			if existing.Name() == check.Name() {
+				r.checks[i] = check
+				replaced = true
+				break
+			}
+		}
+		if !replaced {
+			r.checks = append(r.checks, check)
+		}
+	}
+}
+
+// Checks returns every registered check, ordered by category and then registration
+func (r *Registry) Checks() []Check {
+	checks := append([]Check{}, r.checks...)
+	sort.SliceStable(checks, func(i, j int) bool {
+		return categoryRank(checks[i].Category()) < categoryRank(checks[j].Category())
+	})
+	return checks
+}
+
+// Select returns the checks matching any of the given names or categories. No names selects
+// every check; a name that matches nothing is an error.
+func (r *Registry) Select(names []string) ([]Check, error) {
+	all := r.Checks()
+	if len(names) == 0 {
+		return all, nil
+	}
+
+	wanted := make(map[string]bool)
+	for _, name := range names {
+		matched := false
+		for _, check := range all {
+			if check.Name() == name || check.Category() == name {
+				matched = true
+				wanted[check.Name()] = true
+			}
+		}
+		if !matched {
+			available := make([]string, 0, len(all))
+			for _, check := range all {
+				available = append(available, check.Name())
+			}
+			return nil, fmt.Errorf("unknown check '%s' (available: %s)", name, strings.Join(available, ", "))
+		}
+	}
+
+	var selected []Check
+	for _, check := range all {
+		if wanted[check.Name()] {
+			selected = append(selected, check)
+		}
+	}
+	return selected, nil
+}
+
+// RunCheck runs one check and times it.
+func RunCheck(ctx context.Context, check Check) Result {
+	result := Result{
+		Name:     check.Name(),
+		Category: check.Category(),
+		Severity: check.Severity(),
+		Status:   StatusPassed,
+	}
+	start := time.Now()
+	message, err := check.Run(ctx)
+	result.Duration = time.Since(start)
+	result.Message = message
+
+	var skip *SkipError
+	switch {
+	case errors.As(err, &skip):
+		result.Status = StatusSkipped
+		result.Message = skip.Reason
+	case err != nil:
+		result.Status = StatusFailed
+		result.Message = err.Error()
+		result.Remediation = check.Remediation()
+	}
+	return result
+}
+
+// categoryRank orders the built-in categories first, then any others
+func categoryRank(category string) int {
+	for i, known := range Categories {
+		if category == known {
+			return i
+		}
+	}
+	return len(Categories)
+}
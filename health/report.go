@@ -0,0 +1,156 @@
+package health
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Report is the outcome of a 'dex verify' run
+type Report struct {
+	Passed   bool          `json:"passed"`
+	Duration time.Duration `json:"duration_ns"`
+	Counts   ReportCounts  `json:"counts"`
+	Results  []Result      `json:"results"`
+}
+
+// ReportCounts totals the results of a report by status
+type ReportCounts struct {
+	Passed   int `json:"passed"`
+	Failed   int `json:"failed"`
+	Skipped  int `json:"skipped"`
+	Warnings int `json:"warnings"` // Failed checks that do not fail the run
+}
+
+// NewReport totals a set of results
+func NewReport(results []Result, duration time.Duration) *Report {
+	report := &Report{Passed: true, Duration: duration, Results: results}
+	for _, result := range results {
+		switch {
+		case result.Status == StatusPassed:
+			report.Counts.Passed++
+		case result.Status == StatusSkipped:
+			report.Counts.Skipped++
+		case result.Failing():
+			report.Counts.Failed++
+			report.Passed = false
+		default:
+			report.Counts.Warnings++
+		}
+	}
+	return report
+}
+
+// WriteJSON writes the report as indented JSON
+func (r *Report) WriteJSON(w io.Writer) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Name     string           `xml:"name,attr"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Skipped  int              `xml:"skipped,attr"`
+	Time     string           `xml:"time,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name     string          `xml:"name,attr"`
+	Tests    int             `xml:"tests,attr"`
+	Failures int             `xml:"failures,attr"`
+	Skipped  int             `xml:"skipped,attr"`
+	Time     string          `xml:"time,attr"`
+	Cases    []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	Time      string        `xml:"time,attr"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	Skipped   *junitSkipped `xml:"skipped,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+type junitSkipped struct {
+	Message string `xml:"message,attr"`
+}
+
+// WriteJUnit writes the report as JUnit XML, one test suite per category. Only failures
+// of critical checks are reported as JUnit failures; failed warnings pass with their message
+// in system-out, so CI fails on the same checks 'dex verify' does.
+func (r *Report) WriteJUnit(w io.Writer) error {
+	suites := junitTestSuites{Name: "dex verify", Time: junitSeconds(r.Duration)}
+	index := make(map[string]int)
+	for _, result := range r.Results {
+		i, ok := index[result.Category]
+		if !ok {
+			i = len(suites.Suites)
+			index[result.Category] = i
+			suites.Suites = append(suites.Suites, junitTestSuite{Name: result.Category})
+		}
+		suite := &suites.Suites[i]
+
+		testCase := junitTestCase{
+			Name:      result.Name,
+			ClassName: "dex.verify." + result.Category,
+			Time:      junitSeconds(result.Duration),
+		}
+		switch {
+		case result.Status == StatusSkipped:
+			testCase.Skipped = &junitSkipped{Message: result.Message}
+			suite.Skipped++
+			suites.Skipped++
+		case result.Failing():
+			text := result.Message
+			if result.Remediation != "" {
+				text += "\nRemediation: " + result.Remediation
+			}
+			testCase.Failure = &junitFailure{Message: result.Message, Type: string(result.Severity), Text: text}
+			suite.Failures++
+			suites.Failures++
+		default:
+			testCase.SystemOut = result.Message
+		}
+		suite.Cases = append(suite.Cases, testCase)
+		suite.Tests++
+		suites.Tests++
+	}
+	for i := range suites.Suites {
+		var total time.Duration
+		for _, result := range r.Results {
+			if result.Category == suites.Suites[i].Name {
+				total += result.Duration
+			}
+		}
+		suites.Suites[i].Time = junitSeconds(total)
+	}
+
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(suites); err != nil {
+		return err
+	}
+	_, err := fmt.Fprintln(w)
+	return err
+}
+
+// junitSeconds formats a duration the way JUnit expects
+func junitSeconds(d time.Duration) string {
+	return fmt.Sprintf("%.3f", d.Seconds())
+}
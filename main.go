@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"time"
@@ -36,7 +37,8 @@ func main() {
 	}
 	os.Args = newArgs
 
-	if len(os.Args) > 1 && os.Args[1] != "version" {
+	// doctor checks the Python environment itself, so it can repair it
+	if len(os.Args) > 1 && os.Args[1] != "version" && os.Args[1] != "doctor" {
 		command := os.Args[1]
 		isVerboseCommand := command == "build" || command == "update" || command == "test"
 		if err := utils.EnsurePythonVenv(!isVerboseCommand); err != nil {
@@ -61,6 +63,22 @@ func main() {
 
 	command := os.Args[1]
 
+	// --host runs the command on a server from server-map.json instead of locally; other
+	// commands keep --host for their own flag parsing
+	if cmd.SupportsRemote(command) {
+		host, args, err := cmd.ExtractHostFlag(os.Args[2:])
+		if err != nil {
+			fmt.Println() // Add padding at the start
+			ui.PrintError(err.Error())
+			fmt.Println() // Add padding at the end
+			os.Exit(1)
+		}
+		if host != "" {
+			runCommand(func() error { return cmd.Remote(append([]string{host, command}, args...)) })
+			return
+		}
+	}
+
 	if !config.IsCommandAvailable(command) {
 		fmt.Println() // Add padding at the start
 		ui.PrintError(fmt.Sprintf("Command '%s' is not available", command))
@@ -74,7 +92,10 @@ func main() {
 		runCommand(func() error { return cmd.Update(os.Args[2:]) })
 
 	case "verify":
-		runCommand(func() error { return cmd.Verify() })
+		runCommand(func() error { return cmd.Verify(os.Args[2:]) })
+
+	case "doctor":
+		runCommand(func() error { return cmd.Doctor(os.Args[2:]) })
 
 	case "system":
 		runCommand(func() error { return cmd.System(os.Args[2:]) })
@@ -94,15 +115,26 @@ func main() {
 	case "build":
 		runCommand(func() error { return cmd.Build(os.Args[2:]) })
 
+	case "release":
+		runCommand(func() error { return cmd.Release(os.Args[2:]) })
+
+	case "rollback":
+		runCommand(func() error { return cmd.Rollback(os.Args[2:]) })
+
+	case "backup":
+		runCommand(func() error { return cmd.Backup(os.Args[2:]) })
+
+	case "restore":
+		runCommand(func() error { return cmd.Restore(os.Args[2:]) })
+
 	case "start", "stop", "restart":
 		runCommand(func() error { return cmd.Service(command, os.Args[2:]) })
 
+	case "service":
+		runCommand(func() error { return cmd.ServiceUnit(os.Args[2:]) })
+
 	case "status":
-		service := "all"
-		if len(os.Args) > 2 {
-			service = os.Args[2]
-		}
-		runCommand(func() error { return cmd.Status(service) })
+		runCommand(func() error { return cmd.Status(os.Args[2:]) })
 
 	case "logs":
 		follow := false
@@ -168,6 +200,12 @@ func main() {
 	case "study":
 		runCommand(func() error { return cmd.Study(os.Args[2:]) })
 
+	case "remote":
+		runCommand(func() error { return cmd.Remote(os.Args[2:]) })
+
+	case "metrics":
+		runCommand(func() error { return cmd.Metrics(os.Args[2:]) })
+
 	case "serve": // New serve command
 		runCommand(func() error { return cmd.Serve(os.Args[2:], version, branch, commit, buildDate) })
 
@@ -238,8 +276,11 @@ func runCommand(commandFunc func() error) {
 			})
 		}
 
-		ui.PrintError(fmt.Sprintf("Error: %v", err))
-		fmt.Println() // Add padding at the end
+		var silent *cmd.SilentError
+		if !errors.As(err, &silent) {
+			ui.PrintError(fmt.Sprintf("Error: %v", err))
+			fmt.Println() // Add padding at the end
+		}
 		os.Exit(1)
 	}
 
@@ -272,13 +313,13 @@ func printUsage() {
 
 	ui.PrintSubHeader("CORE LIFECYCLE")
 	ui.PrintKeyValBlock("build", []ui.KeyVal{
-		{Key: "Usage", Value: "dex build [major|minor|patch] [-f|--force]"},
+		{Key: "Usage", Value: "dex build [major|minor|patch] [-f|--force] [-j N] [--plan [--json]]"},
 		{Key: "Desc", Value: "Build and install services from local source."},
 		{Key: "Args", Value: "Increment version: 'patch' (default), 'minor', or 'major'."},
-		{Key: "Flags", Value: "--force: Rebuild all services even without changes."},
+		{Key: "Flags", Value: "--force: Rebuild all services even without changes. -j N: Build up to N services at once. --plan: Show what would happen and stop."},
 	})
 	ui.PrintKeyValBlock("update", []ui.KeyVal{
-		{Key: "Usage", Value: "dex update"},
+		{Key: "Usage", Value: "dex update [--channel stable|beta|dev] [--version X.Y.Z] [--check] [--insecure-skip-verify]"},
 		{Key: "Desc", Value: "Update CLI and services. In DEV mode: Rebuilds from source. In USER mode: Downloads binaries."},
 	})
 	ui.PrintKeyValBlock("test", []ui.KeyVal{
@@ -287,14 +328,37 @@ func printUsage() {
 		{Key: "Args", Value: "[service]: specific service to test. Defaults to all."},
 		{Key: "Flags", Value: "--models: Include comprehensive model tests (slow)."},
 	})
+	ui.PrintKeyValBlock("release", []ui.KeyVal{
+		{Key: "Usage", Value: "dex release <list|show|gc|keygen>"},
+		{Key: "Desc", Value: "Inspect published releases, prune them by retention policy, or rotate the signing key."},
+		{Key: "Flags", Value: "gc --dry-run: Report what would be removed and the space freed."},
+	})
 
 	ui.PrintSubHeader("SERVICE MANAGEMENT")
+	ui.PrintKeyValBlock("rollback", []ui.KeyVal{
+		{Key: "Usage", Value: "dex rollback <service> [version|--list]"},
+		{Key: "Desc", Value: "Restore a previous binary, restart it and confirm it is healthy."},
+	})
+	ui.PrintKeyValBlock("backup", []ui.KeyVal{
+		{Key: "Usage", Value: "dex backup [service|all|list]"},
+		{Key: "Desc", Value: "Archive config, service artifacts and persistent Redis keys."},
+	})
+	ui.PrintKeyValBlock("restore", []ui.KeyVal{
+		{Key: "Usage", Value: "dex restore <archive>"},
+		{Key: "Desc", Value: "Verify a backup, stop affected services, restore and restart them."},
+	})
 	ui.PrintKeyValBlock("start/stop/restart", []ui.KeyVal{
-		{Key: "Usage", Value: "dex [start|stop|restart] <service|all>"},
-		{Key: "Desc", Value: "Manage background systemd services."},
+		{Key: "Usage", Value: "dex [start|stop|restart] <service|all> [--reassign]"},
+		{Key: "Desc", Value: "Manage background systemd services in dependency order."},
+		{Key: "Flags", Value: "--reassign: Move a service whose port is taken to a free port."},
+	})
+	ui.PrintKeyValBlock("service", []ui.KeyVal{
+		{Key: "Usage", Value: "dex service <diff|apply> <service|all>"},
+		{Key: "Desc", Value: "Compare installed systemd units with the generated ones, or regenerate them."},
+		{Key: "Config", Value: "'unit' in service-map.json: environment, memory_max, cpu_quota, nice, restart, sandboxing."},
 	})
 	ui.PrintKeyValBlock("status", []ui.KeyVal{
-		{Key: "Usage", Value: "dex status [service|all]"},
+		{Key: "Usage", Value: "dex status [service|all] [--json|--format table|json|yaml] [--watch] [--all-hosts]"},
 		{Key: "Desc", Value: "Check connectivity and health of services."},
 	})
 	ui.PrintKeyValBlock("logs", []ui.KeyVal{
@@ -312,14 +376,24 @@ func printUsage() {
 		{Key: "", Value: "install [pkg]: Install missing system package(s)."},
 		{Key: "", Value: "upgrade [pkg]: Upgrade installed system package(s)."},
 	})
+	ui.PrintKeyValBlock("verify", []ui.KeyVal{
+		{Key: "Usage", Value: "dex verify [--only <checks>] [--format text|json|junit] [-o <file>]"},
+		{Key: "Desc", Value: "Run infrastructure, service and end-to-end diagnostics with timings."},
+		{Key: "Flags", Value: "--only redis,ollama: Run a subset. --format junit: Report for CI."},
+	})
+	ui.PrintKeyValBlock("doctor", []ui.KeyVal{
+		{Key: "Usage", Value: "dex doctor [--fix] [-y|--yes]"},
+		{Key: "Desc", Value: "Find config, package, venv, unit, log, port and health problems."},
+		{Key: "Flags", Value: "--fix: Offer a fix for each problem. --yes: Apply every fix without asking."},
+	})
 	ui.PrintKeyValBlock("config", []ui.KeyVal{
 		{Key: "Usage", Value: "dex config <service> [field] | reset"},
 		{Key: "Desc", Value: "View or manage service configuration (service-map.json)."},
 		{Key: "Examples", Value: "'dex config event http_port' or 'dex config reset'."},
 	})
 	ui.PrintKeyValBlock("cache", []ui.KeyVal{
-		{Key: "Usage", Value: "dex cache [clear|list]"},
-		{Key: "Desc", Value: "Manage the local CLI cache (GitHub access, etc)."},
+		{Key: "Usage", Value: "dex cache [list|get|del|stats|clear|export|import]"},
+		{Key: "Desc", Value: "Inspect, scope and manage the local Redis cache."},
 	})
 
 	ui.PrintSubHeader("INTELLIGENCE & ANALYSIS")
@@ -368,6 +442,15 @@ func printUsage() {
 		{Key: "Usage", Value: "dex whisper [file]"},
 		{Key: "Desc", Value: "Transcribe audio file using local Whisper model."},
 	})
+	ui.PrintKeyValBlock("remote", []ui.KeyVal{
+		{Key: "Usage", Value: "dex remote <host> <command> [args...] | dex <command> --host <host>"},
+		{Key: "Desc", Value: "Run status, logs, start/stop/restart or update on a server-map.json host."},
+		{Key: "Forward", Value: "dex remote <host> forward [service|port|local:remote]..."},
+	})
+	ui.PrintKeyValBlock("metrics", []ui.KeyVal{
+		{Key: "Usage", Value: "dex metrics serve [--port 9464] [--interval 15s]"},
+		{Key: "Desc", Value: "Expose service health and systemd stats in OpenMetrics format."},
+	})
 	ui.PrintKeyValBlock("serve", []ui.KeyVal{
 		{Key: "Usage", Value: "dex serve -d <dir> -p <port>"},
 		{Key: "Desc", Value: "Serve static files from a directory."},
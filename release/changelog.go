@@ -0,0 +1,215 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/git"
+)
+
+// ReleaseNotesPath is where release notes are written within the easter.company repo
+const ReleaseNotesPath = "source/releases"
+
+// conventionalCommit matches "type(scope)!: description"
+var conventionalCommit = regexp.MustCompile(`^([a-zA-Z]+)(?:\(([^)]*)\))?(!)?:\s*(.+)$`)
+
+// changeGroups are the changelog sections, in display order
+var changeGroups = []struct {
+	Type  string
+	Title string
+}{
+	{"breaking", "Breaking Changes"},
+	{"feat", "Features"},
+	{"fix", "Bug Fixes"},
+	{"perf", "Performance"},
+	{"refactor", "Refactoring"},
+	{"docs", "Documentation"},
+	{"test", "Tests"},
+	{"build", "Build"},
+	{"chore", "Chores"},
+	{"other", "Other Changes"},
+}
+
+// changeTypeAliases folds less common commit types into the groups above
+var changeTypeAliases = map[string]string{
+	"feature": "feat",
+	"bugfix":  "fix",
+	"hotfix":  "fix",
+	"doc":     "docs",
+	"tests":   "test",
+	"ci":      "build",
+	"deps":    "build",
+	"style":   "chore",
+	"update":  "chore",
+	"revert":  "other",
+}
+
+// ChangeGroup is one section of a service changelog, e.g. all "feat" commits
+type ChangeGroup struct {
+	Type    string   `json:"type"`
+	Title   string   `json:"title"`
+	Entries []string `json:"entries"`
+}
+
+// ServiceChangelog describes what changed in one service between two release tags
+type ServiceChangelog struct {
+	From         string        `json:"from,omitempty"` // Previous release tag; empty for a first release
+	To           string        `json:"to"`             // New release tag
+	Summary      string        `json:"summary,omitempty"`
+	Groups       []ChangeGroup `json:"groups,omitempty"`
+	FilesChanged int           `json:"files_changed"`
+	Insertions   int           `json:"insertions"`
+	Deletions    int           `json:"deletions"`
+}
+
+// BuildChangelog collects the commits between two tags of a repository and groups them by
+// conventional-commit type. Commits that do not follow the convention are listed as other changes.
+func BuildChangelog(repoPath, fromTag, toTag string) (*ServiceChangelog, error) {
+	commits, err := git.GetCommitsBetween(repoPath, fromTag, toTag)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := make(map[string][]string)
+	for _, commit := range commits {
+		groupType, entry := classifyCommit(commit.Subject)
+		entries[groupType] = append(entries[groupType], fmt.Sprintf("%s (%s)", entry, commit.Hash))
+	}
+
+	changelog := &ServiceChangelog{From: fromTag, To: toTag}
+	for _, group := range changeGroups {
+		if len(entries[group.Type]) > 0 {
+			changelog.Groups = append(changelog.Groups, ChangeGroup{Type: group.Type, Title: group.Title, Entries: entries[group.Type]})
+		}
+	}
+
+	if fromTag != "" {
+		if stats, err := git.GetDiffSummaryBetween(repoPath, fromTag, toTag); err == nil {
+			changelog.FilesChanged = stats.FilesChanged
+			changelog.Insertions = stats.Insertions
+			changelog.Deletions = stats.Deletions
+		}
+	}
+	return changelog, nil
+}
+
+// classifyCommit returns the changelog group for a commit subject and the entry to list.
+func classifyCommit(subject string) (string, string) {
+	match := conventionalCommit.FindStringSubmatch(subject)
+	if match == nil {
+		return "other", subject
+	}
+	commitType, scope, breaking, description := strings.ToLower(match[1]), match[2], match[3] != "", match[4]
+	if alias, ok := changeTypeAliases[commitType]; ok {
+		commitType = alias
+	}
+	known := false
+	for _, group := range changeGroups {
+		if group.Type == commitType {
+			known = true
+			break
+		}
+	}
+	if !known {
+		commitType = "other"
+	}
+	if breaking {
+		commitType = "breaking"
+	}
+	if scope != "" {
+		description = fmt.Sprintf("**%s:** %s", scope, description)
+	}
+	return commitType, description
+}
+
+// Empty reports whether the changelog lists no commits
+func (c *ServiceChangelog) Empty() bool {
+	return len(c.Groups) == 0
+}
+
+// Plain returns the changelog as plain text, for prompting a summary model
+func (c *ServiceChangelog) Plain() string {
+	var b strings.Builder
+	for _, group := range c.Groups {
+		fmt.Fprintf(&b, "%s:\n", group.Title)
+		for _, entry := range group.Entries {
+			fmt.Fprintf(&b, "- %s\n", strings.ReplaceAll(entry, "**", ""))
+		}
+	}
+	return b.String()
+}
+
+// Markdown renders the changelog as a Markdown section headed by the service name
+func (c *ServiceChangelog) Markdown(service string) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "## %s\n\n", service)
+	if c.From != "" {
+		fmt.Fprintf(&b, "Changes since %s: %d files changed, +%d / -%d lines.\n\n", c.From, c.FilesChanged, c.Insertions, c.Deletions)
+	} else {
+		b.WriteString("First release.\n\n")
+	}
+	if c.Summary != "" {
+		fmt.Fprintf(&b, "%s\n\n", c.Summary)
+	}
+	if c.Empty() {
+		b.WriteString("No changes.\n\n")
+	}
+	for _, group := range c.Groups {
+		fmt.Fprintf(&b, "### %s\n\n", group.Title)
+		for _, entry := range group.Entries {
+			fmt.Fprintf(&b, "- %s\n", entry)
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
+
+// SetChangelog records the per-service changelogs of a release in data.json
+func (rd *ReleaseData) SetChangelog(version string, changelogs map[string]*ServiceChangelog) {
+	info, exists := rd.Releases[version]
+	if !exists || len(changelogs) == 0 {
+		return
+	}
+	info.Changelog = changelogs
+	rd.Releases[version] = info
+}
+
+// WriteReleaseNotes writes a release's changelogs to source/releases/<version>.md in the
+// easter.company repo and returns the file's path.
+func WriteReleaseNotes(repoPath, version string, info ReleaseInfo) (string, error) {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# Dexter %s\n\n", version)
+	fmt.Fprintf(&b, "Released %s (%s release).\n\n", strings.SplitN(info.Date, "T", 2)[0], info.Type)
+	for _, service := range sortedChangelogServices(info.Changelog) {
+		b.WriteString(info.Changelog[service].Markdown(service))
+	}
+
+	dir := filepath.Join(repoPath, ReleaseNotesPath)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", err
+	}
+	path := filepath.Join(dir, version+".md")
+	if err := os.WriteFile(path, []byte(strings.TrimRight(b.String(), "\n")+"\n"), 0644); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// sortedChangelogServices orders services with the CLI first, then alphabetically
+func sortedChangelogServices(changelogs map[string]*ServiceChangelog) []string {
+	services := make([]string, 0, len(changelogs))
+	for service := range changelogs {
+		services = append(services, service)
+	}
+	sort.Slice(services, func(i, j int) bool {
+		if (services[i] == "cli") != (services[j] == "cli") {
+			return services[i] == "cli"
+		}
+		return services[i] < services[j]
+	})
+	return services
+}
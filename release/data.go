@@ -18,11 +18,78 @@ type ReleaseData struct {
 	Services map[string]ServiceInfo `json:"services"`
 }
 
-// LatestVersions tracks current user and dev versions
+// Release channels a user can follow with 'dex update --channel'
+const (
+	ChannelStable = "stable" // Major releases only
+	ChannelBeta   = "beta"   // Major and minor releases
+	ChannelDev    = "dev"    // Every published build
+)
+
+// Channels lists the release channels, most conservative first
+var Channels = []string{ChannelStable, ChannelBeta, ChannelDev}
+
+// LatestVersions tracks the latest version on each release channel
 // Stores FULL version strings including build hash for exact matching
 type LatestVersions struct {
-	User string `json:"user"` // Latest user version (full: 2.1.0.main.abc123.2025-11-27-09-30-45.linux-amd64.xyz789)
-	Dev  string `json:"dev"`  // Latest dev version (full: 2.1.3.main.def456.2025-11-27-10-15-20.linux-amd64.qrs234)
+	User   string `json:"user"`             // Latest user version (full: 2.1.0.main.abc123.2025-11-27-09-30-45.linux-amd64.xyz789)
+	Dev    string `json:"dev"`              // Latest dev version (full: 2.1.3.main.def456.2025-11-27-10-15-20.linux-amd64.qrs234)
+	Stable string `json:"stable,omitempty"` // Latest stable version; older data.json files only have User
+	Beta   string `json:"beta,omitempty"`   // Latest beta version
+}
+
+// IsChannel reports whether name is a known release channel
+func IsChannel(name string) bool {
+	for _, channel := range Channels {
+		if channel == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ForChannel returns the latest full version on a channel.
+// Channels that have never been published fall back to the next more conservative one.
+func (l LatestVersions) ForChannel(channel string) (string, error) {
+	stable := l.Stable
+	if stable == "" {
+		stable = l.User
+	}
+	beta := l.Beta
+	if beta == "" {
+		beta = stable
+	}
+
+	switch channel {
+	case ChannelStable:
+		return stable, nil
+	case ChannelBeta:
+		return beta, nil
+	case ChannelDev:
+		if l.Dev == "" {
+			return beta, nil
+		}
+		return l.Dev, nil
+	default:
+		return "", fmt.Errorf("unknown release channel '%s' (expected %s, %s or %s)", channel, ChannelStable, ChannelBeta, ChannelDev)
+	}
+}
+
+// Promote records a newly published version on the channels a release type belongs to:
+// major releases reach every channel, minor releases beta and dev, anything else dev only.
+// User mirrors stable so older dex binaries keep updating.
+func (l *LatestVersions) Promote(version, releaseType string) {
+	switch releaseType {
+	case "major":
+		l.Stable = version
+		l.User = version
+		l.Beta = version
+	case "minor":
+		if l.Stable == "" {
+			l.Stable = l.User // Keep stable where older data.json files left it
+		}
+		l.Beta = version
+	}
+	l.Dev = version
 }
 
 // ReleaseInfo contains metadata about a specific release
@@ -31,6 +98,8 @@ type ReleaseInfo struct {
 	Date     string                       `json:"date"`     // ISO 8601
 	Commit   string                       `json:"commit"`   // Git commit hash
 	Binaries map[string]map[string]Binary `json:"binaries"` // service -> platform -> binary
+	// Changelog lists what changed in each service since the previous release
+	Changelog map[string]*ServiceChangelog `json:"changelog,omitempty"`
 }
 
 // Binary contains info about a specific binary file
@@ -126,8 +195,8 @@ func (rd *ReleaseData) AddBinary(version, service, platform, binaryPath string)
 		return fmt.Errorf("failed to stat binary: %w", err)
 	}
 
-	// Get relative path from easter.company root
-	relativePath := filepath.Join("/bin", version, filepath.Base(binaryPath))
+	// Get relative path from easter.company root; binaries live in bin/<version>/<platform>/
+	relativePath := filepath.Join("/bin", version, platform, filepath.Base(binaryPath))
 
 	// Initialize maps if needed
 	if release.Binaries[service] == nil {
@@ -144,23 +213,6 @@ func (rd *ReleaseData) AddBinary(version, service, platform, binaryPath string)
 	return nil
 }
 
-// RemoveMinorVersions removes all minor versions from the same major
-func (rd *ReleaseData) RemoveMinorVersions(majorVersion string) []string {
-	var removed []string
-	for version, info := range rd.Releases {
-		// Skip if it's a major version
-		if info.Type == "major" {
-			continue
-		}
-		// Check if it's from the same major version
-		if len(version) >= len(majorVersion) && version[:len(majorVersion)] == majorVersion {
-			delete(rd.Releases, version)
-			removed = append(removed, version)
-		}
-	}
-	return removed
-}
-
 // CalculateChecksum computes SHA256 of a file
 func CalculateChecksum(path string) (string, error) {
 	f, err := os.Open(path)
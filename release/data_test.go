@@ -0,0 +1,47 @@
+package release
+
+import "testing"
+
+func TestPromoteChannels(t *testing.T) {
+	var latest LatestVersions
+	steps := []struct {
+		version, releaseType string
+		stable, beta, dev    string
+	}{
+		{"1.0.0", "major", "1.0.0", "1.0.0", "1.0.0"},
+		{"1.1.0", "minor", "1.0.0", "1.1.0", "1.1.0"},
+		{"1.1.1", "dev", "1.0.0", "1.1.0", "1.1.1"},
+		{"1.2.0", "minor", "1.0.0", "1.2.0", "1.2.0"},
+		{"1.2.1", "dev", "1.0.0", "1.2.0", "1.2.1"},
+		{"2.0.0", "major", "2.0.0", "2.0.0", "2.0.0"},
+	}
+	for _, step := range steps {
+		latest.Promote(step.version, step.releaseType)
+		want := map[string]string{ChannelStable: step.stable, ChannelBeta: step.beta, ChannelDev: step.dev}
+		for _, channel := range Channels {
+			got, err := latest.ForChannel(channel)
+			if err != nil {
+				t.Fatalf("ForChannel(%q) error = %v", channel, err)
+			}
+			if got != want[channel] {
+				t.Errorf("after %s %s: %s = %q, want %q", step.releaseType, step.version, channel, got, want[channel])
+			}
+		}
+	}
+}
+
+func TestForChannelOlderDataJSON(t *testing.T) {
+	// data.json files from before channels only have user and dev
+	latest := LatestVersions{User: "1.0.0"}
+	for _, channel := range Channels {
+		if got, _ := latest.ForChannel(channel); got != "1.0.0" {
+			t.Errorf("%s = %q, want the user version", channel, got)
+		}
+	}
+	if _, err := latest.ForChannel("major"); err == nil {
+		t.Error("ForChannel(\"major\") error = nil, want an unknown channel")
+	}
+	if !IsChannel(ChannelBeta) {
+		t.Error("beta is not an accepted channel")
+	}
+}
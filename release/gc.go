@@ -0,0 +1,133 @@
+package release
+
+import (
+	"crypto/ed25519"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/ui"
+)
+
+// releaseDirPattern matches the bin/<version> directories releases are published into
+var releaseDirPattern = regexp.MustCompile(`^\d+\.\d+\.\d+$`)
+
+// GCReport describes what a garbage collection removed, or would remove on a dry run
+type GCReport struct {
+	Policy RetentionPolicy
+	// Expired are releases in data.json the policy no longer keeps, newest first
+	Expired []string
+	// Orphans are release directories in bin/ that data.json does not list
+	Orphans []string
+	// Freed is the size of every removed directory in bytes
+	Freed int64
+}
+
+// CollectGarbage applies a retention policy to the published releases: expired releases are
+// removed from data.json and their binaries and release notes deleted, along with any release
+// directories data.json no longer mentions. data.json is re-signed and the result committed and pushed.
+// With dryRun set nothing is changed and the report says what would be removed.
+func CollectGarbage(policy RetentionPolicy, dryRun bool) (*GCReport, error) {
+	repoPath, err := EasterCompanyRepoPath()
+	if err != nil {
+		return nil, err
+	}
+
+	dataPath := filepath.Join(repoPath, DataJSONPath)
+	data, err := LoadReleaseData(dataPath)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load data.json: %w", err)
+	}
+
+	report := &GCReport{Policy: policy, Expired: data.Expired(policy)}
+	for _, version := range report.Expired {
+		report.Freed += dirSize(filepath.Join(repoPath, BinPath, version))
+	}
+
+	entries, err := os.ReadDir(filepath.Join(repoPath, BinPath))
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read bin directory: %w", err)
+	}
+	for _, entry := range entries {
+		if !entry.IsDir() || !releaseDirPattern.MatchString(entry.Name()) {
+			continue
+		}
+		if _, listed := data.Releases[entry.Name()]; !listed {
+			report.Orphans = append(report.Orphans, entry.Name())
+			report.Freed += dirSize(filepath.Join(repoPath, BinPath, entry.Name()))
+		}
+	}
+
+	if dryRun || (len(report.Expired) == 0 && len(report.Orphans) == 0) {
+		return report, nil
+	}
+
+	var signingKey ed25519.PrivateKey
+	if len(report.Expired) > 0 {
+		// data.json changes, so it must be re-signed; fail before deleting anything
+		if signingKey, err = LoadSigningKey(); err != nil {
+			return nil, err
+		}
+	}
+
+	removed := append(append([]string{}, report.Expired...), report.Orphans...)
+	removeReleaseFiles(repoPath, removed)
+	if len(report.Expired) > 0 {
+		data.RemoveReleases(report.Expired)
+		if err := data.Save(dataPath); err != nil {
+			return nil, fmt.Errorf("failed to save data.json: %w", err)
+		}
+		if err := SignFile(dataPath, signingKey); err != nil {
+			return nil, fmt.Errorf("failed to sign data.json: %w", err)
+		}
+	}
+
+	message := fmt.Sprintf("release: garbage collect %s", strings.Join(removed, ", "))
+	if err := commitAndPush(repoPath, message); err != nil {
+		return report, fmt.Errorf("failed to commit and push: %w", err)
+	}
+	return report, nil
+}
+
+// removeReleaseFiles deletes the bin/<version> directories and release notes of releases.
+func removeReleaseFiles(repoPath string, versions []string) {
+	for _, version := range versions {
+		dir := filepath.Join(repoPath, BinPath, version)
+		if err := os.RemoveAll(dir); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to remove %s: %v", dir, err))
+		}
+		notes := filepath.Join(repoPath, ReleaseNotesPath, version+".md")
+		if err := os.Remove(notes); err != nil && !os.IsNotExist(err) {
+			ui.PrintWarning(fmt.Sprintf("Failed to remove %s: %v", notes, err))
+		}
+	}
+}
+
+// dirSize returns the total size of the files under a directory.
+func dirSize(dir string) int64 {
+	var size int64
+	_ = filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if info, err := d.Info(); err == nil && info.Mode().IsRegular() {
+			size += info.Size()
+		}
+		return nil
+	})
+	return size
+}
+
+// Size returns the total size of a release's binaries as listed in data.json.
+func (info ReleaseInfo) Size() int64 {
+	var size int64
+	for _, platforms := range info.Binaries {
+		for _, binary := range platforms {
+			size += binary.Size
+		}
+	}
+	return size
+}
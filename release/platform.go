@@ -0,0 +1,72 @@
+package release
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+// DefaultPlatforms are built and published when options.json does not list any
+var DefaultPlatforms = []string{"linux-amd64", "linux-arm64"}
+
+// Platform is a GOOS/GOARCH pair, written "linux-amd64" in data.json and options.json
+type Platform struct {
+	OS   string
+	Arch string
+}
+
+// String returns the platform's key in data.json
+func (p Platform) String() string {
+	return p.OS + "-" + p.Arch
+}
+
+// ParsePlatform parses "linux-arm64" (or "linux/arm64") into a Platform
+func ParsePlatform(s string) (Platform, error) {
+	s = strings.ReplaceAll(strings.TrimSpace(s), "/", "-")
+	goos, goarch, ok := strings.Cut(s, "-")
+	if !ok || goos == "" || goarch == "" {
+		return Platform{}, fmt.Errorf("invalid platform '%s' (expected GOOS-GOARCH, e.g. linux-arm64)", s)
+	}
+	return Platform{OS: goos, Arch: goarch}, nil
+}
+
+// HostPlatform returns the platform this binary was built for
+func HostPlatform() Platform {
+	return Platform{OS: runtime.GOOS, Arch: runtime.GOARCH}
+}
+
+// BuildPlatforms returns the platforms 'dex build' compiles Go services for, from the
+// build.platforms list in options.json. The host platform is always included, first.
+func BuildPlatforms() ([]Platform, error) {
+	names := DefaultPlatforms
+	if options, err := config.LoadOptionsConfig(); err == nil && len(options.Build.Platforms) > 0 {
+		names = options.Build.Platforms
+	}
+
+	host := HostPlatform()
+	platforms := []Platform{host}
+	for _, name := range names {
+		platform, err := ParsePlatform(name)
+		if err != nil {
+			return nil, fmt.Errorf("options.json build.platforms: %w", err)
+		}
+		if platform != host {
+			platforms = append(platforms, platform)
+		}
+	}
+	return platforms, nil
+}
+
+// PlatformBinaryPath returns where 'dex build' leaves a binary for a platform. Host builds are
+// installed into ~/Dexter/bin; cross-compiled builds go to ~/Dexter/build/<platform> so they are
+// never run by accident.
+func PlatformBinaryPath(platform Platform, binaryName string) string {
+	if platform == HostPlatform() {
+		return filepath.Join(os.Getenv("HOME"), "Dexter", "bin", binaryName)
+	}
+	return filepath.Join(os.Getenv("HOME"), "Dexter", "build", platform.String(), binaryName)
+}
@@ -1,6 +1,7 @@
 package release
 
 import (
+	"crypto/ed25519"
 	"fmt"
 	"os"
 	"os/exec"
@@ -18,9 +19,22 @@ const (
 	BinPath           = "bin"
 )
 
+// EasterCompanyRepoPath returns the local easter.company checkout that releases are published from
+func EasterCompanyRepoPath() (string, error) {
+	repoPath, err := config.ExpandPath(EasterCompanyRepo)
+	if err != nil {
+		return "", fmt.Errorf("failed to expand easter.company path: %w", err)
+	}
+	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
+		return "", fmt.Errorf("easter.company repo not found at %s - run: git clone git@github.com:eastercompany/eastercompany.github.io.git ~/EasterCompany/easter.company", repoPath)
+	}
+	return repoPath, nil
+}
+
 // PublishRelease publishes binaries to easter.company for major/minor releases
 // version is the FULL version string (e.g., 2.1.0.main.abc123.2025-11-27-09-30-45.linux-amd64.xyz789)
-func PublishRelease(fullVersion, shortVersion, releaseType string, services []config.ServiceDefinition) error {
+// changelogs, keyed by service short name, are recorded in data.json and written as release notes.
+func PublishRelease(fullVersion, shortVersion, releaseType string, services []config.ServiceDefinition, changelogs map[string]*ServiceChangelog) error {
 	// 1. SKIP PATCH RELEASES
 	// Patch releases are source-only and not published to the public bin directory to save space/bandwidth.
 	if releaseType == "patch" {
@@ -28,15 +42,23 @@ func PublishRelease(fullVersion, shortVersion, releaseType string, services []co
 		return nil
 	}
 
-	// Expand easter.company path
-	repoPath, err := config.ExpandPath(EasterCompanyRepo)
+	repoPath, err := EasterCompanyRepoPath()
 	if err != nil {
-		return fmt.Errorf("failed to expand easter.company path: %w", err)
+		return err
 	}
 
-	// Verify repo exists
-	if _, err := os.Stat(repoPath); os.IsNotExist(err) {
-		return fmt.Errorf("easter.company repo not found at %s - run: git clone git@github.com:eastercompany/eastercompany.github.io.git ~/EasterCompany/easter.company", repoPath)
+	// data.json is signed so 'dex update' can prove it came from us; fail before touching anything
+	signingKey, err := LoadSigningKey()
+	if err != nil {
+		return err
+	}
+	// A data.json signed by a key no dex trusts could not be installed by anyone
+	publicKey := signingKey.Public().(ed25519.PublicKey)
+	if !IsTrusted(publicKey) {
+		return fmt.Errorf("the release signing key is not in this dex build's trusted_keys.txt, so installed CLIs would reject the release; add it to %s, rebuild dex and publish again", TrustedKeysSource)
+	}
+	if sourceKeys, err := SourceTrustedKeys(); err == nil && !containsKey(sourceKeys, publicKey) {
+		return fmt.Errorf("the release signing key is missing from %s, so the CLI being released would reject it", TrustedKeysSource)
 	}
 
 	ui.PrintInfo(fmt.Sprintf("Publishing %s release %s to easter.company...", releaseType, shortVersion))
@@ -51,28 +73,18 @@ func PublishRelease(fullVersion, shortVersion, releaseType string, services []co
 	// Get current git commit
 	_, commit := git.GetVersionInfo(repoPath)
 
-	// Handle version cleanup based on type
-	if releaseType == "minor" {
-		// Extract major version (e.g., "2" from "2.1.0")
-		majorVersion := strings.Split(shortVersion, ".")[0]
-
-		// Remove all other minors from this major
-		removed := data.RemoveMinorVersions(majorVersion)
-		if len(removed) > 0 {
-			ui.PrintInfo(fmt.Sprintf("Removing previous minor versions: %v", removed))
-
-			// Delete the binary directories
-			for _, oldVer := range removed {
-				oldDir := filepath.Join(repoPath, BinPath, oldVer)
-				if err := os.RemoveAll(oldDir); err != nil {
-					ui.PrintWarning(fmt.Sprintf("Failed to remove %s: %v", oldDir, err))
-				}
-			}
-		}
-	}
-
 	// Add the new release (use short version as key, but store full version in data)
 	data.AddRelease(shortVersion, releaseType, commit)
+	data.SetChangelog(shortVersion, changelogs)
+	if len(changelogs) > 0 {
+		notesPath, err := WriteReleaseNotes(repoPath, shortVersion, data.Releases[shortVersion])
+		if err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to write release notes: %v", err))
+		} else {
+			relative, _ := filepath.Rel(repoPath, notesPath)
+			ui.PrintSuccess(fmt.Sprintf("Wrote release notes to %s", relative))
+		}
+	}
 
 	// Create version directory (use short version for directory name)
 	versionDir := filepath.Join(repoPath, BinPath, shortVersion)
@@ -80,25 +92,46 @@ func PublishRelease(fullVersion, shortVersion, releaseType string, services []co
 		return fmt.Errorf("failed to create version directory: %w", err)
 	}
 
+	platforms, err := BuildPlatforms()
+	if err != nil {
+		return err
+	}
+
 	// Copy binaries and update data
-	platform := "linux-amd64" // We only support linux-amd64
 	for _, service := range services {
-		binPath := filepath.Join(os.Getenv("HOME"), "Dexter", "bin", getBinaryName(service))
+		binaryName := getBinaryName(service)
+		published := 0
+		for _, platform := range platforms {
+			binPath := PlatformBinaryPath(platform, binaryName)
+			if _, err := os.Stat(binPath); os.IsNotExist(err) {
+				ui.PrintWarning(fmt.Sprintf("Binary not found: %s", binPath))
+				continue
+			}
 
-		if _, err := os.Stat(binPath); os.IsNotExist(err) {
-			ui.PrintWarning(fmt.Sprintf("Binary not found: %s", binPath))
-			continue
-		}
+			// Copy binary to the platform's directory within the version directory
+			platformDir := filepath.Join(versionDir, platform.String())
+			if err := os.MkdirAll(platformDir, 0755); err != nil {
+				return fmt.Errorf("failed to create platform directory: %w", err)
+			}
+			destPath := filepath.Join(platformDir, binaryName)
+			if err := copyFile(binPath, destPath); err != nil {
+				return fmt.Errorf("failed to copy %s: %w", binPath, err)
+			}
 
-		// Copy binary to version directory
-		destPath := filepath.Join(versionDir, filepath.Base(binPath))
-		if err := copyFile(binPath, destPath); err != nil {
-			return fmt.Errorf("failed to copy %s: %w", binPath, err)
-		}
+			// Add to data.json (using short version as key)
+			if err := data.AddBinary(shortVersion, service.ShortName, platform.String(), destPath); err != nil {
+				return fmt.Errorf("failed to add binary to data: %w", err)
+			}
+			published++
 
-		// Add to data.json (using short version as key)
-		if err := data.AddBinary(shortVersion, service.ShortName, platform, destPath); err != nil {
-			return fmt.Errorf("failed to add binary to data: %w", err)
+			// Special case for CLI: Update latest binaries for the install script.
+			// bin/latest/dex stays linux-amd64 for install scripts that predate other platforms.
+			if service.ShortName == "cli" {
+				publishLatestCLI(repoPath, platform, binPath)
+			}
+		}
+		if published == 0 {
+			continue
 		}
 
 		// Get full version string for this service
@@ -108,22 +141,7 @@ func PublishRelease(fullVersion, shortVersion, releaseType string, services []co
 		repo := fmt.Sprintf("github.com/EasterCompany/%s", service.ID)
 		data.UpdateService(service.ShortName, serviceFullVersion, serviceFullVersion, repo)
 
-		ui.PrintSuccess(fmt.Sprintf("Published %s", filepath.Base(binPath)))
-
-		// Special case for CLI: Update latest binary for install script
-		if service.ShortName == "cli" {
-			latestDir := filepath.Join(repoPath, BinPath, "latest")
-			if err := os.MkdirAll(latestDir, 0755); err != nil {
-				ui.PrintWarning(fmt.Sprintf("Failed to create latest directory: %v", err))
-			} else {
-				latestPath := filepath.Join(latestDir, "dex")
-				if err := copyFile(binPath, latestPath); err != nil {
-					ui.PrintWarning(fmt.Sprintf("Failed to update latest binary: %v", err))
-				} else {
-					ui.PrintSuccess("Updated bin/latest/dex")
-				}
-			}
-		}
+		ui.PrintSuccess(fmt.Sprintf("Published %s (%d platforms)", binaryName, published))
 	}
 
 	// Update latest versions (store full version strings)
@@ -135,8 +153,15 @@ func PublishRelease(fullVersion, shortVersion, releaseType string, services []co
 			break
 		}
 	}
-	data.Latest.User = cliFullVersion
-	data.Latest.Dev = cliFullVersion
+	data.Latest.Promote(cliFullVersion, releaseType)
+
+	// Prune releases the retention policy no longer keeps, now the channels point at this one
+	policy := LoadRetentionPolicy()
+	if expired := data.Expired(policy); len(expired) > 0 {
+		ui.PrintInfo(fmt.Sprintf("Removing releases outside the retention policy (%s): %v", policy, expired))
+		removeReleaseFiles(repoPath, expired)
+		data.RemoveReleases(expired)
+	}
 
 	// Save data.json
 	if err := data.Save(dataPath); err != nil {
@@ -145,14 +170,40 @@ func PublishRelease(fullVersion, shortVersion, releaseType string, services []co
 
 	ui.PrintSuccess(fmt.Sprintf("Updated %s", DataJSONPath))
 
+	if err := SignFile(dataPath, signingKey); err != nil {
+		return fmt.Errorf("failed to sign data.json: %w", err)
+	}
+	ui.PrintSuccess(fmt.Sprintf("Signed %s%s", DataJSONPath, SignatureSuffix))
+
 	// Git commit and push
-	if err := commitAndPush(repoPath, shortVersion, releaseType); err != nil {
+	if err := commitAndPush(repoPath, fmt.Sprintf("release: publish %s version %s", releaseType, shortVersion)); err != nil {
 		return fmt.Errorf("failed to commit and push: %w", err)
 	}
 
 	return nil
 }
 
+// publishLatestCLI copies a CLI build to bin/latest/<platform>/dex, and linux-amd64 builds
+// also to bin/latest/dex.
+func publishLatestCLI(repoPath string, platform Platform, binPath string) {
+	targets := []string{filepath.Join(repoPath, BinPath, "latest", platform.String(), "dex")}
+	if platform.String() == "linux-amd64" {
+		targets = append(targets, filepath.Join(repoPath, BinPath, "latest", "dex"))
+	}
+	for _, latestPath := range targets {
+		if err := os.MkdirAll(filepath.Dir(latestPath), 0755); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to create latest directory: %v", err))
+			continue
+		}
+		if err := copyFile(binPath, latestPath); err != nil {
+			ui.PrintWarning(fmt.Sprintf("Failed to update latest binary: %v", err))
+			continue
+		}
+		relative, _ := filepath.Rel(repoPath, latestPath)
+		ui.PrintSuccess(fmt.Sprintf("Updated %s", relative))
+	}
+}
+
 // getBinaryName returns the binary name for a service
 func getBinaryName(service config.ServiceDefinition) string {
 	if service.ShortName == "cli" {
@@ -196,7 +247,7 @@ func getServiceFullVersion(service config.ServiceDefinition) string {
 }
 
 // commitAndPush commits and pushes changes to easter.company repo
-func commitAndPush(repoPath, version, releaseType string) error {
+func commitAndPush(repoPath, commitMsg string) error {
 	ui.PrintInfo("Committing and pushing to easter.company...")
 
 	// Git add
@@ -222,7 +273,6 @@ func commitAndPush(repoPath, version, releaseType string) error {
 	}
 
 	// Commit
-	commitMsg := fmt.Sprintf("release: publish %s version %s", releaseType, version)
 	commitCmd := exec.Command("git", "commit", "-m", commitMsg)
 	commitCmd.Dir = repoPath
 	commitCmd.Stdout = os.Stdout
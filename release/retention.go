@@ -0,0 +1,119 @@
+package release
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/git"
+)
+
+// RetentionPolicy decides which published releases are kept in data.json and the bin directory
+type RetentionPolicy struct {
+	// KeepMinors is how many minor releases are kept for each major version
+	KeepMinors int
+	// KeepMajors is how many major releases are kept; 0 keeps every major
+	KeepMajors int
+}
+
+// DefaultRetentionPolicy keeps every major and the newest minor of each major
+var DefaultRetentionPolicy = RetentionPolicy{KeepMinors: 1, KeepMajors: 0}
+
+// LoadRetentionPolicy reads release.retention from options.json, falling back to the default
+// for anything it does not set.
+func LoadRetentionPolicy() RetentionPolicy {
+	policy := DefaultRetentionPolicy
+	options, err := config.LoadOptionsConfig()
+	if err != nil {
+		return policy
+	}
+	retention := options.Release.Retention
+	if retention.KeepMinors != nil {
+		policy.KeepMinors = max(*retention.KeepMinors, 0)
+	}
+	if retention.KeepMajors != nil {
+		policy.KeepMajors = max(*retention.KeepMajors, 0)
+	}
+	return policy
+}
+
+// String describes the policy for display
+func (p RetentionPolicy) String() string {
+	majors := "every major"
+	if p.KeepMajors > 0 {
+		majors = fmt.Sprintf("the last %d majors", p.KeepMajors)
+	}
+	return fmt.Sprintf("%s, plus the last %d minors of each major", majors, p.KeepMinors)
+}
+
+// SortedVersions lists the releases in data.json, newest first
+func (rd *ReleaseData) SortedVersions() []string {
+	versions := make([]string, 0, len(rd.Releases))
+	for version := range rd.Releases {
+		versions = append(versions, version)
+	}
+	sort.Slice(versions, func(i, j int) bool {
+		vi, erri := git.Parse(versions[i])
+		vj, errj := git.Parse(versions[j])
+		if erri != nil || errj != nil {
+			return versions[i] > versions[j]
+		}
+		return vi.Compare(vj) > 0
+	})
+	return versions
+}
+
+// ChannelsFor returns the release channels whose latest version is the given short version
+func (rd *ReleaseData) ChannelsFor(version string) []string {
+	var channels []string
+	for _, channel := range Channels {
+		latest, err := rd.Latest.ForChannel(channel)
+		if err == nil && latest != "" && shortVersion(latest) == version {
+			channels = append(channels, channel)
+		}
+	}
+	return channels
+}
+
+// Expired returns the releases the policy no longer keeps, newest first. Releases that are the
+// latest on any channel are always kept, whatever the policy says, and so are releases whose
+// version cannot be parsed, since the policy cannot place them.
+func (rd *ReleaseData) Expired(policy RetentionPolicy) []string {
+	majorsSeen := 0
+	minorsSeen := make(map[int]int)
+
+	var expired []string
+	for _, version := range rd.SortedVersions() {
+		major, _, _, err := git.ParseVersionTag(version)
+		if err != nil {
+			continue
+		}
+		keep := false
+		if rd.Releases[version].Type == "major" {
+			majorsSeen++
+			keep = policy.KeepMajors == 0 || majorsSeen <= policy.KeepMajors
+		} else {
+			minorsSeen[major]++
+			keep = minorsSeen[major] <= policy.KeepMinors
+		}
+		if !keep && len(rd.ChannelsFor(version)) == 0 {
+			expired = append(expired, version)
+		}
+	}
+	return expired
+}
+
+// RemoveReleases deletes releases from data.json
+func (rd *ReleaseData) RemoveReleases(versions []string) {
+	for _, version := range versions {
+		delete(rd.Releases, version)
+	}
+}
+
+// shortVersion returns the MAJOR.MINOR.PATCH part of a full version string
+func shortVersion(fullVersion string) string {
+	if v, err := git.Parse(fullVersion); err == nil {
+		return v.Short()
+	}
+	return fullVersion
+}
@@ -0,0 +1,92 @@
+package release
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestExpired(t *testing.T) {
+	releases := func(types map[string]string) map[string]ReleaseInfo {
+		infos := make(map[string]ReleaseInfo, len(types))
+		for version, releaseType := range types {
+			infos[version] = ReleaseInfo{Type: releaseType}
+		}
+		return infos
+	}
+	history := map[string]string{
+		"2.0.0": "major", "2.1.0": "minor", "2.2.0": "minor",
+		"3.0.0": "major", "3.1.0": "minor", "3.2.0": "minor",
+	}
+	latest := LatestVersions{
+		User:   "3.0.0.main.def5678.2025-12-01-00-00-00.linux-amd64",
+		Stable: "3.0.0.main.def5678.2025-12-01-00-00-00.linux-amd64",
+		Beta:   "3.2.0.main.abc1234.2026-01-02-03-04-05.linux-amd64",
+		Dev:    "3.2.0.main.abc1234.2026-01-02-03-04-05.linux-amd64",
+	}
+
+	tests := []struct {
+		name     string
+		releases map[string]string
+		latest   LatestVersions
+		policy   RetentionPolicy
+		want     []string
+	}{
+		{
+			name:     "default keeps every major and the newest minor of each",
+			releases: history,
+			latest:   latest,
+			policy:   DefaultRetentionPolicy,
+			want:     []string{"3.1.0", "2.1.0"},
+		},
+		{
+			name:     "more minors per major",
+			releases: history,
+			latest:   latest,
+			policy:   RetentionPolicy{KeepMinors: 2},
+			want:     nil,
+		},
+		{
+			name:     "channel releases are kept when the policy drops them",
+			releases: history,
+			latest:   latest,
+			policy:   RetentionPolicy{KeepMinors: 0, KeepMajors: 1},
+			want:     []string{"3.1.0", "2.2.0", "2.1.0", "2.0.0"},
+		},
+		{
+			name:     "keep majors limits majors but not the minors of older majors",
+			releases: history,
+			latest:   latest,
+			policy:   RetentionPolicy{KeepMinors: 1, KeepMajors: 1},
+			want:     []string{"3.1.0", "2.1.0", "2.0.0"},
+		},
+		{
+			name:     "a pinned major outside the limit is kept",
+			releases: history,
+			latest:   LatestVersions{Beta: latest.Beta, Stable: "2.0.0.main.0123456.2025-01-01-00-00-00.linux-amd64"},
+			policy:   RetentionPolicy{KeepMinors: 1, KeepMajors: 1},
+			want:     []string{"3.1.0", "2.1.0"},
+		},
+		{
+			name:     "unparsable versions are never expired",
+			releases: map[string]string{"3.2.0": "minor", "3.1.0": "minor", "3.x.0": "minor", "nightly": "minor"},
+			latest:   LatestVersions{User: "3.2.0"},
+			policy:   RetentionPolicy{KeepMinors: 0},
+			want:     []string{"3.1.0"},
+		},
+		{
+			name:     "nothing published",
+			releases: map[string]string{},
+			policy:   DefaultRetentionPolicy,
+			want:     nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data := &ReleaseData{Latest: tt.latest, Releases: releases(tt.releases)}
+			if got := data.Expired(tt.policy); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Expired(%+v) = %v, want %v", tt.policy, got, tt.want)
+			}
+		})
+	}
+}
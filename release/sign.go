@@ -0,0 +1,214 @@
+package release
+
+import (
+	"bufio"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	_ "embed"
+	"encoding/base64"
+	"encoding/pem"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+const (
+	// SignatureSuffix is appended to a file's name for its detached signature
+	SignatureSuffix = ".sig"
+	// SigningKeyPath is where the release signing key is kept; DEX_RELEASE_KEY overrides it
+	SigningKeyPath = "~/Dexter/config/release-signing.key"
+	// TrustedKeysSource is the file embedded into the CLI as its list of trusted public keys
+	TrustedKeysSource = "~/EasterCompany/dex-cli/release/trusted_keys.txt"
+)
+
+// trustedKeysFile lists the base64 ed25519 public keys allowed to sign data.json, one per line.
+// Several keys may be listed while a rotation is in progress.
+//
+//go:embed trusted_keys.txt
+var trustedKeysFile string
+
+// TrustedKeys returns the public keys built into this CLI.
+func TrustedKeys() ([]ed25519.PublicKey, error) {
+	return parseTrustedKeys(trustedKeysFile)
+}
+
+// SourceTrustedKeys returns the public keys in the trusted_keys.txt of the dex-cli checkout,
+// which the next build will embed.
+func SourceTrustedKeys() ([]ed25519.PublicKey, error) {
+	path, err := config.ExpandPath(TrustedKeysSource)
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return parseTrustedKeys(string(data))
+}
+
+// parseTrustedKeys reads a trusted_keys.txt file.
+func parseTrustedKeys(text string) ([]ed25519.PublicKey, error) {
+	var keys []ed25519.PublicKey
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		key, err := ParsePublicKey(strings.Fields(line)[0])
+		if err != nil {
+			return nil, fmt.Errorf("invalid built-in release key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+	return keys, nil
+}
+
+// ParsePublicKey decodes a base64 ed25519 public key.
+func ParsePublicKey(encoded string) (ed25519.PublicKey, error) {
+	raw, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, err
+	}
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("expected %d bytes, got %d", ed25519.PublicKeySize, len(raw))
+	}
+	return ed25519.PublicKey(raw), nil
+}
+
+// EncodePublicKey encodes a public key in the form used by trusted_keys.txt.
+func EncodePublicKey(key ed25519.PublicKey) string {
+	return base64.StdEncoding.EncodeToString(key)
+}
+
+// signingKeyPath returns the expanded location of the signing key.
+func signingKeyPath() (string, error) {
+	if path := os.Getenv("DEX_RELEASE_KEY"); path != "" {
+		return config.ExpandPath(path)
+	}
+	return config.ExpandPath(SigningKeyPath)
+}
+
+// LoadSigningKey reads the release signing key.
+func LoadSigningKey() (ed25519.PrivateKey, error) {
+	path, err := signingKeyPath()
+	if err != nil {
+		return nil, err
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no release signing key at %s - run 'dex release keygen' or set DEX_RELEASE_KEY", path)
+		}
+		return nil, fmt.Errorf("failed to read release signing key: %w", err)
+	}
+
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "PRIVATE KEY" {
+		return nil, fmt.Errorf("%s is not a PEM private key", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse release signing key: %w", err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an ed25519 key", path)
+	}
+	return key, nil
+}
+
+// GenerateSigningKey creates a new signing key. An existing key is moved aside with a
+// timestamp suffix rather than overwritten, so releases it signed can still be checked.
+// It returns the new public key and the path the previous key was moved to, if any.
+func GenerateSigningKey(suffix string) (ed25519.PublicKey, string, error) {
+	path, err := signingKeyPath()
+	if err != nil {
+		return nil, "", err
+	}
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, "", err
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(privateKey)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, "", err
+	}
+	archived := ""
+	if _, err := os.Stat(path); err == nil {
+		archived = path + "." + suffix
+		if err := os.Rename(path, archived); err != nil {
+			return nil, "", fmt.Errorf("failed to move the previous key aside: %w", err)
+		}
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return nil, archived, err
+	}
+	defer func() { _ = file.Close() }()
+	if err := pem.Encode(file, &pem.Block{Type: "PRIVATE KEY", Bytes: der}); err != nil {
+		return nil, archived, err
+	}
+	return publicKey, archived, nil
+}
+
+// SignFile writes a detached base64 ed25519 signature of path to path + SignatureSuffix.
+func SignFile(path string, key ed25519.PrivateKey) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	signature := base64.StdEncoding.EncodeToString(ed25519.Sign(key, data))
+	return os.WriteFile(path+SignatureSuffix, []byte(signature+"\n"), 0644)
+}
+
+// VerifySignature checks a detached signature against the keys built into the CLI.
+func VerifySignature(data, signature []byte) error {
+	keys, err := TrustedKeys()
+	if err != nil {
+		return err
+	}
+	if len(keys) == 0 {
+		return fmt.Errorf("this dex build has no trusted release keys, so releases cannot be verified")
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil || len(raw) != ed25519.SignatureSize {
+		return fmt.Errorf("malformed release signature")
+	}
+	for _, key := range keys {
+		if ed25519.Verify(key, data, raw) {
+			return nil
+		}
+	}
+	return fmt.Errorf("release signature does not match any trusted key")
+}
+
+// IsTrusted reports whether a public key is built into this CLI.
+func IsTrusted(key ed25519.PublicKey) bool {
+	keys, err := TrustedKeys()
+	if err != nil {
+		return false
+	}
+	return containsKey(keys, key)
+}
+
+// containsKey reports whether key is one of keys.
+func containsKey(keys []ed25519.PublicKey, key ed25519.PublicKey) bool {
+	for _, trusted := range keys {
+		if trusted.Equal(key) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,15 @@
+package release
+
+import "testing"
+
+// TestTrustedKeys guards against shipping a build that can verify nothing: 'dex update'
+// refuses unsigned data.json, so a build without keys could never update.
+func TestTrustedKeys(t *testing.T) {
+	keys, err := TrustedKeys()
+	if err != nil {
+		t.Fatalf("TrustedKeys() error = %v", err)
+	}
+	if len(keys) == 0 {
+		t.Fatal("trusted_keys.txt lists no keys")
+	}
+}
@@ -0,0 +1,249 @@
+// Package remote is dex's SSH client for the servers listed in server-map.json.
+package remote
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/agent"
+)
+
+const (
+	// sshPort is the port every server-map.json server listens for SSH on
+	sshPort = "22"
+	// dialTimeout bounds each address attempt
+	dialTimeout = 10 * time.Second
+	// keepAliveInterval keeps long-lived sessions (logs -f, forwards) from being dropped by NAT
+	keepAliveInterval = 30 * time.Second
+)
+
+// Client is an authenticated SSH connection to a server.
+type Client struct {
+	// Name is the server's key in server-map.json
+	Name string
+	// Address is the IP the connection was established on
+	Address string
+	conn    *ssh.Client
+	done    chan struct{}
+}
+
+// Options customise how a connection is established.
+type Options struct {
+	// OnPin is called when a previously unseen host key is pinned
+	OnPin func(address, fingerprint string)
+}
+
+// Addresses returns the server's addresses in the order they are tried:
+// public IPv4, private IPv4 and finally public IPv6.
+func Addresses(server config.Server) []string {
+	var addresses []string
+	for _, address := range []string{server.PublicIPV4, server.PrivateIPV4, server.PublicIPV6} {
+		if address != "" {
+			addresses = append(addresses, address)
+		}
+	}
+	return addresses
+}
+
+// Dial connects to a server, trying each of its addresses in turn.
+func Dial(ctx context.Context, name string, server config.Server, opts Options) (*Client, error) {
+	if server.User == "" {
+		return nil, fmt.Errorf("host '%s' has no user in server-map.json", name)
+	}
+	addresses := Addresses(server)
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("host '%s' has no address in server-map.json", name)
+	}
+
+	auth, err := authMethods(server.Key)
+	if err != nil {
+		return nil, err
+	}
+	hostKeyCallback, err := pinningCallback(opts.OnPin)
+	if err != nil {
+		return nil, err
+	}
+
+	var failures []string
+	for _, address := range addresses {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		sshConfig := &ssh.ClientConfig{
+			User:              server.User,
+			Auth:              auth,
+			HostKeyCallback:   hostKeyCallback,
+			HostKeyAlgorithms: pinnedAlgorithms(net.JoinHostPort(address, sshPort)),
+			Timeout:           dialTimeout,
+		}
+		conn, err := dialContext(ctx, net.JoinHostPort(address, sshPort), sshConfig)
+		if err != nil {
+			var mismatch *HostKeyMismatchError
+			if errors.As(err, &mismatch) {
+				// Never fall back to another address when a key has changed
+				return nil, err
+			}
+			failures = append(failures, fmt.Sprintf("%s: %v", address, err))
+			continue
+		}
+
+		client := &Client{Name: name, Address: address, conn: conn, done: make(chan struct{})}
+		go client.keepAlive()
+		return client, nil
+	}
+	return nil, fmt.Errorf("failed to connect to %s:\n  %s", name, strings.Join(failures, "\n  "))
+}
+
+// dialContext performs the TCP dial and SSH handshake, abandoning both if ctx ends.
+func dialContext(ctx context.Context, address string, sshConfig *ssh.ClientConfig) (*ssh.Client, error) {
+	dialer := net.Dialer{Timeout: dialTimeout}
+	netConn, err := dialer.DialContext(ctx, "tcp", address)
+	if err != nil {
+		return nil, err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = netConn.SetDeadline(deadline)
+	} else {
+		_ = netConn.SetDeadline(time.Now().Add(dialTimeout))
+	}
+
+	conn, chans, reqs, err := ssh.NewClientConn(netConn, address, sshConfig)
+	if err != nil {
+		_ = netConn.Close()
+		return nil, err
+	}
+	_ = netConn.SetDeadline(time.Time{})
+	return ssh.NewClient(conn, chans, reqs), nil
+}
+
+// authMethods builds the authentication methods for a key path from server-map.json.
+// The key file is used when it can be read without a passphrase; the SSH agent is always offered.
+func authMethods(keyPath string) ([]ssh.AuthMethod, error) {
+	var methods []ssh.AuthMethod
+	var keyErr error
+
+	if keyPath != "" {
+		path, err := config.ExpandPath(keyPath)
+		if err != nil {
+			return nil, err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			keyErr = fmt.Errorf("failed to read key %s: %w", keyPath, err)
+		} else if signer, err := ssh.ParsePrivateKey(data); err == nil {
+			methods = append(methods, ssh.PublicKeys(signer))
+		} else {
+			keyErr = fmt.Errorf("failed to load key %s: %w", keyPath, err)
+		}
+	}
+
+	if sock := os.Getenv("SSH_AUTH_SOCK"); sock != "" {
+		if conn, err := net.Dial("unix", sock); err == nil {
+			methods = append(methods, ssh.PublicKeysCallback(agent.NewClient(conn).Signers))
+		}
+	}
+
+	if len(methods) == 0 {
+		if keyErr != nil {
+			return nil, keyErr
+		}
+		return nil, fmt.Errorf("no SSH key configured and no SSH agent available")
+	}
+	return methods, nil
+}
+
+// keepAlive pings the server until the client is closed.
+func (c *Client) keepAlive() {
+	ticker := time.NewTicker(keepAliveInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-c.done:
+			return
+		case <-ticker.C:
+			if _, _, err := c.conn.SendRequest("keepalive@openssh.com", true, nil); err != nil {
+				return
+			}
+		}
+	}
+}
+
+// Close ends the connection and any forwards running over it.
+func (c *Client) Close() error {
+	select {
+	case <-c.done:
+	default:
+		close(c.done)
+	}
+	return c.conn.Close()
+}
+
+// Run executes a command line on the server, streaming its output. With tty set a terminal
+// is allocated so colors and interactive prompts work. If ctx ends, the command is interrupted.
+func (c *Client) Run(ctx context.Context, command string, stdin io.Reader, stdout, stderr io.Writer, tty bool) error {
+	session, err := c.conn.NewSession()
+	if err != nil {
+		return fmt.Errorf("failed to open session on %s: %w", c.Name, err)
+	}
+	defer func() { _ = session.Close() }()
+
+	session.Stdout = stdout
+	session.Stderr = stderr
+	var input io.WriteCloser
+	if tty {
+		modes := ssh.TerminalModes{ssh.ECHO: 0, ssh.TTY_OP_ISPEED: 14400, ssh.TTY_OP_OSPEED: 14400}
+		if err := session.RequestPty("xterm-256color", 40, 120, modes); err != nil {
+			return fmt.Errorf("failed to allocate a terminal on %s: %w", c.Name, err)
+		}
+		if input, err = session.StdinPipe(); err != nil {
+			return err
+		}
+		if stdin != nil {
+			go func() { _, _ = io.Copy(input, stdin) }()
+		}
+	} else {
+		session.Stdin = stdin
+	}
+
+	if err := session.Start(command); err != nil {
+		return fmt.Errorf("failed to start command on %s: %w", c.Name, err)
+	}
+
+	result := make(chan error, 1)
+	go func() { result <- session.Wait() }()
+
+	select {
+	case err := <-result:
+		return exitError(err)
+	case <-ctx.Done():
+		// A terminal delivers Ctrl+C itself; without one, ask the server to signal the process
+		if input != nil {
+			_, _ = input.Write([]byte{3})
+		} else {
+			_ = session.Signal(ssh.SIGINT)
+		}
+		select {
+		case err := <-result:
+			return exitError(err)
+		case <-time.After(3 * time.Second):
+			return ctx.Err()
+		}
+	}
+}
+
+// exitError converts an SSH exit status into a plain error message.
+func exitError(err error) error {
+	var exitErr *ssh.ExitError
+	if errors.As(err, &exitErr) {
+		return fmt.Errorf("exit status %d", exitErr.ExitStatus())
+	}
+	return err
+}
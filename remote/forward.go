@@ -0,0 +1,59 @@
+package remote
+
+import (
+	"fmt"
+	"io"
+	"net"
+)
+
+// Forward is a local listener whose connections are tunnelled to an address on the server.
+type Forward struct {
+	// Local is the address being listened on, e.g. 127.0.0.1:8100
+	Local string
+	// Remote is the address dialed from the server, e.g. 127.0.0.1:8100
+	Remote   string
+	listener net.Listener
+}
+
+// Forward listens on localAddr and tunnels every connection to remoteAddr as seen from the
+// server, so services bound to the server's loopback interface can be reached without
+// exposing their ports. Use port 0 in localAddr to pick a free port.
+func (c *Client) Forward(localAddr, remoteAddr string) (*Forward, error) {
+	listener, err := net.Listen("tcp", localAddr)
+	if err != nil {
+		return nil, fmt.Errorf("failed to listen on %s: %w", localAddr, err)
+	}
+
+	f := &Forward{Local: listener.Addr().String(), Remote: remoteAddr, listener: listener}
+	go func() {
+		for {
+			local, err := listener.Accept()
+			if err != nil {
+				return // Listener closed
+			}
+			go c.tunnel(local, remoteAddr)
+		}
+	}()
+	return f, nil
+}
+
+// tunnel pipes a local connection to remoteAddr over the SSH connection.
+func (c *Client) tunnel(local net.Conn, remoteAddr string) {
+	defer func() { _ = local.Close() }()
+	upstream, err := c.conn.Dial("tcp", remoteAddr)
+	if err != nil {
+		return
+	}
+	defer func() { _ = upstream.Close() }()
+
+	done := make(chan struct{}, 2)
+	go func() { _, _ = io.Copy(upstream, local); done <- struct{}{} }()
+	go func() { _, _ = io.Copy(local, upstream); done <- struct{}{} }()
+	// Either side finishing ends the tunnel; the deferred closes unblock the other copy
+	<-done
+}
+
+// Close stops accepting connections. Open tunnels end when the client is closed.
+func (f *Forward) Close() error {
+	return f.listener.Close()
+}
@@ -0,0 +1,126 @@
+package remote
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"golang.org/x/crypto/ssh"
+	"golang.org/x/crypto/ssh/knownhosts"
+)
+
+// KnownHostsFile is where dex pins the host keys of server-map.json servers.
+// It is kept separate from ~/.ssh/known_hosts so dex never edits the user's own file.
+const KnownHostsFile = config.DexterRoot + "/config/known_hosts"
+
+// knownHostsMu serialises appends when several hosts are dialed at once.
+var knownHostsMu sync.Mutex
+
+// HostKeyMismatchError is returned when a server presents a key that differs from the pinned one.
+type HostKeyMismatchError struct {
+	Address     string
+	Fingerprint string
+	Path        string
+}
+
+func (e *HostKeyMismatchError) Error() string {
+	return fmt.Sprintf("host key for %s has changed (now %s); if this is expected, remove its line from %s", e.Address, e.Fingerprint, e.Path)
+}
+
+// knownHostsPath returns the expanded path of KnownHostsFile, creating it if needed.
+func knownHostsPath() (string, error) {
+	path, err := config.ExpandPath(KnownHostsFile)
+	if err != nil {
+		return "", err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return "", err
+	}
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_RDONLY, 0600)
+	if err != nil {
+		return "", fmt.Errorf("failed to open %s: %w", KnownHostsFile, err)
+	}
+	return path, file.Close()
+}
+
+// pinningCallback verifies host keys against KnownHostsFile. Keys for addresses that have
+// never been seen are pinned on first use and reported through onPin; any later change is refused.
+func pinningCallback(onPin func(address, fingerprint string)) (ssh.HostKeyCallback, error) {
+	path, err := knownHostsPath()
+	if err != nil {
+		return nil, err
+	}
+
+	return func(hostname string, remote net.Addr, key ssh.PublicKey) error {
+		knownHostsMu.Lock()
+		defer knownHostsMu.Unlock()
+
+		// Re-read on every connection so keys pinned earlier in this run are honoured
+		check, err := knownhosts.New(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", KnownHostsFile, err)
+		}
+		err = check(hostname, remote, key)
+		if err == nil {
+			return nil
+		}
+
+		var keyErr *knownhosts.KeyError
+		if !errors.As(err, &keyErr) {
+			return err
+		}
+		if len(keyErr.Want) > 0 {
+			return &HostKeyMismatchError{Address: hostname, Fingerprint: ssh.FingerprintSHA256(key), Path: KnownHostsFile}
+		}
+
+		file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("failed to pin host key: %w", err)
+		}
+		defer func() { _ = file.Close() }()
+		if _, err := file.WriteString(knownhosts.Line([]string{knownhosts.Normalize(hostname)}, key) + "\n"); err != nil {
+			return fmt.Errorf("failed to pin host key: %w", err)
+		}
+		if onPin != nil {
+			onPin(hostname, ssh.FingerprintSHA256(key))
+		}
+		return nil
+	}, nil
+}
+
+// pinnedAlgorithms returns the host key algorithms pinned for an address, so the server is
+// asked for the key we already trust rather than whichever type it prefers.
+func pinnedAlgorithms(address string) []string {
+	path, err := config.ExpandPath(KnownHostsFile)
+	if err != nil {
+		return nil
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil
+	}
+
+	want := knownhosts.Normalize(address)
+	var algorithms []string
+	for len(data) > 0 {
+		_, hosts, key, _, rest, err := ssh.ParseKnownHosts(data)
+		if err != nil {
+			break
+		}
+		data = rest
+		for _, host := range hosts {
+			if host != want {
+				continue
+			}
+			if key.Type() == ssh.KeyAlgoRSA {
+				algorithms = append(algorithms, ssh.KeyAlgoRSASHA512, ssh.KeyAlgoRSASHA256)
+			}
+			algorithms = append(algorithms, key.Type())
+		}
+	}
+	return algorithms
+}
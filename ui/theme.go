@@ -85,8 +85,15 @@ func GetCapturedOutput() string {
 	return StripANSI(outputBuffer.String())
 }
 
+// noColor follows the NO_COLOR convention (https://no-color.org): when it is set, colors are
+// stripped from everything printed.
+var noColor = os.Getenv("NO_COLOR") != ""
+
 // PrintRaw is the lowest-level printing function, used by all other functions.
 func PrintRaw(s string) {
+	if noColor {
+		s = StripANSI(s)
+	}
 	if isCapturing {
 		outputBuffer.WriteString(s)
 	}
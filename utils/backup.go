@@ -0,0 +1,557 @@
+package utils
+
+import (
+	"archive/tar"
+	"bufio"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/cache"
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+const (
+	// BackupDir is where 'dex backup' writes its archives
+	BackupDir = "~/Dexter/backups"
+	// backupManifestName is the first entry of every archive
+	backupManifestName = "manifest.json"
+	// backupRedisName holds the dump of PersistentRedisPrefixes
+	backupRedisName = "redis/persistent.ndjson"
+)
+
+// BackupManifest describes the contents of a backup archive.
+type BackupManifest struct {
+	Version    int          `json:"version"`
+	CreatedAt  time.Time    `json:"created_at"`
+	DexVersion string       `json:"dex_version"`
+	Hostname   string       `json:"hostname"`
+	Services   []string     `json:"services"`
+	RedisKeys  int          `json:"redis_keys"`
+	Files      []BackupFile `json:"files"`
+}
+
+// BackupFile is a single file stored in a backup archive.
+type BackupFile struct {
+	// Name is the path inside the archive
+	Name string `json:"name"`
+	// Target is where the file is restored to ("~/..."); empty for the Redis dump
+	Target string      `json:"target,omitempty"`
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+	SHA256 string      `json:"sha256"`
+}
+
+// redisDumpEntry is one line of the Redis dump: a key serialized with DUMP.
+type redisDumpEntry struct {
+	Key   string `json:"key"`
+	TTLMs int64  `json:"ttl_ms"`
+	Dump  []byte `json:"dump"`
+}
+
+// CreateBackup stages the config files, the given services' artifacts and a dump of the
+// persistent Redis keys, then writes them to a timestamped tar.zst archive in BackupDir.
+func CreateBackup(ctx context.Context, services []config.ServiceDefinition, dexVersion string, log func(message string)) (string, *BackupManifest, error) {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return "", nil, fmt.Errorf("zstd is required to write backups; install it with your package manager")
+	}
+
+	staging, err := os.MkdirTemp("", "dex-backup-")
+	if err != nil {
+		return "", nil, fmt.Errorf("failed to create staging directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(staging) }()
+
+	hostname, _ := os.Hostname()
+	manifest := &BackupManifest{
+		Version:    1,
+		CreatedAt:  time.Now().UTC(),
+		DexVersion: dexVersion,
+		Hostname:   hostname,
+	}
+
+	// 1. Config files
+	log("Staging ~/Dexter/config...")
+	if err := stageTree(staging, "config", config.DexterRoot+"/config", manifest); err != nil {
+		return "", nil, err
+	}
+
+	// 2. Service artifacts
+	for _, service := range services {
+		if !HasArtifacts(service) {
+			continue
+		}
+		log(fmt.Sprintf("Staging artifacts for %s...", service.ShortName))
+		for _, artifact := range service.Backup.Artifacts {
+			name := path.Join("artifacts", service.ID, strings.TrimPrefix(filepath.ToSlash(artifact), "~/"))
+			if err := stageTree(staging, name, artifact, manifest); err != nil {
+				return "", nil, fmt.Errorf("failed to stage %s artifact %s: %w", service.ShortName, artifact, err)
+			}
+		}
+		manifest.Services = append(manifest.Services, service.ID)
+	}
+
+	// 3. Redis persistent keys
+	log("Dumping persistent Redis keys...")
+	redisPath := filepath.Join(staging, filepath.FromSlash(backupRedisName))
+	count, err := dumpPersistentRedis(ctx, redisPath)
+	if err != nil {
+		return "", nil, err
+	}
+	manifest.RedisKeys = count
+	if err := addStagedFile(staging, backupRedisName, "", manifest); err != nil {
+		return "", nil, err
+	}
+
+	// 4. Archive
+	backupDir, err := config.ExpandPath(BackupDir)
+	if err != nil {
+		return "", nil, err
+	}
+	if err := os.MkdirAll(backupDir, 0755); err != nil {
+		return "", nil, fmt.Errorf("failed to create backup directory: %w", err)
+	}
+	archivePath := filepath.Join(backupDir, fmt.Sprintf("dex-backup-%s.tar.zst", manifest.CreatedAt.Local().Format("20060102-150405")))
+
+	log(fmt.Sprintf("Writing %s...", filepath.Base(archivePath)))
+	if err := writeBackupArchive(archivePath, staging, manifest); err != nil {
+		_ = os.Remove(archivePath)
+		return "", nil, err
+	}
+	return archivePath, manifest, nil
+}
+
+// stageTree copies a file or directory into the staging area under the given archive name.
+// Missing sources are skipped, as not every artifact exists on every machine.
+func stageTree(staging, name, source string, manifest *BackupManifest) error {
+	sourcePath, err := config.ExpandPath(source)
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(sourcePath); os.IsNotExist(err) {
+		return nil
+	}
+
+	return filepath.WalkDir(sourcePath, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		rel, err := filepath.Rel(sourcePath, p)
+		if err != nil {
+			return err
+		}
+		entryName := path.Join(name, filepath.ToSlash(rel))
+		target := strings.TrimSuffix(source, "/")
+		if rel != "." {
+			target = target + "/" + filepath.ToSlash(rel)
+		}
+
+		dst := filepath.Join(staging, filepath.FromSlash(entryName))
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := copyRegularFile(p, dst); err != nil {
+			return err
+		}
+		return addStagedFile(staging, entryName, target, manifest)
+	})
+}
+
+// addStagedFile records a staged file and its checksum in the manifest.
+func addStagedFile(staging, name, target string, manifest *BackupManifest) error {
+	p := filepath.Join(staging, filepath.FromSlash(name))
+	info, err := os.Stat(p)
+	if err != nil {
+		return err
+	}
+	sum, err := fileSHA256(p)
+	if err != nil {
+		return err
+	}
+	manifest.Files = append(manifest.Files, BackupFile{
+		Name:   name,
+		Target: target,
+		Size:   info.Size(),
+		Mode:   info.Mode().Perm(),
+		SHA256: sum,
+	})
+	return nil
+}
+
+// dumpPersistentRedis writes every key matching PersistentRedisPrefixes as NDJSON.
+func dumpPersistentRedis(ctx context.Context, dest string) (int, error) {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return 0, err
+	}
+	file, err := os.Create(dest)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	writer := bufio.NewWriter(file)
+	enc := json.NewEncoder(writer)
+	count := 0
+	for _, prefix := range PersistentRedisPrefixes {
+		err := cache.ScanKeys(ctx, client, cache.PrefixPattern(prefix), func(keys []string) error {
+			for _, key := range keys {
+				dump, err := client.Dump(ctx, key).Result()
+				if err != nil {
+					continue // Expired between SCAN and DUMP
+				}
+				ttl, err := client.PTTL(ctx, key).Result()
+				if err != nil {
+					continue
+				}
+				entry := redisDumpEntry{Key: key, Dump: []byte(dump)}
+				if ttl > 0 {
+					entry.TTLMs = ttl.Milliseconds()
+				}
+				if err := enc.Encode(entry); err != nil {
+					return err
+				}
+				count++
+			}
+			return nil
+		})
+		if err != nil {
+			return count, err
+		}
+	}
+	return count, writer.Flush()
+}
+
+// RestoreRedisDump loads a Redis dump written by a backup, replacing existing keys.
+func RestoreRedisDump(ctx context.Context, src string) (int, error) {
+	file, err := os.Open(src)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = file.Close() }()
+
+	client, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer func() { _ = client.Close() }()
+
+	dec := json.NewDecoder(bufio.NewReader(file))
+	count := 0
+	for dec.More() {
+		var entry redisDumpEntry
+		if err := dec.Decode(&entry); err != nil {
+			return count, fmt.Errorf("corrupt Redis dump: %w", err)
+		}
+		ttl := time.Duration(entry.TTLMs) * time.Millisecond
+		if err := client.RestoreReplace(ctx, entry.Key, ttl, string(entry.Dump)).Err(); err != nil {
+			return count, fmt.Errorf("failed to restore key %s: %w", entry.Key, err)
+		}
+		count++
+	}
+	return count, nil
+}
+
+// writeBackupArchive writes the manifest followed by every staged file, compressed with zstd.
+func writeBackupArchive(archivePath, staging string, manifest *BackupManifest) error {
+	out, err := os.OpenFile(archivePath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("failed to create archive: %w", err)
+	}
+	defer func() { _ = out.Close() }()
+
+	zstd := exec.Command("zstd", "-q", "-c", "-T0")
+	zstd.Stdout = out
+	stdin, err := zstd.StdinPipe()
+	if err != nil {
+		return err
+	}
+	if err := zstd.Start(); err != nil {
+		return fmt.Errorf("failed to start zstd: %w", err)
+	}
+
+	tw := tar.NewWriter(stdin)
+	writeErr := func() error {
+		manifestData, err := json.MarshalIndent(manifest, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := tw.WriteHeader(&tar.Header{Name: backupManifestName, Mode: 0644, Size: int64(len(manifestData)), ModTime: manifest.CreatedAt}); err != nil {
+			return err
+		}
+		if _, err := tw.Write(manifestData); err != nil {
+			return err
+		}
+
+		for _, f := range manifest.Files {
+			if err := tw.WriteHeader(&tar.Header{Name: f.Name, Mode: int64(f.Mode), Size: f.Size, ModTime: manifest.CreatedAt}); err != nil {
+				return err
+			}
+			in, err := os.Open(filepath.Join(staging, filepath.FromSlash(f.Name)))
+			if err != nil {
+				return err
+			}
+			_, err = io.Copy(tw, in)
+			_ = in.Close()
+			if err != nil {
+				return err
+			}
+		}
+		return tw.Close()
+	}()
+	_ = stdin.Close()
+
+	waitErr := zstd.Wait()
+	if writeErr != nil {
+		return fmt.Errorf("failed to write archive: %w", writeErr)
+	}
+	if waitErr != nil {
+		return fmt.Errorf("zstd failed: %w", waitErr)
+	}
+	return out.Sync()
+}
+
+// openBackupArchive streams the tar contents of a tar.zst archive.
+func openBackupArchive(archivePath string, fn func(tr *tar.Reader) error) error {
+	if _, err := exec.LookPath("zstd"); err != nil {
+		return fmt.Errorf("zstd is required to read backups; install it with your package manager")
+	}
+	zstd := exec.Command("zstd", "-q", "-d", "-c", archivePath)
+	stdout, err := zstd.StdoutPipe()
+	if err != nil {
+		return err
+	}
+	if err := zstd.Start(); err != nil {
+		return fmt.Errorf("failed to start zstd: %w", err)
+	}
+
+	readErr := fn(tar.NewReader(stdout))
+	// Drain so zstd can exit even if fn stopped early
+	_, _ = io.Copy(io.Discard, stdout)
+	waitErr := zstd.Wait()
+	if readErr != nil {
+		return readErr
+	}
+	if waitErr != nil {
+		return fmt.Errorf("failed to decompress %s: %w", filepath.Base(archivePath), waitErr)
+	}
+	return nil
+}
+
+// ReadBackupManifest reads only the manifest at the start of an archive.
+func ReadBackupManifest(archivePath string) (*BackupManifest, error) {
+	var manifest *BackupManifest
+	err := openBackupArchive(archivePath, func(tr *tar.Reader) error {
+		header, err := tr.Next()
+		if err != nil {
+			return fmt.Errorf("failed to read archive: %w", err)
+		}
+		if header.Name != backupManifestName {
+			return fmt.Errorf("not a dex backup: first entry is %s", header.Name)
+		}
+		manifest = &BackupManifest{}
+		if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+			return fmt.Errorf("invalid manifest: %w", err)
+		}
+		return nil
+	})
+	return manifest, err
+}
+
+// ExtractBackup unpacks an archive into dest and verifies every file against the manifest.
+// Nothing outside dest is touched, so a corrupt archive never reaches the live system.
+func ExtractBackup(archivePath, dest string) (*BackupManifest, error) {
+	var manifest *BackupManifest
+	seen := make(map[string]string)
+
+	err := openBackupArchive(archivePath, func(tr *tar.Reader) error {
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read archive: %w", err)
+			}
+
+			if header.Name == backupManifestName {
+				manifest = &BackupManifest{}
+				if err := json.NewDecoder(tr).Decode(manifest); err != nil {
+					return fmt.Errorf("invalid manifest: %w", err)
+				}
+				continue
+			}
+
+			clean := path.Clean(header.Name)
+			if path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") {
+				return fmt.Errorf("archive entry escapes the backup: %s", header.Name)
+			}
+			dst := filepath.Join(dest, filepath.FromSlash(clean))
+			if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+				return err
+			}
+			out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+			if err != nil {
+				return err
+			}
+			hash := sha256.New()
+			_, err = io.Copy(io.MultiWriter(out, hash), tr)
+			_ = out.Close()
+			if err != nil {
+				return fmt.Errorf("failed to extract %s: %w", header.Name, err)
+			}
+			seen[clean] = hex.EncodeToString(hash.Sum(nil))
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if manifest == nil {
+		return nil, fmt.Errorf("not a dex backup: manifest.json is missing")
+	}
+
+	var problems []string
+	for _, f := range manifest.Files {
+		sum, ok := seen[path.Clean(f.Name)]
+		switch {
+		case !ok:
+			problems = append(problems, fmt.Sprintf("%s is missing", f.Name))
+		case sum != f.SHA256:
+			problems = append(problems, fmt.Sprintf("%s checksum mismatch", f.Name))
+		}
+	}
+	if len(problems) > 0 {
+		return manifest, fmt.Errorf("backup failed verification:\n  %s", strings.Join(problems, "\n  "))
+	}
+	return manifest, nil
+}
+
+// VerifyBackup checks every file in an archive against its manifest checksum.
+func VerifyBackup(archivePath string) (*BackupManifest, error) {
+	scratch, err := os.MkdirTemp("", "dex-verify-")
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+	return ExtractBackup(archivePath, scratch)
+}
+
+// InstallBackupFiles copies every extracted file with a target back into place. Every target
+// is checked before the first file is written, so a bad manifest changes nothing.
+func InstallBackupFiles(extracted string, manifest *BackupManifest) error {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return fmt.Errorf("failed to get home directory: %w", err)
+	}
+	targets := make([]string, len(manifest.Files))
+	for i, f := range manifest.Files {
+		if f.Target == "" {
+			continue
+		}
+		if !strings.HasPrefix(f.Target, "~/") {
+			return fmt.Errorf("refusing to restore %s outside the home directory", f.Target)
+		}
+		target, err := config.ExpandPath(f.Target)
+		if err != nil {
+			return err
+		}
+		if rel, err := filepath.Rel(home, target); err != nil || rel == ".." || strings.HasPrefix(rel, "../") {
+			return fmt.Errorf("refusing to restore %s outside the home directory", f.Target)
+		}
+		if _, err := os.Stat(filepath.Join(extracted, filepath.FromSlash(f.Name))); err != nil {
+			return fmt.Errorf("backup is missing %s: %w", f.Name, err)
+		}
+		targets[i] = target
+	}
+
+	for i, f := range manifest.Files {
+		target := targets[i]
+		if target == "" {
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+			return err
+		}
+		if err := copyRegularFile(filepath.Join(extracted, filepath.FromSlash(f.Name)), target); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", f.Target, err)
+		}
+		if err := os.Chmod(target, f.Mode); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// BackupRedisDumpPath returns where an extracted archive keeps its Redis dump.
+func BackupRedisDumpPath(extracted string) string {
+	return filepath.Join(extracted, filepath.FromSlash(backupRedisName))
+}
+
+// ListBackups returns the archives in BackupDir, newest first.
+func ListBackups() ([]string, error) {
+	backupDir, err := config.ExpandPath(BackupDir)
+	if err != nil {
+		return nil, err
+	}
+	matches, err := filepath.Glob(filepath.Join(backupDir, "*.tar.zst"))
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(sort.Reverse(sort.StringSlice(matches)))
+	return matches, nil
+}
+
+// copyRegularFile copies a file's contents, preserving its permissions.
+func copyRegularFile(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	info, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, info.Mode().Perm())
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		_ = out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+func fileSHA256(p string) (string, error) {
+	f, err := os.Open(p)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = f.Close() }()
+	hash := sha256.New()
+	if _, err := io.Copy(hash, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
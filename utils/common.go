@@ -4,7 +4,6 @@ import (
 	"context"
 	"fmt"
 	"math/rand"
-	"os"
 	"sort"
 	"strconv"
 	"strings"
@@ -15,6 +14,24 @@ import (
 	"github.com/EasterCompany/dex-cli/ui"
 )
 
+// PersistentRedisPrefixes are the key prefixes that survive WipeRedis and are included in backups.
+var PersistentRedisPrefixes = []string{
+	"user:profile:",
+	"events:", // Preserves events:timeline, events:type:*, events:channel:*, events:user:*
+	"chores:",
+	"roadmap:",
+	"event:", // Preserves individual event data
+	"system:is_paused",
+	"courier:last_run:",
+	"guardian:last_run:",
+	"analyzer:last_run:",
+	"imaginator:last_run:",
+	"imaginator:processed_alerts", // Persist processed alerts logic
+	"fabricator:last_run:",
+	"discord-audio:", // Persist audio buffers if needed (short TTL anyway)
+	"handled:event:", // Persist race-condition locks
+}
+
 // WipeRedis clears ephemeral runtime state from Redis while preserving persistent data.
 func WipeRedis(ctx context.Context) error {
 	ui.PrintInfo("Cleaning Redis runtime state (preserving dossiers and history)...")
@@ -24,45 +41,12 @@ func WipeRedis(ctx context.Context) error {
 	}
 	defer func() { _ = redisClient.Close() }()
 
-	persistentPrefixes := []string{
-		"user:profile:",
-		"events:", // Preserves events:timeline, events:type:*, events:channel:*, events:user:*
-		"chores:",
-		"roadmap:",
-		"event:", // Preserves individual event data
-		"system:is_paused",
-		"courier:last_run:",
-		"guardian:last_run:",
-		"analyzer:last_run:",
-		"imaginator:last_run:",
-		"imaginator:processed_alerts", // Persist processed alerts logic
-		"fabricator:last_run:",
-		"discord-audio:", // Persist audio buffers if needed (short TTL anyway)
-		"handled:event:", // Persist race-condition locks
-	}
-
-	var cursor uint64
 	deletedCount := 0
 	preservedCount := 0
 
-	for {
-		var keys []string
-		var err error
-		keys, cursor, err = redisClient.Scan(ctx, cursor, "*", 100).Result()
-		if err != nil {
-			return fmt.Errorf("failed to scan Redis keys: %w", err)
-		}
-
+	err = cache.ScanKeys(ctx, redisClient, "*", func(keys []string) error {
 		for _, key := range keys {
-			isPersistent := false
-			for _, prefix := range persistentPrefixes {
-				if strings.HasPrefix(key, prefix) {
-					isPersistent = true
-					break
-				}
-			}
-
-			if isPersistent {
+			if isPersistentRedisKey(key) {
 				preservedCount++
 				continue
 			}
@@ -73,30 +57,63 @@ func WipeRedis(ctx context.Context) error {
 				deletedCount++
 			}
 		}
-
-		if cursor == 0 {
-			break
-		}
+		return nil
+	})
+	if err != nil {
+		return fmt.Errorf("failed to scan Redis keys: %w", err)
 	}
 
 	ui.PrintSuccess(fmt.Sprintf("Redis cleanup complete. Removed %d ephemeral keys, preserved %d persistent items.", deletedCount, preservedCount))
 	return nil
 }
 
+// PlanWipeRedis returns the keys WipeRedis would delete and how many it would preserve,
+// without deleting anything.
+func PlanWipeRedis(ctx context.Context) ([]string, int, error) {
+	redisClient, err := cache.GetLocalClient(ctx)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to connect to Redis: %w", err)
+	}
+	defer func() { _ = redisClient.Close() }()
+
+	deleteKeys := []string{}
+	preserved := 0
+	err = cache.ScanKeys(ctx, redisClient, "*", func(keys []string) error {
+		for _, key := range keys {
+			if isPersistentRedisKey(key) {
+				preserved++
+			} else {
+				deleteKeys = append(deleteKeys, key)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to scan Redis keys: %w", err)
+	}
+	sort.Strings(deleteKeys)
+	return deleteKeys, preserved, nil
+}
+
+// isPersistentRedisKey reports whether a key survives WipeRedis.
+func isPersistentRedisKey(key string) bool {
+	for _, prefix := range PersistentRedisPrefixes {
+		if strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
 // GetConfiguredServices loads the service-map.json and merges its values
 // with the master service definitions. This ensures user-configured
-// domains, ports, and credentials are used.
+// domains, ports, and credentials are used, and that user-defined
+// services are included alongside the built-in ones.
 func GetConfiguredServices() ([]config.ServiceDefinition, error) {
-	// 1. Load the user's service-map.json
-	serviceMap, err := config.LoadServiceMapConfig()
+	// 1. Load the user's service-map.json, skipping (and warning about) invalid entries
+	serviceMap, err := config.LoadValidServiceMap()
 	if err != nil {
-		if os.IsNotExist(err) {
-			// If it doesn't exist, use the default map
-			serviceMap = config.DefaultServiceMapConfig()
-		} else {
-			// Other error
-			return nil, fmt.Errorf("failed to load service-map.json: %w", err)
-		}
+		return nil, err
 	}
 
 	// 2. Get the hardcoded master list of all *possible* services
@@ -107,19 +124,17 @@ func GetConfiguredServices() ([]config.ServiceDefinition, error) {
 		masterDefs[def.ID] = def
 	}
 
-	// 3. Create the final list by merging the service map values
+	// 3. Create the final list by merging the service map values, walking the types in a fixed
+	// order so the list is the same on every run
 	var configuredServices []config.ServiceDefinition
 
-	for serviceType, serviceEntries := range serviceMap.Services {
-		for _, entry := range serviceEntries {
+	for _, serviceType := range serviceMap.Types() {
+		for _, entry := range serviceMap.Services[serviceType] {
 			// Find the master definition for this service ID
 			masterDef, ok := masterDefs[entry.ID]
 			if !ok {
-				// This service is in service-map.json but not in the hardcoded master list
-				// We can't check it if we don't know its type, shortname etc.
-				// A better approach: The masterDef *must* exist.
-				// Let's assume for status, we only check services known to the CLI.
-				continue
+				// User-defined entries carry their full definition
+				masterDef = entry.ToServiceDefinition(serviceType)
 			}
 
 			// Merge: Use master def as base, but override with user's config
@@ -133,13 +148,38 @@ func GetConfiguredServices() ([]config.ServiceDefinition, error) {
 			if entry.Credentials != nil {
 				masterDef.Credentials = entry.Credentials
 			}
+			if entry.SystemdName != "" {
+				masterDef.SystemdName = entry.SystemdName
+			}
+			if entry.BuildKind != "" {
+				masterDef.BuildKind = entry.BuildKind
+			}
+			if entry.HealthPath != "" {
+				masterDef.HealthPath = entry.HealthPath
+			}
+			if entry.Backup != nil {
+				masterDef.Backup = entry.Backup
+			}
+			if entry.DependsOn != nil {
+				masterDef.DependsOn = entry.DependsOn
+			}
+			if entry.BuildAfter != nil {
+				masterDef.BuildAfter = entry.BuildAfter
+			}
+			if entry.Checks != nil {
+				masterDef.Checks = entry.Checks
+			}
+			if entry.Unit != nil {
+				masterDef.Unit = entry.Unit
+			}
 
 			configuredServices = append(configuredServices, masterDef)
 		}
 	}
 
-	// Sort by port to maintain a consistent order
-	sort.Slice(configuredServices, func(i, j int) bool {
+	// Sort by port to maintain a consistent order; services sharing a port (or without one)
+	// keep their service-map order
+	sort.SliceStable(configuredServices, func(i, j int) bool {
 		return configuredServices[i].Port < configuredServices[j].Port
 	})
 
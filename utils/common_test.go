@@ -0,0 +1,39 @@
+package utils
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+func TestGetConfiguredServicesOrder(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // no service-map.json, so the default map is used
+	config.ReloadServiceMap()
+	defer config.ReloadServiceMap()
+
+	ids := func() []string {
+		services, err := GetConfiguredServices()
+		if err != nil {
+			t.Fatalf("GetConfiguredServices() error = %v", err)
+		}
+		var ids []string
+		for i, s := range services {
+			if i > 0 && s.Port < services[i-1].Port {
+				t.Fatalf("%s (port %q) is listed after %s (port %q)", s.ID, s.Port, services[i-1].ID, services[i-1].Port)
+			}
+			ids = append(ids, s.ID)
+		}
+		return ids
+	}
+
+	first := ids()
+	if len(first) < 2 {
+		t.Fatalf("default map lists %d services, want several to compare orders", len(first))
+	}
+	for range 20 {
+		if got := ids(); !slices.Equal(got, first) {
+			t.Fatalf("order changed between calls:\n%v\n%v", first, got)
+		}
+	}
+}
@@ -0,0 +1,187 @@
+package utils
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// downloadAttempts is how many consecutive attempts may fail without making progress
+	downloadAttempts = 5
+	// downloadBackoff is the wait after the first failed attempt; it doubles up to downloadMaxBackoff
+	downloadBackoff    = time.Second
+	downloadMaxBackoff = 30 * time.Second
+	// downloadIdleTimeout abandons an attempt when no data has arrived for this long
+	downloadIdleTimeout = 60 * time.Second
+)
+
+// DownloadProgress is called as a download advances. total is 0 when the size is unknown.
+type DownloadProgress func(written, total int64)
+
+// permanentError marks a failure that retrying cannot fix, such as a 404.
+type permanentError struct{ err error }
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// DownloadFile fetches url into destination. If destination already holds part of the file,
+// from this or an earlier interrupted run, the download resumes from where it stopped using an
+// HTTP Range request. Failed attempts are retried with exponential backoff; an attempt that made
+// progress resets the retry budget. size is the expected length, or 0 if unknown.
+func DownloadFile(ctx context.Context, url, destination string, size int64, progress DownloadProgress) error {
+	backoff := downloadBackoff
+	var lastErr error
+	for failures := 0; failures < downloadAttempts; {
+		before := fileSize(destination)
+		err := downloadAttempt(ctx, url, destination, size, progress)
+		if err == nil {
+			return nil
+		}
+		var permanent *permanentError
+		if errors.As(err, &permanent) || ctx.Err() != nil {
+			return err
+		}
+		lastErr = err
+
+		if fileSize(destination) > before {
+			failures, backoff = 0, downloadBackoff
+			continue
+		}
+		failures++
+		if failures == downloadAttempts {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, downloadMaxBackoff)
+	}
+	return fmt.Errorf("download failed after %d attempts: %w", downloadAttempts, lastErr)
+}
+
+// downloadAttempt makes a single request, appending to whatever destination already holds.
+func downloadAttempt(ctx context.Context, url, destination string, size int64, progress DownloadProgress) error {
+	offset := fileSize(destination)
+	if size > 0 && offset == size {
+		if progress != nil {
+			progress(size, size)
+		}
+		return nil
+	}
+	if size > 0 && offset > size {
+		// Left over from a different file; start again
+		if err := os.Truncate(destination, 0); err != nil {
+			return err
+		}
+		offset = 0
+	}
+
+	attemptCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	idle := time.AfterFunc(downloadIdleTimeout, cancel)
+	defer idle.Stop()
+
+	req, err := http.NewRequestWithContext(attemptCtx, http.MethodGet, url, nil)
+	if err != nil {
+		return &permanentError{err}
+	}
+	if offset > 0 {
+		req.Header.Set("Range", fmt.Sprintf("bytes=%d-", offset))
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	flags := os.O_CREATE | os.O_WRONLY
+	total := size
+	switch {
+	case resp.StatusCode == http.StatusPartialContent:
+		flags |= os.O_APPEND
+		if rangeTotal := contentRangeTotal(resp.Header.Get("Content-Range")); rangeTotal > 0 {
+			total = rangeTotal
+		}
+	case resp.StatusCode == http.StatusOK:
+		// The server ignored the range, so the body is the whole file
+		flags |= os.O_TRUNC
+		offset = 0
+		if resp.ContentLength > 0 {
+			total = resp.ContentLength
+		}
+	case resp.StatusCode == http.StatusRequestedRangeNotSatisfiable:
+		// The partial file is not a prefix of what the server has; start again next attempt
+		_ = os.Truncate(destination, 0)
+		return fmt.Errorf("server rejected resume from byte %d", offset)
+	case resp.StatusCode >= 500 || resp.StatusCode == http.StatusTooManyRequests:
+		return fmt.Errorf("server returned status %d", resp.StatusCode)
+	default:
+		return &permanentError{fmt.Errorf("server returned status %d", resp.StatusCode)}
+	}
+
+	out, err := os.OpenFile(destination, flags, 0644)
+	if err != nil {
+		return &permanentError{err}
+	}
+	defer func() { _ = out.Close() }()
+
+	written := offset
+	buf := make([]byte, 64*1024)
+	for {
+		n, readErr := resp.Body.Read(buf)
+		if n > 0 {
+			idle.Reset(downloadIdleTimeout)
+			if _, err := out.Write(buf[:n]); err != nil {
+				return &permanentError{err}
+			}
+			written += int64(n)
+			if progress != nil {
+				progress(written, total)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			if ctx.Err() == nil && attemptCtx.Err() != nil {
+				return fmt.Errorf("no data received for %s", downloadIdleTimeout)
+			}
+			return readErr
+		}
+	}
+	if total > 0 && written < total {
+		return fmt.Errorf("connection closed after %d of %d bytes", written, total)
+	}
+	return nil
+}
+
+// contentRangeTotal returns the complete length from a "bytes start-end/total" header, or 0.
+func contentRangeTotal(header string) int64 {
+	slash := strings.LastIndex(header, "/")
+	if slash < 0 {
+		return 0
+	}
+	total, err := strconv.ParseInt(header[slash+1:], 10, 64)
+	if err != nil {
+		return 0
+	}
+	return total
+}
+
+// fileSize returns the size of path, or 0 if it does not exist.
+func fileSize(path string) int64 {
+	info, err := os.Stat(path)
+	if err != nil {
+		return 0
+	}
+	return info.Size()
+}
@@ -3,45 +3,60 @@ package utils
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"time"
 
 	"github.com/EasterCompany/dex-cli/config"
 	"github.com/EasterCompany/dex-cli/git"
+	"github.com/EasterCompany/dex-cli/release"
 )
 
 // RunUnifiedBuildPipeline runs the unified build and test process for a service.
 // Supports Go services (go mod tidy, fmt, lint, test, build) and Python services (run.sh).
-func RunUnifiedBuildPipeline(ctx context.Context, service config.ServiceDefinition, log func(message string), major, minor, patch int) (bool, error) {
+// Output of the tools it runs is written to out. Besides whether anything was built, it
+// returns the version of the binary the build replaced and stored for rollback, which is empty
+// when no binary was stored.
+func RunUnifiedBuildPipeline(ctx context.Context, service config.ServiceDefinition, log func(message string), out io.Writer, major, minor, patch int) (bool, string, error) {
 	sourcePath, err := config.ExpandPath(service.Source)
 	if err != nil {
-		return false, fmt.Errorf("failed to expand source path: %w", err)
+		return false, "", fmt.Errorf("failed to expand source path: %w", err)
 	}
 
 	versionStr := fmt.Sprintf("%d.%d.%d", major, minor, patch)
 	log(fmt.Sprintf("Starting unified build pipeline for %s (v%s)...", service.ShortName, versionStr))
 
+	// An explicit build kind in service-map.json skips detection
+	switch service.GetBuildKind() {
+	case config.BuildKindNone:
+		log(fmt.Sprintf("%s declares no build pipeline, skipping.", service.ShortName))
+		return false, "", nil
+	case config.BuildKindPython:
+		built, err := runPythonBuildPipeline(ctx, service, sourcePath, log, out)
+		return built, "", err
+	}
+
 	// Check for Go service (prioritize over Python if go.mod exists)
 	goModPath := filepath.Join(sourcePath, "go.mod")
-	if _, err := os.Stat(goModPath); err == nil {
+	if _, err := os.Stat(goModPath); err == nil || service.GetBuildKind() == config.BuildKindGo {
 		// Fetch Git Info for ldflags
 		branch, commit := git.GetVersionInfo(sourcePath)
 		buildDate := time.Now().Format("2006-01-02")
 		buildYear := time.Now().Format("2006")
 		buildHash := commit // Use commit hash as build hash for now
-		arch := runtime.GOARCH
 
-		// Construct ldflags
-		ldflags := fmt.Sprintf(
-			"-X main.version=%s -X main.branch=%s -X main.commit=%s -X main.buildDate=%s -X main.buildYear=%s -X main.buildHash=%s -X main.arch=%s",
-			versionStr, branch, commit, buildDate, buildYear, buildHash, arch,
-		)
+		// Construct ldflags; arch differs per target platform
+		ldflags := func(arch string) string {
+			return fmt.Sprintf(
+				"-X main.version=%s -X main.branch=%s -X main.commit=%s -X main.buildDate=%s -X main.buildYear=%s -X main.buildHash=%s -X main.arch=%s",
+				versionStr, branch, commit, buildDate, buildYear, buildHash, arch,
+			)
+		}
 
-		return runGoBuildPipeline(ctx, service, sourcePath, log, ldflags, versionStr, branch, commit)
+		return runGoBuildPipeline(ctx, service, sourcePath, log, out, ldflags, versionStr, branch, commit)
 	}
 
 	// Check for Python service (marker: requirements.txt or main.py)
@@ -60,7 +75,8 @@ func RunUnifiedBuildPipeline(ctx context.Context, service config.ServiceDefiniti
 	}
 
 	if isPython {
-		return runPythonBuildPipeline(ctx, service, sourcePath, log)
+		built, err := runPythonBuildPipeline(ctx, service, sourcePath, log, out)
+		return built, "", err
 	}
 
 	// Default to Go pipeline (fallback)
@@ -69,18 +85,54 @@ func RunUnifiedBuildPipeline(ctx context.Context, service config.ServiceDefiniti
 	buildDate := time.Now().Format("2006-01-02")
 	buildYear := time.Now().Format("2006")
 	buildHash := commit // Use commit hash as build hash for now
-	arch := runtime.GOARCH
 
-	// Construct ldflags
-	ldflags := fmt.Sprintf(
-		"-X main.version=%s -X main.branch=%s -X main.commit=%s -X main.buildDate=%s -X main.buildYear=%s -X main.buildHash=%s -X main.arch=%s",
-		versionStr, branch, commit, buildDate, buildYear, buildHash, arch,
-	)
+	// Construct ldflags; arch differs per target platform
+	ldflags := func(arch string) string {
+		return fmt.Sprintf(
+			"-X main.version=%s -X main.branch=%s -X main.commit=%s -X main.buildDate=%s -X main.buildYear=%s -X main.buildHash=%s -X main.arch=%s",
+			versionStr, branch, commit, buildDate, buildYear, buildHash, arch,
+		)
+	}
+
+	return runGoBuildPipeline(ctx, service, sourcePath, log, out, ldflags, versionStr, branch, commit)
+}
 
-	return runGoBuildPipeline(ctx, service, sourcePath, log, ldflags, versionStr, branch, commit)
+// IsPythonService reports whether a service's source is built by the Python pipeline,
+// using the same detection as RunUnifiedBuildPipeline.
+func IsPythonService(service config.ServiceDefinition) bool {
+	switch service.GetBuildKind() {
+	case config.BuildKindPython:
+		return true
+	case config.BuildKindNone, config.BuildKindGo:
+		return false
+	}
+	sourcePath, err := config.ExpandPath(service.Source)
+	if err != nil || service.Source == "" {
+		return false
+	}
+	if _, err := os.Stat(filepath.Join(sourcePath, "go.mod")); err == nil {
+		return false
+	}
+	for _, marker := range []string{"requirements.txt", "main.py"} {
+		if _, err := os.Stat(filepath.Join(sourcePath, marker)); err == nil {
+			return true
+		}
+	}
+	return false
 }
 
-func runPythonBuildPipeline(ctx context.Context, service config.ServiceDefinition, sourcePath string, log func(message string)) (bool, error) {
+// SetupPythonService creates a Python service's venv and installs its requirements
+// without running the rest of a build.
+func SetupPythonService(ctx context.Context, service config.ServiceDefinition, log func(message string), out io.Writer) error {
+	sourcePath, err := config.ExpandPath(service.Source)
+	if err != nil {
+		return fmt.Errorf("failed to expand source path: %w", err)
+	}
+	_, err = runPythonBuildPipeline(ctx, service, sourcePath, log, out)
+	return err
+}
+
+func runPythonBuildPipeline(ctx context.Context, service config.ServiceDefinition, sourcePath string, log func(message string), out io.Writer) (bool, error) {
 	log("Detected Python service.")
 
 	// Let's create a virtual env and install requirements if they exist.
@@ -115,8 +167,8 @@ func runPythonBuildPipeline(ctx context.Context, service config.ServiceDefinitio
 
 		cmd := exec.CommandContext(ctx, pipCmd, "install", "-r", "requirements.txt")
 		cmd.Dir = sourcePath
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		cmd.Stdout = out
+		cmd.Stderr = out
 		if err := cmd.Run(); err != nil {
 			return false, fmt.Errorf("failed to install requirements: %w", err)
 		}
@@ -126,7 +178,7 @@ func runPythonBuildPipeline(ctx context.Context, service config.ServiceDefinitio
 	return true, nil
 }
 
-func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, sourcePath string, log func(message string), ldflags string, versionStr string, branch string, commit string) (bool, error) {
+func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, sourcePath string, log func(message string), out io.Writer, ldflags func(arch string) string, versionStr string, branch string, commit string) (bool, string, error) {
 	log("Stopping service if running...")
 	_ = exec.CommandContext(ctx, "systemctl", "--user", "stop", service.SystemdName).Run()
 
@@ -135,7 +187,7 @@ func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, s
 	cmd := exec.CommandContext(ctx, "go", "mod", "tidy")
 	cmd.Dir = sourcePath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return false, fmt.Errorf("%s 'go mod tidy' failed: %w\n%s", service.ShortName, err, string(output))
+		return false, "", fmt.Errorf("%s 'go mod tidy' failed: %w\n%s", service.ShortName, err, string(output))
 	}
 
 	// 3. Format
@@ -143,7 +195,7 @@ func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, s
 	cmd = exec.CommandContext(ctx, "go", "fmt", "./...")
 	cmd.Dir = sourcePath
 	if output, err := cmd.CombinedOutput(); err != nil {
-		return false, fmt.Errorf("%s 'go fmt' failed: %w\n%s", service.ShortName, err, string(output))
+		return false, "", fmt.Errorf("%s 'go fmt' failed: %w\n%s", service.ShortName, err, string(output))
 	}
 
 	// 4. Lint
@@ -152,7 +204,7 @@ func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, s
 		cmd = exec.CommandContext(ctx, "golangci-lint", "run")
 		cmd.Dir = sourcePath
 		if output, err := cmd.CombinedOutput(); err != nil {
-			return false, fmt.Errorf("%s 'golangci-lint run' failed: %w\n%s", service.ShortName, err, string(output))
+			return false, "", fmt.Errorf("%s 'golangci-lint run' failed: %w\n%s", service.ShortName, err, string(output))
 		}
 		log("0 issues.")
 	} else {
@@ -163,10 +215,10 @@ func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, s
 	log("Testing...")
 	cmd = exec.CommandContext(ctx, "go", "test", "./...")
 	cmd.Dir = sourcePath
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
+	cmd.Stdout = out
+	cmd.Stderr = out
 	if err := cmd.Run(); err != nil {
-		return false, fmt.Errorf("%s tests failed: %w", service.ShortName, err)
+		return false, "", fmt.Errorf("%s tests failed: %w", service.ShortName, err)
 	}
 
 	// 6. Build
@@ -174,11 +226,16 @@ func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, s
 
 	binDir := filepath.Join(os.Getenv("HOME"), "Dexter", "bin")
 	if err := os.MkdirAll(binDir, 0755); err != nil {
-		return false, fmt.Errorf("failed to create bin dir: %w", err)
+		return false, "", fmt.Errorf("failed to create bin dir: %w", err)
 	}
 
-	buildBinary := func(outputName string, buildTags string) error {
-		args := []string{"build", "-ldflags", ldflags, "-o", filepath.Join(binDir, outputName)}
+	platforms, err := release.BuildPlatforms()
+	if err != nil {
+		return false, "", err
+	}
+
+	buildBinary := func(platform release.Platform, outputPath string, buildTags string) error {
+		args := []string{"build", "-ldflags", ldflags(platform.Arch), "-o", outputPath}
 		if buildTags != "" {
 			args = append(args, "-tags", buildTags)
 		}
@@ -190,23 +247,52 @@ func runGoBuildPipeline(ctx context.Context, service config.ServiceDefinition, s
 			fmt.Sprintf("DEX_VERSION=%s", versionStr),
 			fmt.Sprintf("DEX_BRANCH=%s", branch),
 			fmt.Sprintf("DEX_COMMIT=%s", commit),
+			fmt.Sprintf("GOOS=%s", platform.OS),
+			fmt.Sprintf("GOARCH=%s", platform.Arch),
 		)
-		cmd.Stdout = os.Stdout
-		cmd.Stderr = os.Stderr
+		if platform != release.HostPlatform() {
+			// Cross-compiled binaries cannot link against the host's C libraries
+			cmd.Env = append(cmd.Env, "CGO_ENABLED=0")
+		}
+		cmd.Stdout = out
+		cmd.Stderr = out
 		return cmd.Run()
 	}
 
+	// Keep the binary being replaced so a bad build can be rolled back
+	previous, err := SnapshotBinary(service)
+	if err != nil {
+		log(fmt.Sprintf("Warning: failed to store previous binary for rollback: %v", err))
+	} else if previous != "" {
+		log(fmt.Sprintf("Stored previous binary (%s) for rollback", previous))
+	}
+
 	outputName := service.ID
 	if service.ShortName == "cli" {
 		outputName = "dex"
 	}
-	if err := buildBinary(outputName, ""); err != nil {
-		return false, fmt.Errorf("failed to build %s: %w", service.ID, err)
+	if err := buildBinary(release.HostPlatform(), filepath.Join(binDir, outputName), ""); err != nil {
+		return false, "", fmt.Errorf("failed to build %s: %w", service.ID, err)
 	}
 	log(fmt.Sprintf("✓ %s built successfully", service.ID))
 
+	// Cross-compile for the other release platforms so they can be published alongside
+	for _, platform := range platforms[1:] {
+		outputPath := release.PlatformBinaryPath(platform, outputName)
+		if err := os.MkdirAll(filepath.Dir(outputPath), 0755); err != nil {
+			return false, "", fmt.Errorf("failed to create build dir: %w", err)
+		}
+		// Never leave an older build behind to be published by mistake
+		_ = os.Remove(outputPath)
+		log(fmt.Sprintf("Cross-compiling %s for %s...", service.ID, platform))
+		if err := buildBinary(platform, outputPath, ""); err != nil {
+			return false, "", fmt.Errorf("failed to build %s for %s: %w", service.ID, platform, err)
+		}
+		log(fmt.Sprintf("✓ %s built for %s", service.ID, platform))
+	}
+
 	log("Cleaning build artifacts...")
 	log("✓ Clean complete")
 
-	return true, nil
+	return true, previous, nil
 }
@@ -0,0 +1,262 @@
+package utils
+
+import (
+	"bufio"
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+// tcpListenState is the state code of a listening socket in /proc/net/tcp
+const tcpListenState = "0A"
+
+// PortHolder is a process listening on a TCP port
+type PortHolder struct {
+	Port    string
+	PID     int    // 0 when the socket belongs to a process we cannot inspect
+	Command string // Process name from /proc/<pid>/comm
+}
+
+// String describes the holder for messages, e.g. "python3 (pid 1234)".
+func (h *PortHolder) String() string {
+	if h.PID == 0 {
+		return "a process owned by another user"
+	}
+	return fmt.Sprintf("%s (pid %d)", h.Command, h.PID)
+}
+
+// InUnit reports whether the holder runs inside the given systemd unit.
+func (h *PortHolder) InUnit(unit string) bool {
+	if h.PID == 0 || unit == "" {
+		return false
+	}
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/cgroup", h.PID))
+	if err != nil {
+		return false
+	}
+	return strings.Contains(string(data), "/"+unit)
+}
+
+// FindPortHolder returns the process listening on a TCP port, or nil if the port is free.
+// Listening sockets are read from /proc/net/tcp and /proc/net/tcp6 and matched to a process
+// through the socket links in /proc/*/fd. Where /proc is not available, it falls back to
+// trying to connect to the port on localhost.
+func FindPortHolder(port string) (*PortHolder, error) {
+	number, err := strconv.Atoi(port)
+	if err != nil || number <= 0 || number > 65535 {
+		return nil, fmt.Errorf("invalid port '%s'", port)
+	}
+
+	inodes, err := listeningSocketInodes(number)
+	if err != nil {
+		if IsPortAvailable("127.0.0.1", port) {
+			return nil, nil
+		}
+		return &PortHolder{Port: port}, nil
+	}
+	if len(inodes) == 0 {
+		return nil, nil
+	}
+
+	holder := &PortHolder{Port: port}
+	if pid := socketOwner(inodes); pid != 0 {
+		holder.PID = pid
+		holder.Command = processName(pid)
+	}
+	return holder, nil
+}
+
+// listeningSocketInodes returns the inodes of the sockets listening on a port.
+func listeningSocketInodes(port int) (map[string]bool, error) {
+	inodes := make(map[string]bool)
+	read := 0
+	for _, table := range []string{"/proc/net/tcp", "/proc/net/tcp6"} {
+		file, err := os.Open(table)
+		if err != nil {
+			continue
+		}
+		read++
+		scanner := bufio.NewScanner(file)
+		scanner.Scan() // Header
+		for scanner.Scan() {
+			// sl local_address rem_address st tx_queue:rx_queue tr:tm->when retrnsmt uid timeout inode
+			fields := strings.Fields(scanner.Text())
+			if len(fields) < 10 || fields[3] != tcpListenState {
+				continue
+			}
+			_, hexPort, ok := strings.Cut(fields[1], ":")
+			if !ok {
+				continue
+			}
+			if local, err := strconv.ParseInt(hexPort, 16, 32); err == nil && int(local) == port {
+				inodes[fields[9]] = true
+			}
+		}
+		_ = file.Close()
+	}
+	if read == 0 {
+		return nil, fmt.Errorf("/proc/net/tcp is not available")
+	}
+	return inodes, nil
+}
+
+// socketOwner finds the process holding one of the given socket inodes. Processes of other
+// users cannot be inspected; 0 is returned when none of the readable ones match.
+func socketOwner(inodes map[string]bool) int {
+	fdDirs, _ := filepath.Glob("/proc/[0-9]*/fd")
+	for _, fdDir := range fdDirs {
+		links, err := os.ReadDir(fdDir)
+		if err != nil {
+			continue
+		}
+		for _, link := range links {
+			target, err := os.Readlink(filepath.Join(fdDir, link.Name()))
+			if err != nil || !strings.HasPrefix(target, "socket:[") {
+				continue
+			}
+			if inodes[strings.TrimSuffix(strings.TrimPrefix(target, "socket:["), "]")] {
+				pid, _ := strconv.Atoi(filepath.Base(filepath.Dir(fdDir)))
+				return pid
+			}
+		}
+	}
+	return 0
+}
+
+// processName returns the command name of a process.
+func processName(pid int) string {
+	data, err := os.ReadFile(fmt.Sprintf("/proc/%d/comm", pid))
+	if err != nil {
+		return "unknown"
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// ServicePortConflict returns the process holding a service's port when it is not the
+// service itself, or nil when the port is free or the service holds it.
+func ServicePortConflict(service config.ServiceDefinition) (*PortHolder, error) {
+	if service.Port == "" {
+		return nil, nil
+	}
+	holder, err := FindPortHolder(service.Port)
+	if err != nil || holder == nil {
+		return nil, err
+	}
+	if holder.InUnit(service.SystemdName) {
+		return nil, nil
+	}
+	// A socket we cannot trace is most likely the running service itself
+	if holder.PID == 0 && IsServiceActive(service) {
+		return nil, nil
+	}
+	return holder, nil
+}
+
+// FindFreePort returns the first port above start that nothing listens on, can be bound on
+// host, and is not in reserved (ports assigned to other services).
+func FindFreePort(host string, start string, reserved map[string]string) (string, error) {
+	first, err := strconv.Atoi(start)
+	if err != nil {
+		return "", fmt.Errorf("invalid port '%s'", start)
+	}
+	for number := first + 1; number <= 65535; number++ {
+		port := strconv.Itoa(number)
+		if _, taken := reserved[port]; taken {
+			continue
+		}
+		if holder, err := FindPortHolder(port); err != nil || holder != nil {
+			continue
+		}
+		listener, err := net.Listen("tcp", net.JoinHostPort(host, port))
+		if err != nil {
+			continue
+		}
+		_ = listener.Close()
+		return port, nil
+	}
+	return "", fmt.Errorf("no free port above %s", start)
+}
+
+// ReassignServicePort moves a service to a free port: it writes the port to service-map.json
+// and regenerates the service's systemd unit if one is installed. The unit is not restarted.
+func ReassignServicePort(service config.ServiceDefinition) (config.ServiceDefinition, error) {
+	serviceMap, err := config.LoadServiceMapConfig()
+	if err != nil {
+		return service, fmt.Errorf("failed to load service-map.json: %w", err)
+	}
+
+	port, err := FindFreePort(ServiceBindHost(service), service.Port, AssignedPorts(serviceMap, service.ID))
+	if err != nil {
+		return service, err
+	}
+
+	updated := false
+	for _, entries := range serviceMap.Services {
+		for i := range entries {
+			if entries[i].ID == service.ID {
+				entries[i].Port = port
+				updated = true
+			}
+		}
+	}
+	if !updated {
+		return service, fmt.Errorf("%s is not in service-map.json", service.ShortName)
+	}
+	if err := config.SaveServiceMapConfig(serviceMap); err != nil {
+		return service, fmt.Errorf("failed to save service-map.json: %w", err)
+	}
+	service.Port = port
+
+	if unitPath, err := config.ExpandPath(service.GetSystemdPath()); err == nil && service.SystemdName != "" {
+		if _, err := os.Stat(unitPath); err == nil {
+			if err := WriteSystemdUnit(service); err != nil {
+				return service, fmt.Errorf("moved %s to port %s but failed to regenerate its unit: %w", service.ShortName, port, err)
+			}
+		}
+	}
+	return service, nil
+}
+
+// AssignedPorts returns the ports of every service in a service map except one, mapped to
+// the short name of the service using each. Built-in services without a port in the map use
+// their default port.
+func AssignedPorts(serviceMap *config.ServiceMapConfig, exceptID string) map[string]string {
+	ports := make(map[string]string)
+	for _, entries := range serviceMap.Services {
+		for _, entry := range entries {
+			if entry.ID == exceptID {
+				continue
+			}
+			def := config.GetServiceDefinition(entry.ID)
+			port := entry.Port
+			if port == "" {
+				port = def.Port
+			}
+			name := entry.ShortName
+			if name == "" {
+				name = def.ShortName
+			}
+			if name == "" {
+				name = entry.ID
+			}
+			if port != "" {
+				ports[port] = name
+			}
+		}
+	}
+	return ports
+}
+
+// ServiceBindHost is the address a service listens on, falling back to loopback for
+// hostnames.
+func ServiceBindHost(service config.ServiceDefinition) string {
+	if net.ParseIP(service.Domain) == nil {
+		return "127.0.0.1"
+	}
+	return service.Domain
+}
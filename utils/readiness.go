@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+// CheckServiceReady performs a single readiness probe against a service.
+// HTTP services must report an OK health status on their health endpoint,
+// OS services (Redis, Ollama) must accept TCP connections.
+func CheckServiceReady(service config.ServiceDefinition) error {
+	switch service.Type {
+	case "cli", "prd":
+		return nil
+	case "os":
+		conn, err := net.DialTimeout("tcp", service.GetHost(), 2*time.Second)
+		if err != nil {
+			return fmt.Errorf("%s is not accepting connections: %w", service.ShortName, err)
+		}
+		_ = conn.Close()
+		return nil
+	}
+
+	report, err := GetHTTPServiceReport(service)
+	if err != nil {
+		return err
+	}
+
+	var health struct {
+		Health struct {
+			Status string `json:"status"`
+		} `json:"health"`
+	}
+	if err := json.Unmarshal([]byte(report), &health); err != nil {
+		return fmt.Errorf("%s returned an unreadable health report: %w", service.ShortName, err)
+	}
+
+	status := strings.ToUpper(health.Health.Status)
+	if status != "OK" && status != "HEALTHY" {
+		if status == "" {
+			status = "UNKNOWN"
+		}
+		return fmt.Errorf("%s reports health status %s", service.ShortName, status)
+	}
+	return nil
+}
+
+// WaitForServiceReady polls CheckServiceReady until it passes, the timeout elapses
+// or the context is cancelled. The last probe error is returned on failure.
+func WaitForServiceReady(ctx context.Context, service config.ServiceDefinition, timeout time.Duration) error {
+	deadline := time.Now().Add(timeout)
+	for {
+		err := CheckServiceReady(service)
+		if err == nil {
+			return nil
+		}
+		if time.Now().After(deadline) {
+			return fmt.Errorf("not ready after %s: %w", timeout, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(500 * time.Millisecond):
+		}
+	}
+}
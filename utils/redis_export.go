@@ -0,0 +1,342 @@
+package utils
+
+import (
+	"bufio"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+	"unicode/utf8"
+
+	"github.com/EasterCompany/dex-cli/cache"
+	"github.com/redis/go-redis/v9"
+)
+
+// RedisExportEntry is one line of a portable Redis export. Values are stored by type:
+// strings as a string, lists and sets as []string, hashes as map[string]string and
+// sorted sets as []RedisZMember. Binary data is base64 encoded and flagged in Encoding.
+type RedisExportEntry struct {
+	Key      string          `json:"key"`
+	Type     string          `json:"type"`
+	TTLMs    int64           `json:"ttl_ms,omitempty"`
+	Encoding string          `json:"encoding,omitempty"`
+	Value    json.RawMessage `json:"value"`
+}
+
+// RedisZMember is a single sorted set member in an export.
+type RedisZMember struct {
+	Member string  `json:"member"`
+	Score  float64 `json:"score"`
+}
+
+// RedisTransferStats summarises an export or import.
+type RedisTransferStats struct {
+	Keys    int
+	Skipped int
+	ByType  map[string]int
+	// Unsupported lists keys whose type cannot be exported (e.g., streams), as "key (type)"
+	Unsupported []string
+}
+
+func newRedisTransferStats() *RedisTransferStats {
+	return &RedisTransferStats{ByType: make(map[string]int)}
+}
+
+// ExportRedisKeys streams every key matching the given prefixes to w as NDJSON.
+// Keys are found with SCAN so large keyspaces are never loaded at once.
+// With dryRun set, keys are counted but nothing is written.
+func ExportRedisKeys(ctx context.Context, client *redis.Client, prefixes []string, w io.Writer, dryRun bool) (*RedisTransferStats, error) {
+	stats := newRedisTransferStats()
+	writer := bufio.NewWriter(w)
+	enc := json.NewEncoder(writer)
+	seen := make(map[string]bool)
+
+	for _, prefix := range prefixes {
+		err := cache.ScanKeys(ctx, client, cache.PrefixPattern(prefix), func(keys []string) error {
+			for _, key := range keys {
+				if seen[key] {
+					continue // Overlapping prefixes, e.g. "event:" and "events:"
+				}
+				seen[key] = true
+
+				entry, keyType, err := readRedisEntry(ctx, client, key)
+				if err == redis.Nil {
+					continue // Expired between SCAN and read
+				}
+				if err != nil {
+					return err
+				}
+				if entry == nil {
+					stats.Unsupported = append(stats.Unsupported, fmt.Sprintf("%s (%s)", key, keyType))
+					continue
+				}
+
+				stats.Keys++
+				stats.ByType[entry.Type]++
+				if dryRun {
+					continue
+				}
+				if err := enc.Encode(entry); err != nil {
+					return err
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return stats, err
+		}
+	}
+	return stats, writer.Flush()
+}
+
+// readRedisEntry reads a key and its TTL into an export entry, and returns the key's type.
+// It returns a nil entry for types that cannot be exported.
+func readRedisEntry(ctx context.Context, client *redis.Client, key string) (*RedisExportEntry, string, error) {
+	keyType, err := client.Type(ctx, key).Result()
+	if err != nil {
+		return nil, "", err
+	}
+
+	var value interface{}
+	var strs []string
+	switch keyType {
+	case "none":
+		return nil, keyType, redis.Nil
+	case "string":
+		s, err := client.Get(ctx, key).Result()
+		if err != nil {
+			return nil, keyType, err
+		}
+		value, strs = s, []string{s}
+	case "list":
+		items, err := client.LRange(ctx, key, 0, -1).Result()
+		if err != nil {
+			return nil, keyType, err
+		}
+		value, strs = items, items
+	case "set":
+		members, err := client.SMembers(ctx, key).Result()
+		if err != nil {
+			return nil, keyType, err
+		}
+		value, strs = members, members
+	case "hash":
+		fields, err := client.HGetAll(ctx, key).Result()
+		if err != nil {
+			return nil, keyType, err
+		}
+		for k, v := range fields {
+			strs = append(strs, k, v)
+		}
+		value = fields
+	case "zset":
+		zs, err := client.ZRangeWithScores(ctx, key, 0, -1).Result()
+		if err != nil {
+			return nil, keyType, err
+		}
+		members := make([]RedisZMember, 0, len(zs))
+		for _, z := range zs {
+			member := fmt.Sprint(z.Member)
+			members = append(members, RedisZMember{Member: member, Score: z.Score})
+			strs = append(strs, member)
+		}
+		value = members
+	default:
+		return nil, keyType, nil
+	}
+
+	entry := &RedisExportEntry{Key: key, Type: keyType}
+	for _, s := range strs {
+		if !utf8.ValidString(s) {
+			entry.Encoding = "base64"
+			value = encodeRedisValue(value)
+			break
+		}
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return nil, keyType, err
+	}
+	entry.Value = raw
+
+	ttl, err := client.PTTL(ctx, key).Result()
+	if err != nil {
+		return nil, keyType, err
+	}
+	if ttl > 0 {
+		entry.TTLMs = ttl.Milliseconds()
+	}
+	return entry, keyType, nil
+}
+
+// encodeRedisValue base64 encodes every string inside an exported value.
+func encodeRedisValue(value interface{}) interface{} {
+	enc := func(s string) string { return base64.StdEncoding.EncodeToString([]byte(s)) }
+	switch v := value.(type) {
+	case string:
+		return enc(v)
+	case []string:
+		out := make([]string, len(v))
+		for i, s := range v {
+			out[i] = enc(s)
+		}
+		return out
+	case map[string]string:
+		out := make(map[string]string, len(v))
+		for k, s := range v {
+			out[enc(k)] = enc(s)
+		}
+		return out
+	case []RedisZMember:
+		out := make([]RedisZMember, len(v))
+		for i, z := range v {
+			out[i] = RedisZMember{Member: enc(z.Member), Score: z.Score}
+		}
+		return out
+	}
+	return value
+}
+
+// ImportRedisKeys loads an NDJSON export written by ExportRedisKeys. Existing keys are
+// skipped unless replace is set. With dryRun set, the file is validated but nothing is written.
+func ImportRedisKeys(ctx context.Context, client *redis.Client, r io.Reader, replace, dryRun bool) (*RedisTransferStats, error) {
+	stats := newRedisTransferStats()
+	dec := json.NewDecoder(bufio.NewReader(r))
+
+	for line := 1; dec.More(); line++ {
+		var entry RedisExportEntry
+		if err := dec.Decode(&entry); err != nil {
+			return stats, fmt.Errorf("invalid export at entry %d: %w", line, err)
+		}
+		if entry.Key == "" {
+			return stats, fmt.Errorf("invalid export at entry %d: missing key", line)
+		}
+
+		if !replace {
+			exists, err := client.Exists(ctx, entry.Key).Result()
+			if err != nil {
+				return stats, err
+			}
+			if exists > 0 {
+				stats.Skipped++
+				continue
+			}
+		}
+
+		pipe := client.TxPipeline()
+		pipe.Del(ctx, entry.Key)
+		if err := writeRedisEntry(ctx, pipe, &entry); err != nil {
+			return stats, fmt.Errorf("invalid export at entry %d (%s): %w", line, entry.Key, err)
+		}
+		if entry.TTLMs > 0 {
+			pipe.PExpire(ctx, entry.Key, time.Duration(entry.TTLMs)*time.Millisecond)
+		}
+
+		stats.Keys++
+		stats.ByType[entry.Type]++
+		if dryRun {
+			pipe.Discard()
+			continue
+		}
+		if _, err := pipe.Exec(ctx); err != nil {
+			return stats, fmt.Errorf("failed to import key %s: %w", entry.Key, err)
+		}
+	}
+	return stats, nil
+}
+
+// writeRedisEntry queues the commands that recreate an exported key.
+func writeRedisEntry(ctx context.Context, pipe redis.Pipeliner, entry *RedisExportEntry) error {
+	decode := func(s string) (string, error) {
+		if entry.Encoding != "base64" {
+			return s, nil
+		}
+		b, err := base64.StdEncoding.DecodeString(s)
+		return string(b), err
+	}
+	decodeAll := func(items []string) ([]interface{}, error) {
+		out := make([]interface{}, 0, len(items))
+		for _, item := range items {
+			s, err := decode(item)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, s)
+		}
+		return out, nil
+	}
+
+	switch entry.Type {
+	case "string":
+		var v string
+		if err := json.Unmarshal(entry.Value, &v); err != nil {
+			return err
+		}
+		s, err := decode(v)
+		if err != nil {
+			return err
+		}
+		pipe.Set(ctx, entry.Key, s, 0)
+	case "list", "set":
+		var v []string
+		if err := json.Unmarshal(entry.Value, &v); err != nil {
+			return err
+		}
+		items, err := decodeAll(v)
+		if err != nil {
+			return err
+		}
+		if len(items) == 0 {
+			return nil
+		}
+		if entry.Type == "list" {
+			pipe.RPush(ctx, entry.Key, items...)
+		} else {
+			pipe.SAdd(ctx, entry.Key, items...)
+		}
+	case "hash":
+		var v map[string]string
+		if err := json.Unmarshal(entry.Value, &v); err != nil {
+			return err
+		}
+		fields := make([]interface{}, 0, len(v)*2)
+		for k, s := range v {
+			field, err := decode(k)
+			if err != nil {
+				return err
+			}
+			val, err := decode(s)
+			if err != nil {
+				return err
+			}
+			fields = append(fields, field, val)
+		}
+		if len(fields) == 0 {
+			return nil
+		}
+		pipe.HSet(ctx, entry.Key, fields...)
+	case "zset":
+		var v []RedisZMember
+		if err := json.Unmarshal(entry.Value, &v); err != nil {
+			return err
+		}
+		members := make([]redis.Z, 0, len(v))
+		for _, z := range v {
+			member, err := decode(z.Member)
+			if err != nil {
+				return err
+			}
+			members = append(members, redis.Z{Score: z.Score, Member: member})
+		}
+		if len(members) == 0 {
+			return nil
+		}
+		pipe.ZAdd(ctx, entry.Key, members...)
+	default:
+		return fmt.Errorf("unsupported type %q", entry.Type)
+	}
+	return nil
+}
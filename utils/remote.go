@@ -0,0 +1,104 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/remote"
+	"github.com/EasterCompany/dex-cli/ui"
+)
+
+// RemoteHost is a server from server-map.json that dex can run commands on.
+type RemoteHost struct {
+	Name string
+	config.Server
+}
+
+// Address returns the first address dex tries when connecting to the host.
+func (h RemoteHost) Address() string {
+	if addresses := remote.Addresses(h.Server); len(addresses) > 0 {
+		return addresses[0]
+	}
+	return ""
+}
+
+// LoadRemoteHosts returns every server in server-map.json, sorted by name.
+func LoadRemoteHosts() ([]RemoteHost, error) {
+	serverMap, err := config.LoadServerMapConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server-map.json: %w", err)
+	}
+	hosts := make([]RemoteHost, 0, len(serverMap.Servers))
+	for name, server := range serverMap.Servers {
+		hosts = append(hosts, RemoteHost{Name: name, Server: server})
+	}
+	sort.Slice(hosts, func(i, j int) bool { return hosts[i].Name < hosts[j].Name })
+	return hosts, nil
+}
+
+// FindRemoteHost looks up a server in server-map.json by name.
+func FindRemoteHost(name string) (RemoteHost, error) {
+	hosts, err := LoadRemoteHosts()
+	if err != nil {
+		return RemoteHost{}, err
+	}
+	for _, host := range hosts {
+		if host.Name == name {
+			return host, nil
+		}
+	}
+	return RemoteHost{}, fmt.Errorf("host '%s' not found in server-map.json", name)
+}
+
+// DialRemoteHost opens an SSH connection to a host, pinning its key on first use.
+func DialRemoteHost(ctx context.Context, host RemoteHost) (*remote.Client, error) {
+	return remote.Dial(ctx, host.Name, host.Server, remote.Options{
+		OnPin: func(address, fingerprint string) {
+			ui.PrintWarning(fmt.Sprintf("Pinned new host key for %s (%s): %s", host.Name, address, fingerprint))
+		},
+	})
+}
+
+// RunRemoteDex runs 'dex <args>' on a remote host over SSH, streaming its output to stdout and
+// stderr. With tty set a terminal is allocated, so colors, prompts and Ctrl+C behave as they
+// would locally.
+func RunRemoteDex(ctx context.Context, host RemoteHost, args []string, stdout, stderr io.Writer, tty bool) error {
+	client, err := DialRemoteHost(ctx, host)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = client.Close() }()
+
+	var stdin io.Reader
+	if tty {
+		stdin = os.Stdin
+	}
+	if err := client.Run(ctx, remoteCommandLine(args, tty), stdin, stdout, stderr, tty); err != nil {
+		return fmt.Errorf("'dex %s' failed on %s: %w", strings.Join(args, " "), host.Name, err)
+	}
+	return nil
+}
+
+// remoteCommandLine quotes args for the remote shell. The remote login shell may not have
+// ~/Dexter/bin on its PATH, so dex is invoked through a login shell. Without a terminal the
+// output is meant for a program, so colors are turned off.
+func remoteCommandLine(args []string, tty bool) string {
+	quoted := []string{"dex"}
+	if !tty {
+		quoted = []string{"NO_COLOR=1", "dex"}
+	}
+	for _, arg := range args {
+		quoted = append(quoted, shellQuote(arg))
+	}
+	return "bash -lc " + shellQuote(strings.Join(quoted, " "))
+}
+
+// shellQuote wraps s in single quotes, escaping any single quotes it contains.
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
@@ -0,0 +1,290 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+	"github.com/EasterCompany/dex-cli/git"
+)
+
+const (
+	// RollbackStore holds previous binaries as ~/Dexter/rollback/<binary>/<version>/<binary>
+	RollbackStore = "~/Dexter/rollback"
+	// RollbackKeep is the number of previous binaries kept for each service
+	RollbackKeep = 3
+	// RollbackHealthTimeout bounds how long a freshly installed binary has to report healthy
+	RollbackHealthTimeout = 30 * time.Second
+)
+
+// healthTimeout is RollbackHealthTimeout, shortened by tests
+var healthTimeout = RollbackHealthTimeout
+
+// restartUnit restarts a service's systemd user unit; tests replace it
+var restartUnit = func(service config.ServiceDefinition) error {
+	if output, err := exec.Command("systemctl", "--user", "restart", service.SystemdName).CombinedOutput(); err != nil {
+		return fmt.Errorf("failed to restart %s: %s", service.ShortName, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// RollbackEntry is a previous binary kept in the rollback store.
+type RollbackEntry struct {
+	Version  string
+	Path     string
+	StoredAt time.Time
+}
+
+// installedBinaryPath returns the absolute path of a service's binary in ~/Dexter/bin.
+func installedBinaryPath(service config.ServiceDefinition) string {
+	path, err := config.ExpandPath(service.GetBinaryPath())
+	if err != nil {
+		return service.GetBinaryPath()
+	}
+	return path
+}
+
+func rollbackDir(service config.ServiceDefinition) (string, error) {
+	root, err := config.ExpandPath(RollbackStore)
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(root, filepath.Base(service.GetBinaryPath())), nil
+}
+
+// SnapshotBinary copies the currently installed binary into the rollback store before it is
+// replaced, then prunes the store down to RollbackKeep entries. It returns the version that
+// was stored, or "" if there was no binary to keep.
+func SnapshotBinary(service config.ServiceDefinition) (string, error) {
+	binPath := installedBinaryPath(service)
+	if _, err := os.Stat(binPath); os.IsNotExist(err) {
+		return "", nil
+	}
+
+	version := installedBinaryVersion(service)
+	if version == "" {
+		version = fmt.Sprintf("unknown-%s", time.Now().Format("20060102150405"))
+	}
+
+	dir, err := rollbackDir(service)
+	if err != nil {
+		return "", err
+	}
+	versionDir := filepath.Join(dir, version)
+	if err := os.MkdirAll(versionDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create rollback directory: %w", err)
+	}
+	if err := copyExecutable(binPath, filepath.Join(versionDir, filepath.Base(service.GetBinaryPath()))); err != nil {
+		return "", fmt.Errorf("failed to store %s %s for rollback: %w", service.ShortName, version, err)
+	}
+	// The directory's mtime orders the store, so refresh it when a version is stored again
+	now := time.Now()
+	_ = os.Chtimes(versionDir, now, now)
+
+	entries, err := ListRollbackVersions(service)
+	if err != nil {
+		return version, err
+	}
+	for _, entry := range entries[min(RollbackKeep, len(entries)):] {
+		_ = os.RemoveAll(filepath.Dir(entry.Path))
+	}
+	return version, nil
+}
+
+// installedBinaryVersion returns the short version of the installed binary, or "" if unknown.
+func installedBinaryVersion(service config.ServiceDefinition) string {
+	output, err := exec.Command(installedBinaryPath(service), "version").Output()
+	if err != nil {
+		return ""
+	}
+	parsed, err := git.Parse(strings.TrimSpace(string(output)))
+	if err != nil {
+		return ""
+	}
+	return parsed.Short()
+}
+
+// ListRollbackVersions returns the binaries kept for a service, newest first.
+func ListRollbackVersions(service config.ServiceDefinition) ([]RollbackEntry, error) {
+	dir, err := rollbackDir(service)
+	if err != nil {
+		return nil, err
+	}
+	dirEntries, err := os.ReadDir(dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rollback store: %w", err)
+	}
+
+	var entries []RollbackEntry
+	for _, d := range dirEntries {
+		if !d.IsDir() {
+			continue
+		}
+		binPath := filepath.Join(dir, d.Name(), filepath.Base(service.GetBinaryPath()))
+		if _, err := os.Stat(binPath); err != nil {
+			continue
+		}
+		info, err := d.Info()
+		if err != nil {
+			continue
+		}
+		entries = append(entries, RollbackEntry{Version: d.Name(), Path: binPath, StoredAt: info.ModTime()})
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].StoredAt.After(entries[j].StoredAt)
+	})
+	return entries, nil
+}
+
+// RestoreBinary installs a version from the rollback store. An empty version restores the newest
+// stored version that differs from the installed one. The current binary is snapshotted first,
+// so a rollback can itself be rolled back.
+func RestoreBinary(service config.ServiceDefinition, version string) (string, error) {
+	entries, err := ListRollbackVersions(service)
+	if err != nil {
+		return "", err
+	}
+	if len(entries) == 0 {
+		return "", fmt.Errorf("no previous versions of %s are stored", service.ShortName)
+	}
+
+	var target RollbackEntry
+	if version == "" {
+		current := installedBinaryVersion(service)
+		for _, entry := range entries {
+			if entry.Version != current {
+				target = entry
+				break
+			}
+		}
+		if target.Path == "" {
+			return "", fmt.Errorf("no stored version of %s differs from the installed %s", service.ShortName, current)
+		}
+	} else {
+		found := false
+		for _, entry := range entries {
+			if entry.Version == strings.TrimPrefix(version, "v") {
+				target = entry
+				found = true
+				break
+			}
+		}
+		if !found {
+			var available []string
+			for _, entry := range entries {
+				available = append(available, entry.Version)
+			}
+			return "", fmt.Errorf("version %s of %s is not stored (available: %s)", version, service.ShortName, strings.Join(available, ", "))
+		}
+	}
+
+	// Read the target before snapshotting, which may prune it from the store
+	data, err := os.ReadFile(target.Path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read stored binary: %w", err)
+	}
+	if _, err := SnapshotBinary(service); err != nil {
+		return "", err
+	}
+	if err := writeExecutable(installedBinaryPath(service), data); err != nil {
+		return "", fmt.Errorf("failed to install %s %s: %w", service.ShortName, target.Version, err)
+	}
+	return target.Version, nil
+}
+
+// RestartAndVerify restarts a service's systemd unit and waits for it to report healthy.
+// Services without a unit (like the CLI) have nothing to restart and pass immediately.
+func RestartAndVerify(ctx context.Context, service config.ServiceDefinition) error {
+	if service.SystemdName == "" {
+		return nil
+	}
+	if err := restartUnit(service); err != nil {
+		return err
+	}
+	return WaitForServiceReady(ctx, service, healthTimeout)
+}
+
+// RestartOrRollback restarts a service onto a freshly installed binary and verifies it with
+// VerifyOrRollback, restoring previousVersion if it does not come up healthy.
+func RestartOrRollback(ctx context.Context, service config.ServiceDefinition, previousVersion string) error {
+	if service.SystemdName == "" {
+		return nil
+	}
+	if err := restartUnit(service); err != nil {
+		return err
+	}
+	return VerifyOrRollback(ctx, service, previousVersion)
+}
+
+// VerifyOrRollback waits for a freshly installed (and already restarted) service to report
+// healthy. If it does not and a previous version was stored, that version is restored and
+// restarted. The returned error describes the failed health check and the rollback outcome.
+func VerifyOrRollback(ctx context.Context, service config.ServiceDefinition, previousVersion string) error {
+	if service.SystemdName == "" {
+		return nil
+	}
+	healthErr := WaitForServiceReady(ctx, service, healthTimeout)
+	if healthErr == nil {
+		return nil
+	}
+	if previousVersion == "" {
+		return fmt.Errorf("%s failed its health check and no previous version is stored: %w", service.ShortName, healthErr)
+	}
+
+	restored, err := RestoreBinary(service, previousVersion)
+	if err != nil {
+		return fmt.Errorf("%s failed its health check (%v) and rollback failed: %w", service.ShortName, healthErr, err)
+	}
+	if err := RestartAndVerify(ctx, service); err != nil {
+		return fmt.Errorf("%s failed its health check (%v); rolled back to %s but it is still unhealthy: %w", service.ShortName, healthErr, restored, err)
+	}
+	return fmt.Errorf("%s failed its health check (%v); rolled back to %s", service.ShortName, healthErr, restored)
+}
+
+// IsServiceActive checks if a service's systemd user unit is currently running.
+func IsServiceActive(service config.ServiceDefinition) bool {
+	if service.SystemdName == "" {
+		return false
+	}
+	return exec.Command("systemctl", "--user", "is-active", "--quiet", service.SystemdName).Run() == nil
+}
+
+// copyExecutable copies a binary, preserving the executable bit.
+func copyExecutable(src, dst string) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = in.Close() }()
+
+	data, err := io.ReadAll(in)
+	if err != nil {
+		return err
+	}
+	return writeExecutable(dst, data)
+}
+
+// writeExecutable writes a binary next to its destination and renames it into place,
+// so a running process never sees a half-written file.
+func writeExecutable(dst string, data []byte) error {
+	tmp := dst + ".tmp"
+	if err := os.WriteFile(tmp, data, 0755); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp, dst); err != nil {
+		_ = os.Remove(tmp)
+		return err
+	}
+	return nil
+}
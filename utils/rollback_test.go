@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+// fakeBinary is a script that reports a version like a dex service binary does.
+func fakeBinary(version string) []byte {
+	return []byte(fmt.Sprintf("#!/bin/sh\necho %s.main.abc1234.2026-01-02-03-04-05.linux-amd64\n", version))
+}
+
+func TestRestartOrRollbackRestoresSnapshot(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+	originalRestart := restartUnit
+	healthTimeout = time.Second
+	restarts := 0
+	restartUnit = func(config.ServiceDefinition) error {
+		restarts++
+		return nil
+	}
+	defer func() {
+		healthTimeout = RollbackHealthTimeout
+		restartUnit = originalRestart
+	}()
+
+	// The service is healthy only while the old binary is installed
+	service := config.ServiceDefinition{ID: "dex-fake-service", ShortName: "fake", Type: "be", SystemdName: "dex-fake-service.service"}
+	binPath := installedBinaryPath(service)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		status := "DEGRADED"
+		if installed, _ := os.ReadFile(binPath); strings.Contains(string(installed), "echo 1.0.0.") {
+			status = "OK"
+		}
+		_, _ = fmt.Fprintf(w, `{"health": {"status": %q}}`, status)
+	}))
+	defer server.Close()
+	host, port, _ := net.SplitHostPort(strings.TrimPrefix(server.URL, "http://"))
+	service.Domain, service.Port = host, port
+
+	if err := os.MkdirAll(filepath.Dir(binPath), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(binPath, fakeBinary("1.0.0"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	previous, err := SnapshotBinary(service)
+	if err != nil || previous != "1.0.0" {
+		t.Fatalf("SnapshotBinary() = %q, %v, want 1.0.0", previous, err)
+	}
+	if err := writeExecutable(binPath, fakeBinary("1.1.0")); err != nil {
+		t.Fatal(err)
+	}
+
+	err = RestartOrRollback(context.Background(), service, previous)
+	if err == nil || !strings.Contains(err.Error(), "rolled back to 1.0.0") || strings.Contains(err.Error(), "still unhealthy") {
+		t.Fatalf("RestartOrRollback() error = %v, want a rollback to 1.0.0", err)
+	}
+	if installed, _ := os.ReadFile(binPath); string(installed) != string(fakeBinary("1.0.0")) {
+		t.Errorf("installed binary = %q, want the 1.0.0 snapshot", installed)
+	}
+	if restarts != 2 {
+		t.Errorf("unit restarted %d times, want 2 (new binary, then rollback)", restarts)
+	}
+}
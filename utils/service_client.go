@@ -15,7 +15,7 @@ import (
 // GetHTTPVersion fetches the version from a service's HTTP endpoint.
 func GetHTTPVersion(service config.ServiceDefinition) (string, error) {
 	// Append ?format=version to get the raw version string
-	url := service.GetHTTP("/service") + "?format=version"
+	url := service.GetHTTP(service.GetHealthPath()) + "?format=version"
 	body, statusCode, err := GetHTTPBody(url)
 	if err != nil {
 		return "N/A", fmt.Errorf("failed to connect to %s: %w", service.ShortName, err)
@@ -30,9 +30,9 @@ func GetHTTPVersion(service config.ServiceDefinition) (string, error) {
 	return status, nil
 }
 
-// GetHTTPServiceReport retrieves the full JSON service report from /service endpoint
+// GetHTTPServiceReport retrieves the full JSON service report from the service's health endpoint
 func GetHTTPServiceReport(service config.ServiceDefinition) (string, error) {
-	url := service.GetHTTP("/service")
+	url := service.GetHTTP(service.GetHealthPath())
 	body, statusCode, err := GetHTTPBody(url)
 	if err != nil {
 		return "", fmt.Errorf("failed to connect to %s: %w", service.ShortName, err)
@@ -7,44 +7,251 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
+	"strings"
 	"text/template"
 	"time"
+	"unicode"
 
 	"github.com/EasterCompany/dex-cli/config"
 )
 
-// InstallSystemdService installs a systemd service for the given definition.
-// It handles standard binaries and special cases like python scripts or static sites.
+// InstallSystemdService installs a systemd service for the given definition, then enables
+// and restarts it.
 func InstallSystemdService(service config.ServiceDefinition) error {
-	systemdDir := os.ExpandEnv("$HOME/.config/systemd/user")
-	if err := os.MkdirAll(systemdDir, 0755); err != nil {
-		return fmt.Errorf("failed to create systemd directory: %w", err)
+	if err := WriteSystemdUnit(service); err != nil {
+		return err
 	}
 
-	servicePath := filepath.Join(systemdDir, service.SystemdName)
+	// Enable service
+	if err := exec.Command("systemctl", "--user", "enable", service.SystemdName).Run(); err != nil {
+		return fmt.Errorf("failed to enable service: %w", err)
+	}
+
+	// Restart service
+	if err := exec.Command("systemctl", "--user", "restart", service.SystemdName).Run(); err != nil {
+		return fmt.Errorf("failed to restart service: %w", err)
+	}
+
+	return nil
+}
+
+// systemdUnitTemplate is the unit dex generates for every service. Per-service settings go
+// in a drop-in file rather than here.
+const systemdUnitTemplate = `[Unit]
+Description={{.Description}}
+After=network.target
+
+[Service]
+Type=simple
+ExecStart={{.ExecStart}}
+WorkingDirectory={{.WorkingDir}}
+Restart=always
+RestartSec=5
+StandardOutput=append:{{.LogPath}}
+StandardError=append:{{.LogPath}}
+
+[Install]
+WantedBy=default.target
+`
+
+// systemdDropInName is the drop-in file dex writes the 'unit' settings of service-map.json to
+const systemdDropInName = "dex-overrides.conf"
+
+// SystemdUnitPath returns the path of a service's generated user unit.
+func SystemdUnitPath(service config.ServiceDefinition) string {
+	return filepath.Join(os.ExpandEnv("$HOME/.config/systemd/user"), service.SystemdName)
+}
+
+// SystemdDropInPath returns the path of the drop-in file holding a service's unit settings.
+func SystemdDropInPath(service config.ServiceDefinition) string {
+	return filepath.Join(os.ExpandEnv("$HOME/.config/systemd/user"), service.SystemdName+".d", systemdDropInName)
+}
 
-	// Prepare template data
-	type ServiceData struct {
+// RenderSystemdUnit returns the unit dex would generate for a service.
+func RenderSystemdUnit(service config.ServiceDefinition) (string, error) {
+	data := struct {
 		Description string
 		ExecStart   string
 		WorkingDir  string
-		Environment string
 		LogPath     string
-	}
-
-	data := ServiceData{
+	}{
 		Description: fmt.Sprintf("Dexter Service: %s", service.ShortName),
 		LogPath:     fmt.Sprintf("%%h/Dexter/logs/%s.log", service.ID),
 	}
+	data.ExecStart, data.WorkingDir = SystemdExecStart(service)
+
+	var unit strings.Builder
+	t := template.Must(template.New("service").Parse(systemdUnitTemplate))
+	if err := t.Execute(&unit, data); err != nil {
+		return "", fmt.Errorf("failed to render service template: %w", err)
+	}
+	return unit.String(), nil
+}
+
+// RenderSystemdDropIn returns the drop-in file for a service's unit settings, or "" when it
+// has none.
+func RenderSystemdDropIn(service config.ServiceDefinition) string {
+	u := service.Unit
+	if u == nil {
+		return ""
+	}
+
+	var unitSection, serviceSection []string
+	set := func(section *[]string, key, value string) {
+		if value != "" {
+			*section = append(*section, key+"="+value)
+		}
+	}
+	setInt := func(section *[]string, key string, value int) {
+		if value > 0 {
+			*section = append(*section, fmt.Sprintf("%s=%d", key, value))
+		}
+	}
+	setOptionalInt := func(section *[]string, key string, value *int) {
+		if value != nil {
+			*section = append(*section, fmt.Sprintf("%s=%d", key, *value))
+		}
+	}
+
+	setInt(&unitSection, "StartLimitBurst", u.StartLimitBurst)
+	setOptionalInt(&unitSection, "StartLimitIntervalSec", u.StartLimitIntervalSec)
+
+	keys := make([]string, 0, len(u.Environment))
+	for key := range u.Environment {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		set(&serviceSection, "Environment", systemdQuote(key+"="+u.Environment[key]))
+	}
+	if u.EnvironmentFile != "" {
+		optional := strings.HasPrefix(u.EnvironmentFile, "-")
+		path, _ := config.ExpandPath(strings.TrimPrefix(u.EnvironmentFile, "-"))
+		if optional {
+			path = "-" + path
+		}
+		set(&serviceSection, "EnvironmentFile", path)
+	}
+	set(&serviceSection, "MemoryMax", u.MemoryMax)
+	set(&serviceSection, "CPUQuota", u.CPUQuota)
+	if u.Nice != nil {
+		serviceSection = append(serviceSection, fmt.Sprintf("Nice=%d", *u.Nice))
+	}
+	set(&serviceSection, "Restart", u.Restart)
+	setOptionalInt(&serviceSection, "RestartSec", u.RestartSec)
+	setInt(&serviceSection, "RestartSteps", u.RestartSteps)
+	setInt(&serviceSection, "RestartMaxDelaySec", u.RestartMaxDelaySec)
+	set(&serviceSection, "ProtectSystem", u.ProtectSystem)
+	set(&serviceSection, "ProtectHome", u.ProtectHome)
+	for _, path := range u.ReadWritePaths {
+		expanded, _ := config.ExpandPath(path)
+		set(&serviceSection, "ReadWritePaths", expanded)
+	}
+	if u.PrivateTmp {
+		serviceSection = append(serviceSection, "PrivateTmp=true")
+	}
+	if u.NoNewPrivileges {
+		serviceSection = append(serviceSection, "NoNewPrivileges=true")
+	}
+
+	if len(unitSection) == 0 && len(serviceSection) == 0 {
+		return ""
+	}
+	var dropIn strings.Builder
+	dropIn.WriteString("# Generated by dex from the 'unit' settings in service-map.json\n")
+	if len(unitSection) > 0 {
+		dropIn.WriteString("[Unit]\n" + strings.Join(unitSection, "\n") + "\n")
+	}
+	if len(serviceSection) > 0 {
+		if len(unitSection) > 0 {
+			dropIn.WriteString("\n")
+		}
+		dropIn.WriteString("[Service]\n" + strings.Join(serviceSection, "\n") + "\n")
+	}
+	return dropIn.String()
+}
+
+// systemdQuote quotes a value for a unit file, escaping quotes, backslashes and specifiers, and
+// control characters so a value can never end the line and start a directive of its own.
+func systemdQuote(value string) string {
+	var quoted strings.Builder
+	quoted.WriteByte('"')
+	for _, r := range value {
+		switch {
+		case r == '\\' || r == '"':
+			quoted.WriteRune('\\')
+			quoted.WriteRune(r)
+		case r == '%':
+			quoted.WriteString("%%")
+		case r == '\n':
+			quoted.WriteString(`\n`)
+		case r == '\r':
+			quoted.WriteString(`\r`)
+		case r == '\t':
+			quoted.WriteString(`\t`)
+		case unicode.IsControl(r):
+			for _, b := range []byte(string(r)) {
+				quoted.WriteString(fmt.Sprintf(`\x%02x`, b))
+			}
+		default:
+			quoted.WriteRune(r)
+		}
+	}
+	quoted.WriteByte('"')
+	return quoted.String()
+}
 
+// WriteSystemdUnit (re)generates the systemd unit for the given definition and its drop-in
+// file, and reloads systemd, without touching the running service.
+// It handles standard binaries and special cases like python scripts or static sites.
+func WriteSystemdUnit(service config.ServiceDefinition) error {
+	unitPath := SystemdUnitPath(service)
+	if err := os.MkdirAll(filepath.Dir(unitPath), 0755); err != nil {
+		return fmt.Errorf("failed to create systemd directory: %w", err)
+	}
+
+	unit, err := RenderSystemdUnit(service)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(unitPath, []byte(unit), 0644); err != nil {
+		return fmt.Errorf("failed to create service file: %w", err)
+	}
+
+	// Settings removed from service-map.json must not linger in an old drop-in
+	dropInPath := SystemdDropInPath(service)
+	if dropIn := RenderSystemdDropIn(service); dropIn != "" {
+		if err := os.MkdirAll(filepath.Dir(dropInPath), 0755); err != nil {
+			return fmt.Errorf("failed to create drop-in directory: %w", err)
+		}
+		if err := os.WriteFile(dropInPath, []byte(dropIn), 0644); err != nil {
+			return fmt.Errorf("failed to write drop-in file: %w", err)
+		}
+	} else if err := os.Remove(dropInPath); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("failed to remove drop-in file: %w", err)
+	} else {
+		_ = os.Remove(filepath.Dir(dropInPath)) // Only succeeds when nothing else is in it
+	}
+
+	// Reload systemd
+	if err := exec.Command("systemctl", "--user", "daemon-reload").Run(); err != nil {
+		return fmt.Errorf("failed to reload systemd daemon: %w", err)
+	}
+
+	return nil
+}
+
+// SystemdExecStart resolves the command a service's systemd unit runs and its working directory.
+func SystemdExecStart(service config.ServiceDefinition) (execStart, workingDir string) {
 	// Set WorkingDir to the service's source path if available
 	if service.Source != "" {
 		expandedSourcePath, err := config.ExpandPath(service.Source)
 		if err == nil {
-			data.WorkingDir = expandedSourcePath
+			workingDir = expandedSourcePath
 		}
 	} else {
-		data.WorkingDir = os.ExpandEnv("$HOME") // Fallback for services without a source dir
+		workingDir = os.ExpandEnv("$HOME") // Fallback for services without a source dir
 	}
 
 	// Determine ExecStart based on service type
@@ -55,7 +262,7 @@ func InstallSystemdService(service config.ServiceDefinition) error {
 
 		// Serve directly from source root (GitHub Pages style)
 		// We use --no-event to prevent the static server from spamming logs with access events
-		data.ExecStart = fmt.Sprintf("%s --no-event serve --dir %s --port %s", dexPath, sourcePath, service.Port)
+		execStart = fmt.Sprintf("%s --no-event serve --dir %s --port %s", dexPath, sourcePath, service.Port)
 
 	case "be": // Backend (Python or other)
 		sourcePath, _ := config.ExpandPath(service.Source)
@@ -63,72 +270,24 @@ func InstallSystemdService(service config.ServiceDefinition) error {
 
 		// Check for built binary first (Go wrapper or standard binary)
 		if _, err := os.Stat(binaryPath); err == nil {
-			data.ExecStart = binaryPath
+			execStart = binaryPath
 		} else {
 			// Check for run.sh as fallback
 			runScript := filepath.Join(sourcePath, "run.sh")
 			if _, err := os.Stat(runScript); err == nil {
-				data.ExecStart = runScript
-				data.WorkingDir = sourcePath
+				execStart = runScript
+				workingDir = sourcePath
 			} else {
 				// Final fallback
-				data.ExecStart = binaryPath
+				execStart = binaryPath
 			}
 		}
 
 	default: // "cs", "th" etc - usually Go binaries
 		binaryPath := filepath.Join(os.ExpandEnv("$HOME/Dexter/bin"), service.ID)
-		data.ExecStart = binaryPath
-	}
-
-	// Special environment variables?
-	// data.Environment = "VAR=value"
-
-	tmpl := `[Unit]
-Description={{.Description}}
-After=network.target
-
-[Service]
-Type=simple
-ExecStart={{.ExecStart}}
-WorkingDirectory={{.WorkingDir}}
-Restart=always
-RestartSec=5
-StandardOutput=append:{{.LogPath}}
-StandardError=append:{{.LogPath}}
-{{if .Environment}}Environment={{.Environment}}{{end}}
-
-[Install]
-WantedBy=default.target
-`
-
-	file, err := os.Create(servicePath)
-	if err != nil {
-		return fmt.Errorf("failed to create service file: %w", err)
-	}
-	defer func() { _ = file.Close() }()
-
-	t := template.Must(template.New("service").Parse(tmpl))
-	if err := t.Execute(file, data); err != nil {
-		return fmt.Errorf("failed to write service template: %w", err)
+		execStart = binaryPath
 	}
-
-	// Reload systemd
-	if err := exec.Command("systemctl", "--user", "daemon-reload").Run(); err != nil {
-		return fmt.Errorf("failed to reload systemd daemon: %w", err)
-	}
-
-	// Enable service
-	if err := exec.Command("systemctl", "--user", "enable", service.SystemdName).Run(); err != nil {
-		return fmt.Errorf("failed to enable service: %w", err)
-	}
-
-	// Restart service
-	if err := exec.Command("systemctl", "--user", "restart", service.SystemdName).Run(); err != nil {
-		return fmt.Errorf("failed to restart service: %w", err)
-	}
-
-	return nil
+	return execStart, workingDir
 }
 
 // RegisterQueuedProcess registers a process in the queue in Redis.
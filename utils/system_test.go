@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/EasterCompany/dex-cli/config"
+)
+
+func TestRenderSystemdDropIn(t *testing.T) {
+	zero := 0
+	service := config.ServiceDefinition{ShortName: "tts", Unit: &config.UnitSettings{
+		Environment: map[string]string{
+			"GREETING": "say \"hi\" 100%",
+			"INJECT":   "x\nExecStartPre=/bin/sh -c evil",
+		},
+		RestartSec:            &zero,
+		StartLimitIntervalSec: &zero,
+	}}
+
+	want := strings.Join([]string{
+		"# Generated by dex from the 'unit' settings in service-map.json",
+		"[Unit]",
+		"StartLimitIntervalSec=0",
+		"",
+		"[Service]",
+		`Environment="GREETING=say \"hi\" 100%%"`,
+		`Environment="INJECT=x\nExecStartPre=/bin/sh -c evil"`,
+		"RestartSec=0",
+		"",
+	}, "\n")
+	if got := RenderSystemdDropIn(service); got != want {
+		t.Errorf("RenderSystemdDropIn() =\n%s\nwant\n%s", got, want)
+	}
+}
+
+func TestSystemdQuote(t *testing.T) {
+	tests := map[string]string{
+		"plain":      `"plain"`,
+		`back\slash`: `"back\\slash"`,
+		"tab\there":  `"tab\there"`,
+		"cr\rlf\n":   `"cr\rlf\n"`,
+		"bell\a":     `"bell\x07"`,
+		"nel\u0085":  `"nel\xc2\x85"`,
+	}
+	for value, want := range tests {
+		if got := systemdQuote(value); got != want {
+			t.Errorf("systemdQuote(%q) = %s, want %s", value, got, want)
+		}
+	}
+}
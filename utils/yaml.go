@@ -0,0 +1,157 @@
+package utils
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// yamlNode is an order-preserving tree decoded from JSON.
+type yamlNode struct {
+	kind   byte // 'o' object, 'a' array, 's' scalar
+	keys   []string
+	values []*yamlNode
+	scalar string
+}
+
+// plainScalar matches strings that can be written in YAML without quotes.
+var plainScalar = regexp.MustCompile(`^[A-Za-z0-9_./][A-Za-z0-9_ ./%@+()-]*$`)
+
+// MarshalYAML encodes a value as YAML by way of its JSON representation,
+// so `json` struct tags and field order are respected.
+func MarshalYAML(v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	root, err := decodeYAMLNode(dec)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert to YAML: %w", err)
+	}
+
+	var out strings.Builder
+	writeYAMLNode(&out, root, 0)
+	return []byte(out.String()), nil
+}
+
+func decodeYAMLNode(dec *json.Decoder) (*yamlNode, error) {
+	tok, err := dec.Token()
+	if err != nil {
+		return nil, err
+	}
+
+	switch t := tok.(type) {
+	case json.Delim:
+		switch t {
+		case '{':
+			node := &yamlNode{kind: 'o'}
+			for dec.More() {
+				keyTok, err := dec.Token()
+				if err != nil {
+					return nil, err
+				}
+				value, err := decodeYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.keys = append(node.keys, fmt.Sprint(keyTok))
+				node.values = append(node.values, value)
+			}
+			_, err := dec.Token() // closing brace
+			return node, err
+		case '[':
+			node := &yamlNode{kind: 'a'}
+			for dec.More() {
+				value, err := decodeYAMLNode(dec)
+				if err != nil {
+					return nil, err
+				}
+				node.values = append(node.values, value)
+			}
+			_, err := dec.Token() // closing bracket
+			return node, err
+		}
+		return nil, fmt.Errorf("unexpected delimiter %v", t)
+	case nil:
+		return &yamlNode{kind: 's', scalar: "null"}, nil
+	case bool:
+		return &yamlNode{kind: 's', scalar: strconv.FormatBool(t)}, nil
+	case json.Number:
+		return &yamlNode{kind: 's', scalar: t.String()}, nil
+	case string:
+		return &yamlNode{kind: 's', scalar: quoteYAMLString(t)}, nil
+	}
+	return nil, fmt.Errorf("unexpected token %v", tok)
+}
+
+// quoteYAMLString quotes a string unless it is unambiguous as a plain scalar.
+func quoteYAMLString(s string) string {
+	if plainScalar.MatchString(s) && !strings.HasSuffix(s, " ") {
+		switch strings.ToLower(s) {
+		case "true", "false", "yes", "no", "on", "off", "null", "~":
+			return strconv.Quote(s)
+		}
+		if _, err := strconv.ParseFloat(s, 64); err != nil {
+			return s
+		}
+	}
+	return strconv.Quote(s)
+}
+
+// inlineYAML returns the single-line form of a node, or "" if it needs a block.
+func inlineYAML(node *yamlNode) string {
+	switch {
+	case node.kind == 's':
+		return node.scalar
+	case node.kind == 'o' && len(node.keys) == 0:
+		return "{}"
+	case node.kind == 'a' && len(node.values) == 0:
+		return "[]"
+	}
+	return ""
+}
+
+func writeYAMLNode(out *strings.Builder, node *yamlNode, indent int) {
+	pad := strings.Repeat(" ", indent)
+
+	if inline := inlineYAML(node); inline != "" {
+		out.WriteString(pad + inline + "\n")
+		return
+	}
+
+	switch node.kind {
+	case 'o':
+		for i, key := range node.keys {
+			value := node.values[i]
+			if inline := inlineYAML(value); inline != "" {
+				fmt.Fprintf(out, "%s%s: %s\n", pad, quoteYAMLString(key), inline)
+				continue
+			}
+			fmt.Fprintf(out, "%s%s:\n", pad, quoteYAMLString(key))
+			childIndent := indent + 2
+			if value.kind == 'a' {
+				childIndent = indent
+			}
+			writeYAMLNode(out, value, childIndent)
+		}
+	case 'a':
+		for _, value := range node.values {
+			if inline := inlineYAML(value); inline != "" {
+				out.WriteString(pad + "- " + inline + "\n")
+				continue
+			}
+			// Render the child block two spaces deeper, then swap its leading
+			// padding on the first line for the list marker
+			var child strings.Builder
+			writeYAMLNode(&child, value, indent+2)
+			block := child.String()
+			out.WriteString(pad + "- " + strings.TrimPrefix(block, pad+"  "))
+		}
+	}
+}